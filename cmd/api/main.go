@@ -1,21 +1,25 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
-	_ "github.com/ariam/my-api/docs"
 	"github.com/ariam/my-api/internal/config"
 	"github.com/ariam/my-api/internal/middleware"
 	"github.com/ariam/my-api/internal/router"
 	"github.com/ariam/my-api/pkg/jwt"
 	"github.com/ariam/my-api/pkg/logger"
 	"github.com/ariam/my-api/pkg/response"
+	"github.com/ariam/my-api/pkg/tracing"
 	"github.com/ariam/my-api/pkg/validator"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/swagger"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
@@ -39,32 +43,102 @@ import (
 // @description Enter token with Bearer prefix: "Bearer <token>"
 
 func main() {
+	seedAdmin := flag.Bool("seed-admin", false, "create an admin from ADMIN_EMAIL and ADMIN_PASSWORD if none exists")
+	flag.Parse()
+
 	cfg := config.Load()
 
-	logger.Init(cfg.App.Env)
+	logger.Init(cfg.App.Env, cfg.Log.LoggerOptions()...)
 	defer logger.Sync()
 
 	validator.Init()
 
+	if err := cfg.Validate(); err != nil {
+		if cfg.App.Env != "development" {
+			logger.Fatal("Invalid configuration", zap.Error(err))
+		}
+		logger.Warn("Insecure configuration; startup would fail outside development", zap.Error(err))
+	}
+	if err := cfg.CORS.Validate(cfg.App.Env); err != nil {
+		logger.Fatal("Invalid CORS configuration", zap.Error(err))
+	}
+	if err := cfg.DB.Validate(); err != nil {
+		logger.Fatal("Invalid database configuration", zap.Error(err))
+	}
+
+	if cfg.Tracing.Enabled {
+		shutdownTracing, err := tracing.Init(context.Background(), cfg.App.Name)
+		if err != nil {
+			logger.Fatal("Tracing setup failed", zap.Error(err))
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				logger.Error("Tracing shutdown error", zap.Error(err))
+			}
+		}()
+	}
+
 	db, err := config.NewDatabase(&cfg.DB, cfg.App.Env)
 	if err != nil {
 		logger.Fatal("Database connection failed", zap.Error(err))
 	}
 	defer config.CloseDatabase(db)
 
-	if err := config.RunMigration(db); err != nil {
+	if cfg.Tracing.Enabled {
+		if err := config.UseTracing(db, &cfg.DB); err != nil {
+			logger.Fatal("Database tracing setup failed", zap.Error(err))
+		}
+	}
+
+	var seeds []config.SeedFunc
+	if *seedAdmin {
+		seeds = append(seeds, adminSeed(&cfg.Admin))
+	}
+	migrationCtx, cancelMigration := context.WithTimeout(context.Background(), time.Duration(cfg.DB.BootstrapTimeout)*time.Second)
+	err = config.RunMigration(migrationCtx, db, cfg.App.Env, seeds...)
+	cancelMigration()
+	if err != nil {
 		logger.Fatal("Migration failed", zap.Error(err))
 	}
+	if err := config.UseQueryTimeout(db, &cfg.DB); err != nil {
+		logger.Fatal("Database setup failed", zap.Error(err))
+	}
 
-	jwtManager := jwt.NewJWTManager(cfg.JWT.Secret, cfg.JWT.ExpireHours)
+	var rdb *redis.Client
+	if cfg.Redis.URL != "" {
+		rdb, err = config.NewRedis(&cfg.Redis)
+		if err != nil {
+			logger.Fatal("Redis connection failed", zap.Error(err))
+		}
+		defer rdb.Close()
+	}
 
-	app := fiber.New(fiber.Config{
-		AppName:      cfg.App.Name,
-		ErrorHandler: customErrorHandler,
-	})
+	jwtManager := jwt.NewJWTManager(cfg.JWT.Secret, cfg.JWT.ExpireHours, jwt.WithLeeway(time.Duration(cfg.JWT.LeewaySeconds)*time.Second))
 
-	middleware.SetupSecurity(app, cfg.App.Env)
+	fiberConfig := fiber.Config{
+		AppName:           cfg.App.Name,
+		ErrorHandler:      middleware.ErrorHandler(cfg.App.Env),
+		StreamRequestBody: true,
+		BodyLimit:         cfg.App.MaxBodyBytes,
+	}
+	cfg.Proxy.Apply(&fiberConfig)
+	app := fiber.New(fiberConfig)
+
+	middleware.SetupSecurity(app, cfg, middleware.LimiterStorage(rdb, "global"))
+	if cfg.Compression.Enabled {
+		app.Use(middleware.Compression(compress.Level(cfg.Compression.Level), cfg.Compression.MinBytes))
+	}
+	if cfg.Tracing.Enabled {
+		app.Use(middleware.Tracing())
+	}
+	// bulk import lasts as long as its body streams in and is bounded by item count
+	app.Use(middleware.Timeout(time.Duration(cfg.App.RequestTimeout)*time.Second, "/api/v1/users/bulk"))
 	app.Use(middleware.RequestLogger())
+	if cfg.App.LogBodies {
+		app.Use(middleware.BodyLogger(cfg.App.LogBodyMaxBytes))
+	}
 
 	app.Get("/health", func(c *fiber.Ctx) error {
 		sqlDB, _ := db.DB()
@@ -79,10 +153,12 @@ func main() {
 			"database": dbStatus,
 		})
 	})
+	router.SetupHealth(app, db, rdb)
 
-	app.Get("/swagger/*", swagger.HandlerDefault)
+	router.SetupSwagger(app, cfg)
 
-	router.Setup(app, db, jwtManager)
+	router.Setup(app, cfg, db, rdb, jwtManager)
+	app.Use(middleware.NotFound())
 
 	go func() {
 		if err := app.Listen(":" + cfg.App.Port); err != nil {
@@ -100,23 +176,4 @@ func main() {
 	if err := app.Shutdown(); err != nil {
 		logger.Error("Server shutdown error", zap.Error(err))
 	}
-}
-
-func customErrorHandler(c *fiber.Ctx, err error) error {
-	code := fiber.StatusInternalServerError
-
-	if e, ok := err.(*fiber.Error); ok {
-		code = e.Code
-	}
-
-	logger.Error("Unhandled error",
-		zap.Error(err),
-		zap.String("path", c.Path()),
-		zap.String("method", c.Method()),
-	)
-
-	return c.Status(code).JSON(fiber.Map{
-		"success": false,
-		"error":   err.Error(),
-	})
 }
\ No newline at end of file
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ariam/my-api/internal/config"
+	"github.com/ariam/my-api/internal/repository"
+	"github.com/ariam/my-api/internal/service"
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/ariam/my-api/pkg/validator"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// adminSeed creates the first admin from cfg while the bootstrap lock is held,
+// so replicas started together with --seed-admin still create only one. The
+// password is held to the same policy as any other account.
+func adminSeed(cfg *config.AdminConfig) config.SeedFunc {
+	return func(ctx context.Context, db *gorm.DB) error {
+		input := &service.CreateUserInput{Name: cfg.Name, Email: cfg.Email, Password: cfg.Password}
+		if errs := validator.Validate(input); len(errs) > 0 {
+			return fmt.Errorf("invalid admin %s: %s", errs[0].Field, errs[0].Message)
+		}
+
+		users := service.NewUserService(repository.NewUserRepository(db),
+			service.WithAuditLog(repository.NewAuditRepository(db)),
+		)
+		created, err := users.EnsureAdmin(ctx, input)
+		if err != nil {
+			return err
+		}
+		if created {
+			logger.Info("Created admin user", zap.String("email", cfg.Email))
+		}
+		return nil
+	}
+}
\ No newline at end of file
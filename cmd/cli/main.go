@@ -0,0 +1,59 @@
+// Command cli manages users directly against the database the API is
+// configured for, for scripted onboarding and debugging:
+//
+//	cli user create --email EMAIL --name NAME --password PASSWORD [--role ROLE]
+//	cli user list [--page N] [--per-page N]
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ariam/my-api/internal/config"
+	"github.com/ariam/my-api/internal/service"
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/ariam/my-api/pkg/validator"
+	"go.uber.org/zap"
+)
+
+func main() {
+	cfg := config.Load()
+
+	logger.Init(cfg.App.Env, cfg.Log.LoggerOptions()...)
+	defer logger.Sync()
+
+	validator.Init()
+
+	if err := cfg.DB.Validate(); err != nil {
+		logger.Fatal("Invalid database configuration", zap.Error(err))
+	}
+
+	db, err := config.NewDatabase(&cfg.DB, cfg.App.Env)
+	if err != nil {
+		logger.Fatal("Database connection failed", zap.Error(err))
+	}
+	defer config.CloseDatabase(db)
+
+	migrationCtx, cancelMigration := context.WithTimeout(context.Background(), time.Duration(cfg.DB.BootstrapTimeout)*time.Second)
+	err = config.RunMigration(migrationCtx, db, cfg.App.Env)
+	cancelMigration()
+	if err != nil {
+		logger.Fatal("Migration failed", zap.Error(err))
+	}
+	if err := config.UseQueryTimeout(db, &cfg.DB); err != nil {
+		logger.Fatal("Database setup failed", zap.Error(err))
+	}
+
+	limits := service.PageLimits{DefaultPerPage: cfg.Pagination.DefaultPerPage, MaxPerPage: cfg.Pagination.MaxPerPage}
+	if err := run(context.Background(), db, limits, os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
\ No newline at end of file
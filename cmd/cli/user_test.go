@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ariam/my-api/internal/config"
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/internal/service"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	require.NoError(t, config.RunMigration(context.Background(), db, "test"))
+	return db
+}
+
+func TestRun_UserCreate(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	err := run(ctx, db, service.PageLimits{}, []string{"user", "create", "--email", "ada@example.com", "--name", "Ada Lovelace", "--password", "Password123!", "--role", "admin"}, &out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "Created admin user ada@example.com")
+
+	var user model.User
+	require.NoError(t, db.Where("email = ?", "ada@example.com").First(&user).Error)
+	assert.Equal(t, "admin", user.Role)
+	assert.True(t, user.IsActive)
+	assert.NotEqual(t, "Password123!", user.Password, "password is stored hashed")
+
+	var audits int64
+	require.NoError(t, db.Model(&model.AuditLog{}).Where("target_id = ?", user.ID.String()).Count(&audits).Error)
+	assert.Positive(t, audits)
+
+	out.Reset()
+	require.NoError(t, run(ctx, db, service.PageLimits{}, []string{"user", "list"}, &out))
+	assert.Contains(t, out.String(), "ada@example.com")
+	assert.Contains(t, out.String(), "1 of 1 users")
+}
+
+func TestRun_UserCreate_Validation(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "invalid email", args: []string{"--email", "not-an-email", "--name", "Ada", "--password", "Password123!"}, wantErr: "email must be a valid email"},
+		{name: "weak password", args: []string{"--email", "ada@example.com", "--name", "Ada", "--password", "short"}, wantErr: "password must"},
+		{name: "missing name", args: []string{"--email", "ada@example.com", "--password", "Password123!"}, wantErr: "name is required"},
+		{name: "unknown role", args: []string{"--email", "ada@example.com", "--name", "Ada", "--password", "Password123!", "--role", "root"}, wantErr: "role must be one of user, admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := run(ctx, db, service.PageLimits{}, append([]string{"user", "create"}, tt.args...), &out)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	var count int64
+	require.NoError(t, db.Model(&model.User{}).Count(&count).Error)
+	assert.Zero(t, count)
+
+	var out bytes.Buffer
+	require.NoError(t, run(ctx, db, service.PageLimits{}, []string{"user", "create", "--email", "ada@example.com", "--name", "Ada", "--password", "Password123!"}, &out))
+	assert.ErrorContains(t, run(ctx, db, service.PageLimits{}, []string{"user", "create", "--email", "ada@example.com", "--name", "Ada", "--password", "Password123!"}, &out), "email already exists")
+}
+
+func TestRun_Usage(t *testing.T) {
+	db := setupTestDB(t)
+
+	for _, args := range [][]string{nil, {"user"}, {"user", "delete"}, {"group", "list"}, {"user", "list", "--bogus"}} {
+		assert.ErrorIs(t, run(context.Background(), db, service.PageLimits{}, args, &bytes.Buffer{}), errUsage, args)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,93 @@
+package authz
+
+import "sort"
+
+type Permission string
+
+const (
+	UsersRead     Permission = "users:read"
+	UsersCreate   Permission = "users:create"
+	UsersUpdate   Permission = "users:update"
+	UsersDelete   Permission = "users:delete"
+	UsersImport   Permission = "users:import"
+	UsersApprove  Permission = "users:approve"
+	UsersSetRole  Permission = "users:set_role"
+	UsersExport   Permission = "users:export"
+	AuditRead     Permission = "audit:read"
+	ProfileExport Permission = "profile:export"
+)
+
+// rolePermissions mirrors the route guards in the router. A role is also
+// granted the permissions of every role it implies in Hierarchy.
+var rolePermissions = map[string][]Permission{
+	"user": {
+		UsersRead,
+		UsersUpdate,
+		ProfileExport,
+	},
+	"admin": {
+		UsersCreate,
+		UsersDelete,
+		UsersImport,
+		UsersApprove,
+		UsersSetRole,
+		UsersExport,
+		AuditRead,
+	},
+}
+
+// RoleHierarchy maps a role to the roles it implies.
+type RoleHierarchy map[string][]string
+
+// Hierarchy is consulted by every role and permission check. Replace it at
+// startup to change which roles imply which.
+var Hierarchy = RoleHierarchy{
+	"admin": {"user"},
+}
+
+// Roles returns role followed by every role it implies, directly or
+// transitively.
+func (h RoleHierarchy) Roles(role string) []string {
+	roles := []string{role}
+	seen := map[string]bool{role: true}
+	for i := 0; i < len(roles); i++ {
+		for _, implied := range h[roles[i]] {
+			if !seen[implied] {
+				seen[implied] = true
+				roles = append(roles, implied)
+			}
+		}
+	}
+	return roles
+}
+
+// HasRole reports whether role is required or implies it.
+func HasRole(role, required string) bool {
+	for _, r := range Hierarchy.Roles(role) {
+		if r == required {
+			return true
+		}
+	}
+	return false
+}
+
+// Can reports whether role is granted perm.
+func Can(role string, perm Permission) bool {
+	for _, p := range PermissionsFor(role) {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionsFor returns the sorted permission set granted to role, including
+// the permissions of the roles it implies. Unknown roles get no permissions.
+func PermissionsFor(role string) []Permission {
+	var perms []Permission
+	for _, r := range Hierarchy.Roles(role) {
+		perms = append(perms, rolePermissions[r]...)
+	}
+	sort.Slice(perms, func(i, j int) bool { return perms[i] < perms[j] })
+	return perms
+}
\ No newline at end of file
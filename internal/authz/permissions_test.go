@@ -0,0 +1,30 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleHierarchy_Roles(t *testing.T) {
+	h := RoleHierarchy{
+		"owner": {"admin"},
+		"admin": {"user"},
+		"user":  {"admin"},
+	}
+
+	assert.Equal(t, []string{"owner", "admin", "user"}, h.Roles("owner"), "implied roles are transitive")
+	assert.Equal(t, []string{"user", "admin"}, h.Roles("user"), "cycles terminate")
+	assert.Equal(t, []string{"guest"}, h.Roles("guest"))
+}
+
+func TestPermissionsFor_IncludesImpliedRoles(t *testing.T) {
+	admin := PermissionsFor("admin")
+
+	for _, perm := range PermissionsFor("user") {
+		assert.Contains(t, admin, perm)
+	}
+	assert.Contains(t, admin, AuditRead)
+	assert.NotContains(t, PermissionsFor("user"), AuditRead)
+	assert.Empty(t, PermissionsFor("guest"))
+}
\ No newline at end of file
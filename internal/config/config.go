@@ -1,75 +1,514 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"net"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/ariam/my-api/pkg/mailer"
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v2"
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	App AppConfig
-	DB  DBConfig
-	JWT JWTConfig
+	App           AppConfig           `yaml:"app"`
+	DB            DBConfig            `yaml:"db"`
+	JWT           JWTConfig           `yaml:"jwt"`
+	Session       SessionConfig       `yaml:"session"`
+	Bulk          BulkConfig          `yaml:"bulk"`
+	CORS          CORSConfig          `yaml:"cors"`
+	RateLimit     RateLimitConfig     `yaml:"rate_limit"`
+	Signup        SignupConfig        `yaml:"signup"`
+	PasswordReset PasswordResetConfig `yaml:"password_reset"`
+	Mail          MailConfig          `yaml:"mail"`
+	Tracing       TracingConfig       `yaml:"tracing"`
+	Redis         RedisConfig         `yaml:"redis"`
+	Cache         CacheConfig         `yaml:"cache"`
+	Admin         AdminConfig         `yaml:"admin"`
+	Log           LogConfig           `yaml:"log"`
+	Compression   CompressionConfig   `yaml:"compression"`
+	Pagination    PaginationConfig    `yaml:"pagination"`
+	Maintenance   MaintenanceConfig   `yaml:"maintenance"`
+	Proxy         ProxyConfig         `yaml:"proxy"`
 }
 
 type AppConfig struct {
-	Env  string
-	Port string
-	Name string
+	Env              string   `yaml:"env" env:"APP_ENV"`
+	Port             string   `yaml:"port" env:"APP_PORT"`
+	Name             string   `yaml:"name" env:"APP_NAME"`
+	PublicUserFields []string `yaml:"public_user_fields" env:"PUBLIC_USER_FIELDS"`
+	LogBodies        bool     `yaml:"log_bodies" env:"LOG_BODIES"`
+	LogBodyMaxBytes  int      `yaml:"log_body_max_bytes" env:"LOG_BODY_MAX_BYTES"`
+	AllowedMethods   []string `yaml:"allowed_methods" env:"ALLOWED_HTTP_METHODS"`
+	BaseURL          string   `yaml:"base_url" env:"APP_BASE_URL"`
+	RequestTimeout   int      `yaml:"request_timeout" env:"REQUEST_TIMEOUT_SECONDS"` // seconds; 0 disables the limit
+	MaxBodyBytes     int      `yaml:"max_body_bytes" env:"MAX_BODY_BYTES"`
+	Swagger          *bool    `yaml:"swagger" env:"SWAGGER_ENABLED"` // nil uses SwaggerEnabled's default
+}
+
+// SwaggerEnabled reports whether the Swagger UI is served. Unless set
+// explicitly it is off in production, where it would expose API internals.
+func (c AppConfig) SwaggerEnabled() bool {
+	if c.Swagger != nil {
+		return *c.Swagger
+	}
+	return c.Env != "production"
 }
 
 type DBConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Name     string
+	Driver           string `yaml:"driver" env:"DB_DRIVER"` // postgres, mysql or sqlite
+	Host             string `yaml:"host" env:"DB_HOST"`
+	Port             string `yaml:"port" env:"DB_PORT"`
+	User             string `yaml:"user" env:"DB_USER"`
+	Password         string `yaml:"password" env:"DB_PASSWORD"`
+	Name             string `yaml:"name" env:"DB_NAME"`
+	TablePrefix      string `yaml:"table_prefix" env:"DB_TABLE_PREFIX"`
+	BootstrapTimeout int    `yaml:"bootstrap_timeout" env:"DB_BOOTSTRAP_TIMEOUT"` // seconds to wait for migrations, including another instance's
+	SSLMode          string `yaml:"sslmode" env:"DB_SSLMODE"`
+	SSLRootCert      string `yaml:"sslrootcert" env:"DB_SSLROOTCERT"`
+
+	MaxIdleConns           int `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	MaxOpenConns           int `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	ConnMaxLifetimeMinutes int `yaml:"conn_max_lifetime_minutes" env:"DB_CONN_MAX_LIFETIME_MINUTES"`
+	ConnMaxIdleTimeMinutes int `yaml:"conn_max_idle_time_minutes" env:"DB_CONN_MAX_IDLE_TIME_MINUTES"` // 0 keeps idle connections until ConnMaxLifetime
+
+	ConnectAttempts     int `yaml:"connect_attempts" env:"DB_CONNECT_ATTEMPTS"`
+	ConnectRetryDelayMs int `yaml:"connect_retry_delay_ms" env:"DB_CONNECT_RETRY_DELAY_MS"` // doubled after each failed attempt
+
+	QueryTimeoutMs int `yaml:"query_timeout_ms" env:"DB_QUERY_TIMEOUT_MS"` // per statement after migrations; 0 disables
+	SlowQueryMs    int `yaml:"slow_query_ms" env:"DB_SLOW_QUERY_MS"`       // statements slower than this are logged as warnings; 0 disables
+}
+
+var sslModes = map[string]bool{
+	"disable":     true,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+// Validate rejects unknown drivers and SSL modes, and verify modes without a
+// root certificate to verify the server against.
+func (c DBConfig) Validate() error {
+	switch c.Driver {
+	case "postgres", "mysql", "sqlite":
+	default:
+		return fmt.Errorf("DB_DRIVER %q is not one of postgres, mysql, sqlite", c.Driver)
+	}
+	if !sslModes[c.SSLMode] {
+		return fmt.Errorf("DB_SSLMODE %q is not one of disable, require, verify-ca, verify-full", c.SSLMode)
+	}
+	if strings.HasPrefix(c.SSLMode, "verify-") && c.SSLRootCert == "" {
+		return fmt.Errorf("DB_SSLMODE %s requires DB_SSLROOTCERT", c.SSLMode)
+	}
+	return nil
+}
+
+// DSN builds the connection string for Driver. For sqlite, Name is the
+// database file path or ":memory:". SSLMode and SSLRootCert apply to
+// postgres only.
+func (c DBConfig) DSN() string {
+	switch c.Driver {
+	case "sqlite":
+		return c.Name
+	case "mysql":
+		// FormatDSN escapes what a hand-built string wouldn't, such as an @ or /
+		// in the password
+		mc := mysql.NewConfig()
+		mc.User = c.User
+		mc.Passwd = c.Password
+		mc.Net = "tcp"
+		mc.Addr = net.JoinHostPort(c.Host, c.Port)
+		mc.DBName = c.Name
+		mc.Params = map[string]string{"charset": "utf8mb4"}
+		mc.ParseTime = true
+		mc.Loc = time.UTC
+		return mc.FormatDSN()
+	}
+
+	dsn := fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC",
+		c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode,
+	)
+	if c.SSLRootCert != "" {
+		dsn += " sslrootcert=" + c.SSLRootCert
+	}
+	return dsn
 }
 
+// JWTConfig token lifetimes are in hours. RememberMeExpireHours applies to
+// logins that ask to be remembered; zero gives them the default lifetime.
+// LeewaySeconds is the clock drift between services tolerated when checking
+// a token's exp, nbf and iat.
 type JWTConfig struct {
-	Secret      string
-	ExpireHours int
+	Secret                string `yaml:"secret" env:"JWT_SECRET"`
+	ExpireHours           int    `yaml:"expire_hours" env:"JWT_EXPIRE_HOURS"`
+	RememberMeExpireHours int    `yaml:"remember_me_expire_hours" env:"REMEMBER_ME_EXPIRE_HOURS"`
+	LeewaySeconds         int    `yaml:"leeway_seconds" env:"JWT_LEEWAY_SECONDS"`
+}
+
+type SessionConfig struct {
+	MaxActive   int            `yaml:"max_active" env:"SESSION_MAX_ACTIVE"`
+	RoleLimits  map[string]int `yaml:"role_limits" env:"SESSION_ROLE_LIMITS"`
+	LimitPolicy string         `yaml:"limit_policy" env:"SESSION_LIMIT_POLICY"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins   []string `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+	AllowedMethods   []string `yaml:"allowed_methods" env:"CORS_ALLOWED_METHODS"`
+	AllowedHeaders   []string `yaml:"allowed_headers" env:"CORS_ALLOWED_HEADERS"`
+	AllowCredentials bool     `yaml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS"`
+	MaxAge           int      `yaml:"max_age" env:"CORS_MAX_AGE"`
+}
+
+// Validate rejects CORS settings that are unsafe for the environment: the
+// wildcard origin is only allowed in development, and never with credentials.
+func (c CORSConfig) Validate(env string) error {
+	for _, origin := range c.AllowedOrigins {
+		if origin != "*" {
+			continue
+		}
+		if c.AllowCredentials {
+			return errors.New("CORS_ALLOWED_ORIGINS cannot contain \"*\" when CORS_ALLOW_CREDENTIALS is enabled")
+		}
+		if env != "development" {
+			return errors.New("CORS_ALLOWED_ORIGINS cannot contain \"*\" outside development")
+		}
+	}
+	return nil
+}
+
+// RateLimitConfig windows are in seconds.
+type RateLimitConfig struct {
+	GlobalMax    int `yaml:"global_max" env:"RATE_LIMIT_MAX"`
+	GlobalWindow int `yaml:"global_window" env:"RATE_LIMIT_WINDOW"`
+	LoginMax     int `yaml:"login_max" env:"LOGIN_RATE_LIMIT_MAX"`
+	LoginWindow  int `yaml:"login_window" env:"LOGIN_RATE_LIMIT_WINDOW"`
 }
 
+// SignupConfig DeletedEmailPolicy is what creating a user with a
+// soft-deleted user's email does: reject it, or restore the deleted account.
+type SignupConfig struct {
+	RequireVerification  bool   `yaml:"require_verification" env:"SIGNUP_REQUIRE_VERIFICATION"`
+	VerificationTTLHours int    `yaml:"verification_ttl_hours" env:"SIGNUP_VERIFICATION_TTL_HOURS"`
+	RequireApproval      bool   `yaml:"require_approval" env:"REQUIRE_APPROVAL"`
+	DeletedEmailPolicy   string `yaml:"deleted_email_policy" env:"SIGNUP_DELETED_EMAIL_POLICY"`
+}
+
+// Validate rejects unknown deleted-email policies.
+func (c SignupConfig) Validate() error {
+	switch c.DeletedEmailPolicy {
+	case "reject", "restore":
+		return nil
+	}
+	return fmt.Errorf("SIGNUP_DELETED_EMAIL_POLICY %q is not one of reject, restore", c.DeletedEmailPolicy)
+}
+
+type PasswordResetConfig struct {
+	URL        string `yaml:"url" env:"PASSWORD_RESET_URL"`
+	TTLMinutes int    `yaml:"ttl_minutes" env:"PASSWORD_RESET_TTL_MINUTES"`
+}
+
+// MailConfig selects how verification, password reset and notice emails are
+// delivered. Without an SMTPHost nothing is sent.
+type MailConfig struct {
+	SMTPHost     string `yaml:"smtp_host" env:"SMTP_HOST"`
+	SMTPPort     string `yaml:"smtp_port" env:"SMTP_PORT"`
+	SMTPUsername string `yaml:"smtp_username" env:"SMTP_USERNAME"`
+	SMTPPassword string `yaml:"smtp_password" env:"SMTP_PASSWORD"`
+	From         string `yaml:"from" env:"MAIL_FROM"`
+}
+
+// Mailer returns the configured mailer, or one that delivers nothing when no
+// SMTP server is set.
+func (c MailConfig) Mailer() mailer.Mailer {
+	if c.SMTPHost == "" {
+		return mailer.NewNoop()
+	}
+	return mailer.NewSMTP(c.SMTPHost, c.SMTPPort, c.SMTPUsername, c.SMTPPassword, c.From)
+}
+
+// TracingConfig enables OpenTelemetry tracing. The exporter is configured
+// with the standard OTEL_EXPORTER_OTLP_* variables.
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled" env:"OTEL_ENABLED"`
+}
+
+// RedisConfig enables features that share state between instances. An empty
+// URL keeps that state in process memory.
+type RedisConfig struct {
+	URL string `yaml:"url" env:"REDIS_URL"`
+}
+
+// CacheConfig controls the user lookup cache and the GET response cache. A
+// TTL of zero disables the cache; Size bounds each in-memory cache used when
+// Redis is not configured.
+type CacheConfig struct {
+	TTLSeconds         int `yaml:"ttl_seconds" env:"USER_CACHE_TTL_SECONDS"`
+	Size               int `yaml:"size" env:"USER_CACHE_SIZE"`
+	ResponseTTLSeconds int `yaml:"response_ttl_seconds" env:"RESPONSE_CACHE_TTL_SECONDS"`
+}
+
+// CompressionConfig controls response compression. Level is 0 for the
+// default, 1 for best speed or 2 for best compression; responses smaller than
+// MinBytes are sent uncompressed.
+type CompressionConfig struct {
+	Enabled  bool `yaml:"enabled" env:"COMPRESSION_ENABLED"`
+	Level    int  `yaml:"level" env:"COMPRESSION_LEVEL"`
+	MinBytes int  `yaml:"min_bytes" env:"COMPRESSION_MIN_BYTES"`
+}
+
+// LogConfig overrides the logger defaults for the environment. An empty
+// Level or Format, or a nil Sampling, keeps the default.
+type LogConfig struct {
+	Level    string `yaml:"level" env:"LOG_LEVEL"` // debug, info, warn or error
+	Sampling *bool  `yaml:"sampling" env:"LOG_SAMPLING"`
+	Format   string `yaml:"format" env:"LOG_FORMAT"` // json or console
+}
+
+var logLevels = map[string]bool{"": true, "debug": true, "info": true, "warn": true, "error": true}
+
+// Validate rejects log levels and formats the logger doesn't support.
+func (c LogConfig) Validate() error {
+	if !logLevels[c.Level] {
+		return fmt.Errorf("LOG_LEVEL %q is not one of debug, info, warn, error", c.Level)
+	}
+	switch c.Format {
+	case "", "json", "console":
+	default:
+		return fmt.Errorf("LOG_FORMAT %q is not one of json, console", c.Format)
+	}
+	return nil
+}
+
+// LoggerOptions returns the logger.Init options for these settings.
+func (c LogConfig) LoggerOptions() []logger.Option {
+	opts := []logger.Option{logger.WithLevel(c.Level), logger.WithEncoding(c.Format)}
+	if c.Sampling != nil {
+		opts = append(opts, logger.WithSampling(*c.Sampling))
+	}
+	return opts
+}
+
+// AdminConfig is the account created by the --seed-admin flag when the
+// database has no admin yet.
+type AdminConfig struct {
+	Email    string `yaml:"email" env:"ADMIN_EMAIL"`
+	Password string `yaml:"password" env:"ADMIN_PASSWORD"`
+	Name     string `yaml:"name" env:"ADMIN_NAME"`
+}
+
+type BulkConfig struct {
+	BatchSize int `yaml:"batch_size" env:"BULK_IMPORT_BATCH_SIZE"`
+	MaxItems  int `yaml:"max_items" env:"BULK_IMPORT_MAX_ITEMS"`
+}
+
+// PaginationConfig bounds the page size of paginated listings. A missing or
+// invalid per_page uses DefaultPerPage and a larger one is clamped to
+// MaxPerPage.
+type PaginationConfig struct {
+	DefaultPerPage int `yaml:"default_per_page" env:"DEFAULT_PER_PAGE"`
+	MaxPerPage     int `yaml:"max_per_page" env:"MAX_PER_PAGE"`
+}
+
+// Validate rejects a default page size outside 1..MaxPerPage.
+func (c PaginationConfig) Validate() error {
+	if c.MaxPerPage < 1 {
+		return fmt.Errorf("MAX_PER_PAGE %d must be at least 1", c.MaxPerPage)
+	}
+	if c.DefaultPerPage < 1 || c.DefaultPerPage > c.MaxPerPage {
+		return fmt.Errorf("DEFAULT_PER_PAGE %d must be between 1 and MAX_PER_PAGE (%d)", c.DefaultPerPage, c.MaxPerPage)
+	}
+	return nil
+}
+
+// MaintenanceConfig is the maintenance mode the API starts in: off, read_only
+// (writes get 503) or full (every request gets 503). Admins can switch it at
+// runtime. RetryAfter is in seconds.
+type MaintenanceConfig struct {
+	Mode       string `yaml:"mode" env:"MAINTENANCE_MODE"`
+	RetryAfter int    `yaml:"retry_after" env:"MAINTENANCE_RETRY_AFTER_SECONDS"`
+}
+
+// Validate rejects unknown maintenance modes.
+func (c MaintenanceConfig) Validate() error {
+	switch c.Mode {
+	case "off", "read_only", "full":
+		return nil
+	}
+	return fmt.Errorf("MAINTENANCE_MODE %q is not one of off, read_only, full", c.Mode)
+}
+
+// ProxyConfig controls where c.IP() reads the client address. Header, such as
+// X-Forwarded-For, is only believed on requests whose peer is one of
+// TrustedProxies (IPs or CIDR ranges); every other request resolves to its
+// peer address, so clients can't spoof their IP by sending the header.
+type ProxyConfig struct {
+	TrustedProxies []string `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+	Header         string   `yaml:"header" env:"PROXY_HEADER"`
+}
+
+// Validate rejects trusted proxies that aren't IPs or CIDR ranges, and a
+// header without any proxy to trust it from.
+func (c ProxyConfig) Validate() error {
+	for _, proxy := range c.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("TRUSTED_PROXIES entry %q is not an IP or CIDR range", proxy)
+			}
+		}
+	}
+	if c.Header != "" && len(c.TrustedProxies) == 0 {
+		return errors.New("PROXY_HEADER requires TRUSTED_PROXIES")
+	}
+	return nil
+}
+
+// Apply sets the proxy settings on a Fiber app config. Fiber takes the first
+// valid IP in Header, which for a list such as X-Forwarded-For is whatever
+// the client put there; middleware.ProxyClientIP must run first to narrow the
+// header to the address the outermost trusted proxy saw.
+func (c ProxyConfig) Apply(fc *fiber.Config) {
+	fc.ProxyHeader = c.Header
+	fc.EnableTrustedProxyCheck = true
+	fc.TrustedProxies = c.TrustedProxies
+	fc.EnableIPValidation = true
+}
+
+// Load builds the configuration from defaults, then the YAML or JSON file
+// named by CONFIG_FILE, then environment variables, each overriding the last.
+// It exits if the file cannot be loaded; call Validate on the result.
 func Load() *Config {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using system environment")
 	}
 
+	cfg, err := load(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	return cfg
+}
+
+// minJWTSecretBytes matches the HS256 key size; shorter secrets are
+// brute-forceable.
+const minJWTSecretBytes = 32
+
+// Validate reports every setting that would leave the app insecure or unable
+// to serve: a missing or short JWT secret, an empty database password for a
+// networked database, an invalid port, and email verification without a
+// mailer to send the links. The problems are joined into one error; main
+// refuses to start on it outside development.
+func (c *Config) Validate() error {
+	var errs []error
+	if len(c.JWT.Secret) < minJWTSecretBytes {
+		errs = append(errs, fmt.Errorf("JWT_SECRET must be at least %d bytes", minJWTSecretBytes))
+	}
+	if c.DB.Driver != "sqlite" && c.DB.Password == "" {
+		errs = append(errs, errors.New("DB_PASSWORD is required"))
+	}
+	if port, err := strconv.Atoi(c.App.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("APP_PORT %q is not a valid port", c.App.Port))
+	}
+	if c.Signup.RequireVerification && c.Mail.SMTPHost == "" {
+		// new accounts would stay inactive with no way to verify
+		errs = append(errs, errors.New("SIGNUP_REQUIRE_VERIFICATION needs SMTP_HOST to send verification emails"))
+	}
+	if c.Mail.SMTPHost != "" && c.Mail.From == "" {
+		errs = append(errs, errors.New("MAIL_FROM is required with SMTP_HOST"))
+	}
+	return errors.Join(errs...)
+}
+
+func defaults() *Config {
 	return &Config{
 		App: AppConfig{
-			Env:  getEnv("APP_ENV", "development"),
-			Port: getEnv("APP_PORT", "3000"),
-			Name: getEnv("APP_NAME", "my-api"),
+			Env:              "development",
+			Port:             "3000",
+			Name:             "my-api",
+			PublicUserFields: []string{"id", "name", "email"},
+			LogBodyMaxBytes:  2048,
+			BaseURL:          "http://localhost:3000",
+			RequestTimeout:   30,
+			MaxBodyBytes:     1 << 20,
 		},
 		DB: DBConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", ""),
-			Name:     getEnv("DB_NAME", "db"),
+			Driver:           "postgres",
+			Host:             "localhost",
+			Port:             "5432",
+			User:             "postgres",
+			Name:             "db",
+			BootstrapTimeout: 300,
+			SSLMode:          "disable",
+
+			MaxIdleConns:           10,
+			MaxOpenConns:           100,
+			ConnMaxLifetimeMinutes: 60,
+
+			ConnectAttempts:     5,
+			ConnectRetryDelayMs: 500,
+
+			SlowQueryMs: 200,
 		},
 		JWT: JWTConfig{
-			Secret:      getEnv("JWT_SECRET", ""),
-			ExpireHours: getEnvInt("JWT_EXPIRE_HOURS", 24),
+			ExpireHours:           24,
+			RememberMeExpireHours: 720,
+			LeewaySeconds:         30,
+		},
+		Session: SessionConfig{
+			RoleLimits:  map[string]int{},
+			LimitPolicy: "evict_oldest",
+		},
+		Bulk: BulkConfig{
+			BatchSize: 100,
+			MaxItems:  10000,
+		},
+		CORS: CORSConfig{
+			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
+			AllowedHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "X-Validation-Format", "Idempotency-Key"},
+			MaxAge:         300,
+		},
+		Signup: SignupConfig{
+			RequireVerification:  true,
+			VerificationTTLHours: 24,
+			DeletedEmailPolicy:   "reject",
+		},
+		PasswordReset: PasswordResetConfig{
+			URL:        "http://localhost:3000/reset-password",
+			TTLMinutes: 60,
+		},
+		Mail: MailConfig{
+			SMTPPort: "587",
+		},
+		RateLimit: RateLimitConfig{
+			GlobalMax:    100,
+			GlobalWindow: 60,
+			LoginMax:     5,
+			LoginWindow:  900,
+		},
+		Cache: CacheConfig{
+			TTLSeconds: 60,
+			Size:       10000,
+		},
+		Admin: AdminConfig{
+			Name: "Admin",
+		},
+		Compression: CompressionConfig{
+			MinBytes: 1024,
+		},
+		Pagination: PaginationConfig{
+			DefaultPerPage: 10,
+			MaxPerPage:     100,
+		},
+		Maintenance: MaintenanceConfig{
+			Mode:       "off",
+			RetryAfter: 300,
 		},
 	}
-}
-
-func getEnv(key, fallback string) string {
-	if val := os.Getenv(key); val != "" {
-		return val
-	}
-	return fallback
-}
-
-func getEnvInt(key string, fallback int) int {
-	if val := os.Getenv(key); val != "" {
-		if i, err := strconv.Atoi(val); err == nil {
-			return i
-		}
-	}
-	return fallback
 }
\ No newline at end of file
@@ -0,0 +1,430 @@
+package config
+
+import (
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORSConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     CORSConfig
+		env     string
+		wantErr bool
+	}{
+		{name: "wildcard allowed in development", cfg: CORSConfig{AllowedOrigins: []string{"*"}}, env: "development"},
+		{name: "wildcard rejected in production", cfg: CORSConfig{AllowedOrigins: []string{"*"}}, env: "production", wantErr: true},
+		{name: "wildcard rejected with credentials", cfg: CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}, env: "development", wantErr: true},
+		{name: "explicit origins with credentials", cfg: CORSConfig{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true}, env: "production"},
+		{name: "no origins", cfg: CORSConfig{}, env: "production"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate(tt.env)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDBConfig_DSN(t *testing.T) {
+	base := DBConfig{Driver: "postgres", Host: "db", Port: "5432", User: "app", Password: "secret", Name: "mydb"}
+	prefix := "host=db port=5432 user=app password=secret dbname=mydb "
+
+	tests := []struct {
+		name     string
+		mode     string
+		rootCert string
+		expected string
+	}{
+		{name: "disable", mode: "disable", expected: prefix + "sslmode=disable TimeZone=UTC"},
+		{name: "require", mode: "require", expected: prefix + "sslmode=require TimeZone=UTC"},
+		{name: "verify-ca with root cert", mode: "verify-ca", rootCert: "/etc/ssl/rds.pem", expected: prefix + "sslmode=verify-ca TimeZone=UTC sslrootcert=/etc/ssl/rds.pem"},
+		{name: "verify-full with root cert", mode: "verify-full", rootCert: "/etc/ssl/rds.pem", expected: prefix + "sslmode=verify-full TimeZone=UTC sslrootcert=/etc/ssl/rds.pem"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base
+			cfg.SSLMode = tt.mode
+			cfg.SSLRootCert = tt.rootCert
+
+			assert.Equal(t, tt.expected, cfg.DSN())
+		})
+	}
+
+	t.Run("mysql", func(t *testing.T) {
+		cfg := base
+		cfg.Driver = "mysql"
+		cfg.Port = "3306"
+
+		assert.Equal(t, "app:secret@tcp(db:3306)/mydb?parseTime=true&charset=utf8mb4", cfg.DSN())
+	})
+
+	t.Run("mysql password with DSN delimiters", func(t *testing.T) {
+		cfg := base
+		cfg.Driver = "mysql"
+		cfg.Port = "3306"
+		cfg.Password = "p@ss/word:1?x=y"
+
+		parsed, err := mysql.ParseDSN(cfg.DSN())
+		require.NoError(t, err)
+		assert.Equal(t, "app", parsed.User)
+		assert.Equal(t, "p@ss/word:1?x=y", parsed.Passwd)
+		assert.Equal(t, "db:3306", parsed.Addr)
+		assert.Equal(t, "mydb", parsed.DBName)
+		assert.True(t, parsed.ParseTime)
+		assert.Equal(t, time.UTC, parsed.Loc)
+		assert.Equal(t, "utf8mb4", parsed.Params["charset"])
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		cfg := base
+		cfg.Driver = "sqlite"
+		cfg.Name = "/var/lib/my-api/app.db"
+
+		assert.Equal(t, "/var/lib/my-api/app.db", cfg.DSN())
+	})
+}
+
+func TestDBConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     DBConfig
+		wantErr string
+	}{
+		{name: "disable", cfg: DBConfig{Driver: "postgres", SSLMode: "disable"}},
+		{name: "require without root cert", cfg: DBConfig{Driver: "postgres", SSLMode: "require"}},
+		{name: "verify-full with root cert", cfg: DBConfig{Driver: "postgres", SSLMode: "verify-full", SSLRootCert: "/etc/ssl/ca.pem"}},
+		{name: "verify-full without root cert", cfg: DBConfig{Driver: "postgres", SSLMode: "verify-full"}, wantErr: "DB_SSLMODE verify-full requires DB_SSLROOTCERT"},
+		{name: "verify-ca without root cert", cfg: DBConfig{Driver: "postgres", SSLMode: "verify-ca"}, wantErr: "DB_SSLMODE verify-ca requires DB_SSLROOTCERT"},
+		{name: "unknown mode", cfg: DBConfig{Driver: "postgres", SSLMode: "prefer"}, wantErr: `DB_SSLMODE "prefer" is not one of disable, require, verify-ca, verify-full`},
+		{name: "mysql", cfg: DBConfig{Driver: "mysql", SSLMode: "disable"}},
+		{name: "sqlite", cfg: DBConfig{Driver: "sqlite", SSLMode: "disable"}},
+		{name: "unknown driver", cfg: DBConfig{Driver: "oracle", SSLMode: "disable"}, wantErr: `DB_DRIVER "oracle" is not one of postgres, mysql, sqlite`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoad_DBPool(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		for _, key := range []string{"DB_MAX_IDLE_CONNS", "DB_MAX_OPEN_CONNS", "DB_CONN_MAX_LIFETIME_MINUTES", "DB_CONN_MAX_IDLE_TIME_MINUTES"} {
+			t.Setenv(key, "")
+		}
+
+		cfg := Load()
+
+		assert.Equal(t, 10, cfg.DB.MaxIdleConns)
+		assert.Equal(t, 100, cfg.DB.MaxOpenConns)
+		assert.Equal(t, 60, cfg.DB.ConnMaxLifetimeMinutes)
+		assert.Equal(t, 0, cfg.DB.ConnMaxIdleTimeMinutes)
+	})
+
+	t.Run("from env", func(t *testing.T) {
+		t.Setenv("DB_MAX_IDLE_CONNS", "1")
+		t.Setenv("DB_MAX_OPEN_CONNS", "5")
+		t.Setenv("DB_CONN_MAX_LIFETIME_MINUTES", "15")
+		t.Setenv("DB_CONN_MAX_IDLE_TIME_MINUTES", "2")
+
+		cfg := Load()
+
+		assert.Equal(t, 1, cfg.DB.MaxIdleConns)
+		assert.Equal(t, 5, cfg.DB.MaxOpenConns)
+		assert.Equal(t, 15, cfg.DB.ConnMaxLifetimeMinutes)
+		assert.Equal(t, 2, cfg.DB.ConnMaxIdleTimeMinutes)
+	})
+}
+
+func TestLoad_InvalidEnvValues(t *testing.T) {
+	t.Setenv("DB_MAX_OPEN_CONNS", "abc")
+	t.Setenv("RATE_LIMIT_MAX", "10x")
+	t.Setenv("OTEL_ENABLED", "maybe")
+	t.Setenv("SESSION_ROLE_LIMITS", "admin:5,user")
+
+	_, err := load("")
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `DB_MAX_OPEN_CONNS: "abc" is not an integer`)
+	assert.ErrorContains(t, err, `RATE_LIMIT_MAX: "10x" is not an integer`)
+	assert.ErrorContains(t, err, `OTEL_ENABLED: "maybe" is not a boolean`)
+	assert.ErrorContains(t, err, `SESSION_ROLE_LIMITS: "user" is not a key:integer pair`)
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_ConfigFile(t *testing.T) {
+	for _, key := range []string{"APP_ENV", "APP_PORT", "APP_NAME", "DB_HOST", "DB_MAX_OPEN_CONNS", "SESSION_ROLE_LIMITS", "CORS_ALLOWED_ORIGINS", "JWT_SECRET"} {
+		t.Setenv(key, "")
+	}
+
+	yamlPath := writeConfigFile(t, "config.yaml", `
+app:
+  port: "8080"
+  name: from-file
+db:
+  host: db.internal
+  max_open_conns: 20
+session:
+  role_limits:
+    admin: 2
+cors:
+  allowed_origins: [https://app.example.com]
+`)
+
+	t.Run("file overrides defaults", func(t *testing.T) {
+		cfg, err := load(yamlPath)
+		require.NoError(t, err)
+
+		assert.Equal(t, "8080", cfg.App.Port)
+		assert.Equal(t, "from-file", cfg.App.Name)
+		assert.Equal(t, "db.internal", cfg.DB.Host)
+		assert.Equal(t, 20, cfg.DB.MaxOpenConns)
+		assert.Equal(t, map[string]int{"admin": 2}, cfg.Session.RoleLimits)
+		assert.Equal(t, []string{"https://app.example.com"}, cfg.CORS.AllowedOrigins)
+		assert.Equal(t, 10, cfg.DB.MaxIdleConns, "unset keys keep their defaults")
+	})
+
+	t.Run("env overrides file", func(t *testing.T) {
+		t.Setenv("APP_PORT", "9090")
+		t.Setenv("DB_MAX_OPEN_CONNS", "50")
+		t.Setenv("SESSION_ROLE_LIMITS", "user:1")
+
+		cfg, err := load(yamlPath)
+		require.NoError(t, err)
+
+		assert.Equal(t, "9090", cfg.App.Port)
+		assert.Equal(t, 50, cfg.DB.MaxOpenConns)
+		assert.Equal(t, map[string]int{"user": 1}, cfg.Session.RoleLimits)
+		assert.Equal(t, "from-file", cfg.App.Name, "file values without an env override are kept")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		path := writeConfigFile(t, "config.json", `{
+	"app": {"port": "7070"},
+	"db": {"max_open_conns": 30}
+}`)
+
+		cfg, err := load(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, "7070", cfg.App.Port)
+		assert.Equal(t, 30, cfg.DB.MaxOpenConns)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := load(writeConfigFile(t, "config.yaml", "db:\n  hots: db.internal\n"))
+		assert.ErrorContains(t, err, "hots")
+	})
+
+	t.Run("unsupported extension", func(t *testing.T) {
+		_, err := load(writeConfigFile(t, "config.toml", ""))
+		assert.ErrorContains(t, err, "unsupported extension")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := load(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.Error(t, err)
+	})
+
+	t.Run("validated after merge", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", "jwt:\n  secret: too-short\n")
+
+		cfg, err := load(path)
+		require.NoError(t, err)
+		assert.ErrorContains(t, cfg.Validate(), "JWT_SECRET")
+
+		t.Setenv("JWT_SECRET", strings.Repeat("s", 32))
+		cfg, err = load(path)
+		require.NoError(t, err)
+		assert.NotContains(t, cfg.Validate().Error(), "JWT_SECRET")
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() *Config {
+		cfg := defaults()
+		cfg.JWT.Secret = strings.Repeat("s", 32)
+		cfg.DB.Password = "secret"
+		cfg.Mail.SMTPHost = "smtp.example.com"
+		cfg.Mail.From = "noreply@example.com"
+		return cfg
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{name: "valid", modify: func(*Config) {}},
+		{name: "missing JWT secret", modify: func(c *Config) { c.JWT.Secret = "" }, wantErr: "JWT_SECRET must be at least 32 bytes"},
+		{name: "short JWT secret", modify: func(c *Config) { c.JWT.Secret = strings.Repeat("s", 31) }, wantErr: "JWT_SECRET must be at least 32 bytes"},
+		{name: "missing DB password", modify: func(c *Config) { c.DB.Password = "" }, wantErr: "DB_PASSWORD is required"},
+		{name: "sqlite needs no DB password", modify: func(c *Config) { c.DB.Driver = "sqlite"; c.DB.Password = "" }},
+		{name: "non-numeric port", modify: func(c *Config) { c.App.Port = "http" }, wantErr: `APP_PORT "http" is not a valid port`},
+		{name: "port out of range", modify: func(c *Config) { c.App.Port = "70000" }, wantErr: `APP_PORT "70000" is not a valid port`},
+		{name: "port zero", modify: func(c *Config) { c.App.Port = "0" }, wantErr: `APP_PORT "0" is not a valid port`},
+		{name: "verification without a mailer", modify: func(c *Config) { c.Mail.SMTPHost = "" }, wantErr: "SIGNUP_REQUIRE_VERIFICATION needs SMTP_HOST to send verification emails"},
+		{name: "no verification needs no mailer", modify: func(c *Config) { c.Mail.SMTPHost = ""; c.Signup.RequireVerification = false }},
+		{name: "SMTP without a sender", modify: func(c *Config) { c.Mail.From = "" }, wantErr: "MAIL_FROM is required with SMTP_HOST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("reports every problem", func(t *testing.T) {
+		cfg := defaults()
+		cfg.App.Port = ""
+
+		err := cfg.Validate()
+
+		assert.ErrorContains(t, err, "JWT_SECRET")
+		assert.ErrorContains(t, err, "DB_PASSWORD")
+		assert.ErrorContains(t, err, "APP_PORT")
+	})
+}
+func TestLoad_Log(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		for _, key := range []string{"LOG_LEVEL", "LOG_SAMPLING", "LOG_FORMAT"} {
+			t.Setenv(key, "")
+		}
+
+		cfg, err := load("")
+		require.NoError(t, err)
+		assert.Equal(t, LogConfig{}, cfg.Log)
+	})
+
+	t.Run("from env", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "warn")
+		t.Setenv("LOG_SAMPLING", "false")
+		t.Setenv("LOG_FORMAT", "json")
+
+		cfg, err := load("")
+		require.NoError(t, err)
+		assert.Equal(t, "warn", cfg.Log.Level)
+		require.NotNil(t, cfg.Log.Sampling)
+		assert.False(t, *cfg.Log.Sampling)
+		assert.Equal(t, "json", cfg.Log.Format)
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "verbose")
+
+		_, err := load("")
+		assert.ErrorContains(t, err, "LOG_LEVEL")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		t.Setenv("LOG_FORMAT", "xml")
+
+		_, err := load("")
+		assert.ErrorContains(t, err, "LOG_FORMAT")
+	})
+}
+
+func TestLoad_Pagination(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("DEFAULT_PER_PAGE", "")
+		t.Setenv("MAX_PER_PAGE", "")
+
+		cfg, err := load("")
+		require.NoError(t, err)
+		assert.Equal(t, PaginationConfig{DefaultPerPage: 10, MaxPerPage: 100}, cfg.Pagination)
+	})
+
+	t.Run("from env", func(t *testing.T) {
+		t.Setenv("DEFAULT_PER_PAGE", "50")
+		t.Setenv("MAX_PER_PAGE", "500")
+
+		cfg, err := load("")
+		require.NoError(t, err)
+		assert.Equal(t, PaginationConfig{DefaultPerPage: 50, MaxPerPage: 500}, cfg.Pagination)
+	})
+
+	t.Run("default above max", func(t *testing.T) {
+		t.Setenv("DEFAULT_PER_PAGE", "50")
+		t.Setenv("MAX_PER_PAGE", "20")
+
+		_, err := load("")
+		assert.ErrorContains(t, err, "DEFAULT_PER_PAGE")
+	})
+}
+
+func TestProxyConfig_Apply(t *testing.T) {
+	tests := []struct {
+		name       string
+		proxy      ProxyConfig
+		expectedIP string
+	}{
+		// app.Test requests come from 0.0.0.0
+		{name: "header from trusted proxy", proxy: ProxyConfig{TrustedProxies: []string{"0.0.0.0"}, Header: "X-Forwarded-For"}, expectedIP: "203.0.113.7"},
+		{name: "header from trusted range", proxy: ProxyConfig{TrustedProxies: []string{"0.0.0.0/8"}, Header: "X-Forwarded-For"}, expectedIP: "203.0.113.7"},
+		{name: "spoofed header from untrusted peer", proxy: ProxyConfig{TrustedProxies: []string{"10.0.0.0/8"}, Header: "X-Forwarded-For"}, expectedIP: "0.0.0.0"},
+		{name: "no proxy header configured", proxy: ProxyConfig{}, expectedIP: "0.0.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fc fiber.Config
+			tt.proxy.Apply(&fc)
+			app := fiber.New(fc)
+			app.Get("/", func(c *fiber.Ctx) error { return c.SendString(c.IP()) })
+
+			// a single address, as left by middleware.ProxyClientIP
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedIP, string(body))
+		})
+	}
+}
+
+func TestProxyConfig_Validate(t *testing.T) {
+	assert.NoError(t, ProxyConfig{}.Validate())
+	assert.NoError(t, ProxyConfig{TrustedProxies: []string{"10.0.0.1", "172.16.0.0/12"}, Header: "X-Forwarded-For"}.Validate())
+	assert.ErrorContains(t, ProxyConfig{TrustedProxies: []string{"lb.internal"}}.Validate(), "TRUSTED_PROXIES")
+	assert.ErrorContains(t, ProxyConfig{Header: "X-Forwarded-For"}.Validate(), "requires TRUSTED_PROXIES")
+}
\ No newline at end of file
@@ -1,30 +1,41 @@
 package config
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
+	"github.com/ariam/my-api/internal/repository"
 	"github.com/ariam/my-api/pkg/logger"
+	"github.com/glebarez/sqlite"
+	"github.com/uptrace/opentelemetry-go-extra/otelgorm"
 	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
 )
 
-func NewDatabase(cfg *DBConfig, env string) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable TimeZone=UTC",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name,
-	)
+// maxConnectRetryDelay caps the exponential backoff between connection attempts.
+const maxConnectRetryDelay = 30 * time.Second
 
-	logLevel := gormlogger.Silent
-	if env == "development" {
-		logLevel = gormlogger.Info
-	}
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: gormlogger.Default.LogMode(logLevel),
-	})
+func NewDatabase(cfg *DBConfig, env string) (*gorm.DB, error) {
+	db, err := connectWithRetry(func() (*gorm.DB, error) {
+		db, err := gorm.Open(dialector(cfg), newGormConfig(cfg, env))
+		if err != nil {
+			return nil, err
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		if err := sqlDB.Ping(); err != nil {
+			sqlDB.Close()
+			return nil, err
+		}
+		return db, nil
+	}, cfg.ConnectAttempts, time.Duration(cfg.ConnectRetryDelayMs)*time.Millisecond)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect database: %w", err)
 	}
@@ -34,15 +45,101 @@ func NewDatabase(cfg *DBConfig, env string) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to get database instance: %w", err)
 	}
 
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	configurePool(sqlDB, cfg)
+	if cfg.Driver == "sqlite" && cfg.Name == ":memory:" {
+		// every connection to :memory: is a separate database
+		sqlDB.SetMaxOpenConns(1)
+	}
 
-	logger.Info("Database connected", zap.String("host", cfg.Host), zap.String("database", cfg.Name))
+	logger.Info("Database connected", zap.String("driver", cfg.Driver), zap.String("host", cfg.Host), zap.String("database", cfg.Name))
 
 	return db, nil
 }
 
+// UseTracing records a span for every query made with a traced context.
+// Query parameters are left out of the span so they cannot leak credentials.
+func UseTracing(db *gorm.DB, cfg *DBConfig) error {
+	if err := db.Use(otelgorm.NewPlugin(otelgorm.WithDBName(cfg.Name), otelgorm.WithoutQueryVariables())); err != nil {
+		return fmt.Errorf("failed to enable query tracing: %w", err)
+	}
+	return nil
+}
+
+// UseQueryTimeout cancels any statement that runs longer than
+// cfg.QueryTimeoutMs. Enable it after migrations, whose statements may
+// legitimately take longer.
+func UseQueryTimeout(db *gorm.DB, cfg *DBConfig) error {
+	if cfg.QueryTimeoutMs <= 0 {
+		return nil
+	}
+	if err := db.Use(repository.QueryTimeout(time.Duration(cfg.QueryTimeoutMs) * time.Millisecond)); err != nil {
+		return fmt.Errorf("failed to enable query timeout: %w", err)
+	}
+	return nil
+}
+
+func dialector(cfg *DBConfig) gorm.Dialector {
+	switch cfg.Driver {
+	case "sqlite":
+		return sqlite.Open(cfg.DSN())
+	case "mysql":
+		return mysql.Open(cfg.DSN())
+	default:
+		return postgres.Open(cfg.DSN())
+	}
+}
+
+// connectWithRetry calls dial up to attempts times, doubling the wait from
+// baseDelay after each failure, and returns the last error if none succeed.
+// It lets the API start before the database is accepting connections.
+func connectWithRetry(dial func() (*gorm.DB, error), attempts int, baseDelay time.Duration) (*gorm.DB, error) {
+	attempts = max(attempts, 1)
+	delay := baseDelay
+
+	for attempt := 1; ; attempt++ {
+		db, err := dial()
+		if err == nil {
+			return db, nil
+		}
+		if attempt == attempts {
+			return nil, err
+		}
+
+		logger.Warn("Database not ready, retrying",
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", attempts),
+			zap.Duration("retry_in", delay),
+			zap.Error(err),
+		)
+		time.Sleep(delay)
+		delay = min(delay*2, maxConnectRetryDelay)
+	}
+}
+
+func configurePool(sqlDB *sql.DB, cfg *DBConfig) {
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetimeMinutes) * time.Minute)
+	sqlDB.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTimeMinutes) * time.Minute)
+}
+
+func newGormConfig(cfg *DBConfig, env string) *gorm.Config {
+	logLevel := gormlogger.Silent
+	if env == "development" {
+		logLevel = gormlogger.Info
+	}
+
+	return &gorm.Config{
+		// bound values only appear in development logs
+		Logger: newGormLogger(logLevel, time.Duration(cfg.SlowQueryMs)*time.Millisecond, env != "development"),
+		// unique violations surface as gorm.ErrDuplicatedKey on every driver
+		TranslateError: true,
+		NamingStrategy: schema.NamingStrategy{
+			TablePrefix: cfg.TablePrefix,
+		},
+	}
+}
+
 func CloseDatabase(db *gorm.DB) {
 	sqlDB, err := db.DB()
 	if err != nil {
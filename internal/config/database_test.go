@@ -0,0 +1,101 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestNewGormConfig_TablePrefix(t *testing.T) {
+	tests := []struct {
+		name          string
+		prefix        string
+		expectedTable string
+	}{
+		{name: "no prefix", prefix: "", expectedTable: "`users`"},
+		{name: "with prefix", prefix: "myapi_", expectedTable: "`myapi_users`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := gorm.Open(sqlite.Open(":memory:"), newGormConfig(&DBConfig{TablePrefix: tt.prefix}, "test"))
+			require.NoError(t, err)
+
+			stmt := db.Session(&gorm.Session{DryRun: true}).Find(&[]model.User{}).Statement
+
+			assert.Contains(t, stmt.SQL.String(), "FROM "+tt.expectedTable)
+		})
+	}
+}
+
+func TestConfigurePool(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	configurePool(sqlDB, &DBConfig{MaxIdleConns: 2, MaxOpenConns: 4, ConnMaxLifetimeMinutes: 30})
+
+	assert.Equal(t, 4, sqlDB.Stats().MaxOpenConnections)
+}
+
+func TestConnectWithRetry(t *testing.T) {
+	refused := errors.New("connection refused")
+
+	tests := []struct {
+		name          string
+		failures      int
+		attempts      int
+		expectedCalls int
+		wantErr       bool
+	}{
+		{name: "first attempt succeeds", failures: 0, attempts: 3, expectedCalls: 1},
+		{name: "succeeds after transient failures", failures: 2, attempts: 3, expectedCalls: 3},
+		{name: "gives up after max attempts", failures: 5, attempts: 3, expectedCalls: 3, wantErr: true},
+		{name: "zero attempts still tries once", failures: 1, attempts: 0, expectedCalls: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			dial := func() (*gorm.DB, error) {
+				calls++
+				if calls <= tt.failures {
+					return nil, refused
+				}
+				return gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+			}
+
+			db, err := connectWithRetry(dial, tt.attempts, time.Millisecond)
+
+			assert.Equal(t, tt.expectedCalls, calls)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, refused)
+				assert.Nil(t, db)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, db)
+			}
+		})
+	}
+}
+
+func TestNewDatabase_SQLiteInMemory(t *testing.T) {
+	db, err := NewDatabase(&DBConfig{Driver: "sqlite", Name: ":memory:", MaxIdleConns: 1, MaxOpenConns: 10, ConnectAttempts: 1}, "test")
+	require.NoError(t, err)
+	t.Cleanup(func() { CloseDatabase(db) })
+
+	require.NoError(t, RunMigration(context.Background(), db, "test"))
+
+	assert.True(t, db.Migrator().HasTable(&model.User{}))
+	assert.True(t, db.Migrator().HasTable(&model.Session{}))
+	assert.True(t, db.Migrator().HasTable(&model.UserToken{}))
+}
\ No newline at end of file
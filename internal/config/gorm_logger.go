@@ -0,0 +1,88 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ariam/my-api/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// gormLogger writes GORM's output through the zap logger, tagged with the
+// request_id of the statement's context. level controls the statement and
+// error log as with GORM's default logger; statements slower than
+// slowThreshold are logged as warnings at any level, so they are visible in
+// production too. A zero slowThreshold disables the slow-query log. When
+// parameterized is set, statements are logged with their placeholders rather
+// than the bound values, which may be emails, password hashes or tokens.
+type gormLogger struct {
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+	parameterized bool
+}
+
+func newGormLogger(level gormlogger.LogLevel, slowThreshold time.Duration, parameterized bool) *gormLogger {
+	return &gormLogger{level: level, slowThreshold: slowThreshold, parameterized: parameterized}
+}
+
+// ParamsFilter implements gorm.ParamsFilter, which GORM calls before
+// rendering the statement passed to Trace.
+func (l *gormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.parameterized {
+		return sql, nil
+	}
+	return sql, params
+}
+
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		logger.WithContext(ctx).Info(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		logger.WithContext(ctx).Warn(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		logger.WithContext(ctx).Error(fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	slow := l.slowThreshold > 0 && elapsed > l.slowThreshold
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error
+	if !slow && !failed && l.level < gormlogger.Info {
+		return
+	}
+
+	sql, rows := fc()
+	fields := []zap.Field{
+		zap.String("sql", sql),
+		zap.Int64("rows", rows),
+		zap.Duration("duration", elapsed),
+	}
+	log := logger.WithContext(ctx)
+	switch {
+	case failed:
+		log.Error("Database query failed", append(fields, zap.Error(err))...)
+	case slow:
+		log.Warn("Slow database query", append(fields, zap.Duration("threshold", l.slowThreshold))...)
+	default:
+		log.Debug("Database query", fields...)
+	}
+}
\ No newline at end of file
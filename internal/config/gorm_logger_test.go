@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestGormLogger_SlowQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		slowThreshold time.Duration
+		expectedLogs  int
+	}{
+		{name: "query over the threshold", slowThreshold: time.Nanosecond, expectedLogs: 1},
+		{name: "slow-query log disabled", slowThreshold: 0, expectedLogs: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			t.Cleanup(logger.Replace(zap.New(core)))
+
+			db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+				Logger: newGormLogger(gormlogger.Silent, tt.slowThreshold, false),
+			})
+			require.NoError(t, err)
+			require.NoError(t, db.AutoMigrate(&model.User{}))
+
+			ctx := logger.ContextWithRequestID(context.Background(), "req-slow")
+			var users []model.User
+			require.NoError(t, db.WithContext(ctx).Where("role = ?", "admin").Find(&users).Error)
+
+			entries := logs.FilterMessage("Slow database query").
+				FilterField(zap.String("request_id", "req-slow")).All()
+			require.Len(t, entries, tt.expectedLogs)
+			if tt.expectedLogs == 0 {
+				return
+			}
+
+			assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+			fields := entries[0].ContextMap()
+			assert.Contains(t, fields["sql"], "FROM `users` WHERE role = \"admin\"")
+			assert.Equal(t, int64(0), fields["rows"])
+			assert.Contains(t, fields, "duration")
+		})
+	}
+}
+
+func TestGormLogger_Parameterized(t *testing.T) {
+	tests := []struct {
+		name          string
+		parameterized bool
+		expectedSQL   string
+	}{
+		{name: "bound values logged", parameterized: false, expectedSQL: "\"secret@example.com\""},
+		{name: "placeholders logged", parameterized: true, expectedSQL: "VALUES (?,?,?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			t.Cleanup(logger.Replace(zap.New(core)))
+
+			db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+				Logger: newGormLogger(gormlogger.Silent, time.Nanosecond, tt.parameterized),
+			})
+			require.NoError(t, err)
+			require.NoError(t, db.AutoMigrate(&model.User{}))
+
+			ctx := logger.ContextWithRequestID(context.Background(), "req-params")
+			require.NoError(t, db.WithContext(ctx).Create(&model.User{Name: "Jane", Email: "secret@example.com", Password: "$2a$10$hash"}).Error)
+
+			entries := logs.FilterMessage("Slow database query").
+				FilterField(zap.String("request_id", "req-params")).All()
+			require.NotEmpty(t, entries)
+			var inserts []string
+			for _, entry := range entries {
+				if sql, _ := entry.ContextMap()["sql"].(string); strings.HasPrefix(sql, "INSERT") {
+					inserts = append(inserts, sql)
+				}
+			}
+			require.Len(t, inserts, 1)
+			assert.Contains(t, inserts[0], tt.expectedSQL)
+			if tt.parameterized {
+				assert.NotContains(t, inserts[0], "secret@example.com")
+				assert.NotContains(t, inserts[0], "$2a$10$hash")
+			}
+		})
+	}
+}
\ No newline at end of file
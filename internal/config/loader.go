@@ -0,0 +1,169 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func load(path string) (*Config, error) {
+	cfg := defaults()
+	if path != "" {
+		if err := readFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyEnv(reflect.ValueOf(cfg).Elem()); err != nil {
+		return nil, err
+	}
+
+	cfg.App.BaseURL = strings.TrimRight(cfg.App.BaseURL, "/")
+	// development keeps the permissive default; elsewhere origins must be listed
+	if len(cfg.CORS.AllowedOrigins) == 0 && cfg.App.Env == "development" {
+		cfg.CORS.AllowedOrigins = []string{"*"}
+	}
+
+	// the logger is built from these before anything else can report errors
+	if err := cfg.Log.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Pagination.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Maintenance.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Proxy.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Signup.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// readFile overlays the settings in a YAML or JSON file onto cfg, keyed by the
+// yaml struct tags. JSON is decoded by the YAML parser, which accepts it as a
+// subset. Unknown keys are rejected so a typo doesn't silently fall back to a
+// default.
+func readFile(path string, cfg *Config) error {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml", ".json":
+	default:
+		return fmt.Errorf("config file %s: unsupported extension %q", path, ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv overrides every field tagged `env:"NAME"` whose variable is set to
+// a non-empty value. Values that don't parse as the field's type are
+// reported, all of them joined into one error, so a typo fails startup
+// instead of silently keeping the default.
+func applyEnv(v reflect.Value) error {
+	var errs []error
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		name := t.Field(i).Tag.Get("env")
+		if name == "" {
+			continue
+		}
+		if val := os.Getenv(name); val != "" {
+			if err := setField(field, val); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func setField(field reflect.Value, val string) error {
+	switch field.Interface().(type) {
+	case string:
+		field.SetString(val)
+	case int:
+		i, err := strconv.Atoi(val)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", val)
+		}
+		field.SetInt(int64(i))
+	case bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", val)
+		}
+		field.SetBool(b)
+	case *bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", val)
+		}
+		field.Set(reflect.ValueOf(&b))
+	case []string:
+		if items := parseList(val); len(items) > 0 {
+			field.Set(reflect.ValueOf(items))
+		}
+	case map[string]int:
+		m, err := parseIntMap(val)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(m))
+	default:
+		panic(fmt.Sprintf("config: unsupported env field type %s", field.Type()))
+	}
+	return nil
+}
+
+func parseList(val string) []string {
+	var items []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseIntMap parses "key:int" pairs such as "admin:5,user:3".
+func parseIntMap(val string) (map[string]int, error) {
+	result := make(map[string]int)
+	for _, pair := range parseList(val) {
+		k, v, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("%q is not a key:integer pair", pair)
+		}
+		i, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a key:integer pair", pair)
+		}
+		result[strings.TrimSpace(k)] = i
+	}
+	return result, nil
+}
\ No newline at end of file
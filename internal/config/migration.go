@@ -1,18 +1,52 @@
 package config
 
 import (
-	"github.com/ariam/my-api/internal/model"
+	"context"
+	"fmt"
+	"sync"
+
 	"github.com/ariam/my-api/pkg/logger"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
-func RunMigration(db *gorm.DB) error {
+// bootstrapLockKey identifies the Postgres advisory lock that serialises
+// migrations and seeds across instances.
+const bootstrapLockKey int64 = 0x6d79617069
+
+// bootstrapMu serialises bootstrap within the process on databases that have
+// no advisory locks.
+var bootstrapMu sync.Mutex
+
+// SeedFunc inserts initial data. Seeds run after migrations while the
+// bootstrap lock is held, so a check-then-insert is safe across instances.
+type SeedFunc func(ctx context.Context, db *gorm.DB) error
+
+// RunMigration applies the versioned Migrations and then seeds, holding the
+// bootstrap lock. In development AutoMigrate runs after the migrations as a
+// fallback, picking up model changes that have no migration yet; elsewhere the
+// schema only changes through Migrations, which can be rolled back.
+func RunMigration(ctx context.Context, db *gorm.DB, env string, seeds ...SeedFunc) error {
 	logger.Info("Running database migrations...")
 
-	err := db.AutoMigrate(
-		&model.User{},
-	)
+	err := withBootstrapLock(ctx, db, func() error {
+		if err := Migrate(ctx, db, Migrations); err != nil {
+			return err
+		}
+
+		if env == "development" {
+			if err := db.WithContext(ctx).AutoMigrate(schemaModels()...); err != nil {
+				return err
+			}
+		}
+
+		for _, seed := range seeds {
+			if err := seed(ctx, db.WithContext(ctx)); err != nil {
+				return fmt.Errorf("seed failed: %w", err)
+			}
+		}
+		return nil
+	})
 
 	if err != nil {
 		logger.Error("Migration failed", zap.Error(err))
@@ -21,4 +55,30 @@ func RunMigration(db *gorm.DB) error {
 
 	logger.Info("Database migrations completed")
 	return nil
+}
+
+// withBootstrapLock runs fn while holding the bootstrap lock. On Postgres this
+// is a session advisory lock held on a dedicated connection, so replicas
+// booting together wait for the first one instead of migrating concurrently.
+func withBootstrapLock(ctx context.Context, db *gorm.DB, fn func() error) error {
+	if db.Dialector.Name() != "postgres" {
+		bootstrapMu.Lock()
+		defer bootstrapMu.Unlock()
+		return fn()
+	}
+
+	return db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		logger.Info("Waiting for migration lock...")
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", bootstrapLockKey).Error; err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		defer func() {
+			// release with a fresh context so an expired ctx cannot leak the lock
+			if err := conn.WithContext(context.Background()).Exec("SELECT pg_advisory_unlock(?)", bootstrapLockKey).Error; err != nil {
+				logger.Error("Failed to release migration lock", zap.Error(err))
+			}
+		}()
+
+		return fn()
+	})
 }
\ No newline at end of file
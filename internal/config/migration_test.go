@@ -0,0 +1,115 @@
+package config
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestRunMigration_ConcurrentBootstrapSeedsOnce(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	var inserts atomic.Int32
+	seedAdmin := func(ctx context.Context, db *gorm.DB) error {
+		var count int64
+		if err := db.Model(&model.User{}).Where("email = ?", "admin@example.com").Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+		// widen the window between check and insert
+		time.Sleep(20 * time.Millisecond)
+		inserts.Add(1)
+		return db.Create(&model.User{Name: "Admin", Email: "admin@example.com", Password: "x", Role: "admin"}).Error
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = RunMigration(context.Background(), db, "test", seedAdmin)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), inserts.Load())
+
+	var count int64
+	require.NoError(t, db.Model(&model.User{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestMigrations_EmailLowerUnique(t *testing.T) {
+	db := newMigrationTestDB(t)
+	require.NoError(t, db.AutoMigrate(&model.User{}))
+	require.NoError(t, db.Create(&model.User{Name: "John", Email: " John@Example.com", Password: "x"}).Error)
+
+	require.NoError(t, Migrate(context.Background(), db, Migrations))
+
+	var user model.User
+	require.NoError(t, db.First(&user).Error)
+	assert.Equal(t, "john@example.com", user.Email)
+
+	err := db.Create(&model.User{Name: "Johnny", Email: "JOHN@example.com", Password: "x"}).Error
+	assert.Error(t, err, "mixed-case duplicate should violate the LOWER(email) index")
+
+	require.NoError(t, Rollback(context.Background(), db, Migrations))
+	assert.NoError(t, db.Create(&model.User{Name: "Johnny", Email: "JOHN@example.com", Password: "x"}).Error)
+}
+
+func TestMigrations_EmailLowerUnique_DefersOnCaseDuplicates(t *testing.T) {
+	db := newMigrationTestDB(t)
+	require.NoError(t, db.AutoMigrate(&model.User{}))
+	require.NoError(t, db.Create(&model.User{Name: "John", Email: "john@example.com", Password: "x"}).Error)
+	require.NoError(t, db.Create(&model.User{Name: "Johnny", Email: "John@Example.com", Password: "x"}).Error)
+	require.NoError(t, db.Create(&model.User{Name: "Jane", Email: "Jane@Example.com", Password: "x"}).Error)
+
+	require.NoError(t, Migrate(context.Background(), db, Migrations), "duplicates don't block startup")
+
+	var emails []string
+	require.NoError(t, db.Model(&model.User{}).Order("name").Pluck("email", &emails).Error)
+	assert.Equal(t, []string{"jane@example.com", "john@example.com", "John@Example.com"}, emails, "only the colliding accounts are left alone")
+	assert.Equal(t, []string{Migrations[0].ID}, appliedIDs(t, db), "the migration runs again once the duplicates are resolved")
+
+	require.NoError(t, db.Unscoped().Where("name = ?", "Johnny").Delete(&model.User{}).Error)
+	require.NoError(t, Migrate(context.Background(), db, Migrations))
+	assert.Equal(t, []string{Migrations[0].ID, Migrations[1].ID}, appliedIDs(t, db))
+}
+
+func TestRunMigration_SchemaComesFromMigrationsOutsideDevelopment(t *testing.T) {
+	db := newMigrationTestDB(t)
+
+	require.NoError(t, RunMigration(context.Background(), db, "production"))
+
+	for _, m := range schemaModels() {
+		assert.True(t, db.Migrator().HasTable(m))
+	}
+	assert.Equal(t, []string{Migrations[0].ID, Migrations[1].ID}, appliedIDs(t, db))
+
+	ctx := context.Background()
+	require.NoError(t, Rollback(ctx, db, Migrations))
+	require.NoError(t, Rollback(ctx, db, Migrations))
+	assert.False(t, db.Migrator().HasTable(&model.User{}), "the initial schema rolls back too")
+}
\ No newline at end of file
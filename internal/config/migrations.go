@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ariam/my-api/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Migrations are applied in order by RunMigration. Outside development they
+// are the only way the schema changes, so a model change needs a migration
+// here.
+var Migrations = []Migration{
+	{
+		ID:   "20261015_initial_schema",
+		Up:   initialSchemaUp,
+		Down: initialSchemaDown,
+	},
+	{
+		ID:   "20261016_users_email_lower_unique",
+		Up:   lowercaseEmailsUp,
+		Down: lowercaseEmailsDown,
+	},
+}
+
+// schemaModels are the models with tables, in creation order.
+func schemaModels() []interface{} {
+	return []interface{}{
+		&model.User{},
+		&model.Session{},
+		&model.UserToken{},
+		&model.AuditLog{},
+	}
+}
+
+// initialSchemaUp creates the model tables. On databases set up before
+// versioned migrations the tables already exist, and only what they are
+// missing is added.
+func initialSchemaUp(tx *gorm.DB) error {
+	return tx.AutoMigrate(schemaModels()...)
+}
+
+// initialSchemaDown drops the model tables, and all their data with them.
+func initialSchemaDown(tx *gorm.DB) error {
+	models := schemaModels()
+	for i := len(models) - 1; i >= 0; i-- {
+		if err := tx.Migrator().DropTable(models[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lowercaseEmailsUp lowercases stored emails to match what the user service
+// now writes, and adds a unique index on LOWER(email) so addresses differing
+// only in case can't be stored side by side. Accounts whose emails collide
+// once lowercased are left as they are and reported, and the migration is
+// deferred until they are merged or renamed by hand; the other emails are
+// lowercased meanwhile.
+//
+// MySQL gets no index: its default collations already compare emails case
+// insensitively, so the existing unique index covers it.
+func lowercaseEmailsUp(tx *gorm.DB) error {
+	table, err := usersTable(tx)
+	if err != nil {
+		return err
+	}
+
+	// soft-deleted accounts count too: the index covers them
+	var duplicates []string
+	err = tx.Raw("SELECT LOWER(TRIM(email)) FROM ? GROUP BY LOWER(TRIM(email)) HAVING COUNT(*) > 1 ORDER BY 1",
+		clause.Table{Name: table}).Scan(&duplicates).Error
+	if err != nil {
+		return err
+	}
+
+	update := tx.Table(table).Where("email <> LOWER(TRIM(email))")
+	if len(duplicates) > 0 {
+		update = update.Where("LOWER(TRIM(email)) NOT IN ?", duplicates)
+	}
+	if err := update.UpdateColumn("email", gorm.Expr("LOWER(TRIM(email))")).Error; err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("%w: accounts differing only in the case of their email must be merged or renamed: %s",
+			ErrMigrationDeferred, strings.Join(duplicates, ", "))
+	}
+
+	if tx.Dialector.Name() == "mysql" {
+		return nil
+	}
+	return tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS ? ON ? (LOWER(email))",
+		clause.Table{Name: "idx_" + table + "_email_lower"}, clause.Table{Name: table}).Error
+}
+
+// lowercaseEmailsDown drops the index; the lowercased emails are kept.
+func lowercaseEmailsDown(tx *gorm.DB) error {
+	if tx.Dialector.Name() == "mysql" {
+		return nil
+	}
+	table, err := usersTable(tx)
+	if err != nil {
+		return err
+	}
+	return tx.Exec("DROP INDEX IF EXISTS ?", clause.Table{Name: "idx_" + table + "_email_lower"}).Error
+}
+
+// usersTable resolves the users table name under the configured naming
+// strategy, which may add a prefix.
+func usersTable(tx *gorm.DB) (string, error) {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(&model.User{}); err != nil {
+		return "", err
+	}
+	return stmt.Schema.Table, nil
+}
\ No newline at end of file
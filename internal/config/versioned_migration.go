@@ -0,0 +1,136 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ariam/my-api/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Migration is a versioned schema change for what AutoMigrate cannot express:
+// dropping or renaming columns and backfilling data. IDs must be unique and
+// should sort in the order migrations were written, e.g. "20250101_drop_x".
+type Migration struct {
+	ID   string
+	Up   func(tx *gorm.DB) error
+	Down func(tx *gorm.DB) error
+}
+
+// ErrMigrationDeferred is returned, wrapped, by a Migration's Up when it
+// can't finish until data is fixed by hand. Migrate commits what Up did but
+// doesn't record the migration, logs the error and stops without failing, so
+// the service still starts and the migration, and those after it, run again
+// on the next start.
+var ErrMigrationDeferred = errors.New("migration deferred")
+
+// schemaMigration records an applied Migration. Its table is named by the
+// naming strategy, as schema_migrations plus any DB_TABLE_PREFIX.
+type schemaMigration struct {
+	ID        string `gorm:"primaryKey;size:255"`
+	AppliedAt time.Time
+}
+
+// Migrate applies the migrations that have not run yet, in slice order. Each
+// migration and its schema_migrations row are committed together, so a
+// failed migration is retried on the next run. A migration that returns
+// ErrMigrationDeferred ends the run without an error.
+func Migrate(ctx context.Context, db *gorm.DB, migrations []Migration) error {
+	db = db.WithContext(ctx)
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.ID] {
+			continue
+		}
+
+		var deferred error
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				if errors.Is(err, ErrMigrationDeferred) {
+					deferred = err
+					return nil
+				}
+				return err
+			}
+			return tx.Create(&schemaMigration{ID: m.ID, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %s failed: %w", m.ID, err)
+		}
+		if deferred != nil {
+			logger.Warn("Migration deferred; it and later migrations will be retried on the next start",
+				zap.String("id", m.ID), zap.Error(deferred))
+			return nil
+		}
+		logger.Info("Applied migration", zap.String("id", m.ID))
+	}
+	return nil
+}
+
+// Rollback reverts the most recently applied migration. It returns nil when
+// nothing has been applied.
+func Rollback(ctx context.Context, db *gorm.DB, migrations []Migration) error {
+	db = db.WithContext(ctx)
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var last schemaMigration
+	err := db.Order("applied_at DESC").Order("id DESC").First(&last).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var m *Migration
+	for i := range migrations {
+		if migrations[i].ID == last.ID {
+			m = &migrations[i]
+			break
+		}
+	}
+	if m == nil {
+		return fmt.Errorf("migration %s is applied but not defined", last.ID)
+	}
+	if m.Down == nil {
+		return fmt.Errorf("migration %s cannot be rolled back", m.ID)
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := m.Down(tx); err != nil {
+			return err
+		}
+		return tx.Delete(&schemaMigration{}, "id = ?", m.ID).Error
+	})
+	if err != nil {
+		return fmt.Errorf("rollback of %s failed: %w", m.ID, err)
+	}
+	logger.Info("Rolled back migration", zap.String("id", m.ID))
+	return nil
+}
+
+func appliedMigrations(db *gorm.DB) (map[string]bool, error) {
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		applied[row.ID] = true
+	}
+	return applied, nil
+}
\ No newline at end of file
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type auditEntry struct {
+	ID     uint
+	Action string
+}
+
+func newMigrationTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		{
+			ID:   "0001_create_audit_entries",
+			Up:   func(tx *gorm.DB) error { return tx.Migrator().CreateTable(&auditEntry{}) },
+			Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable(&auditEntry{}) },
+		},
+		{
+			ID: "0002_add_audit_actor",
+			Up: func(tx *gorm.DB) error {
+				return tx.Exec("ALTER TABLE audit_entries ADD COLUMN actor TEXT").Error
+			},
+			Down: func(tx *gorm.DB) error {
+				return tx.Exec("ALTER TABLE audit_entries DROP COLUMN actor").Error
+			},
+		},
+	}
+}
+
+func appliedIDs(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var ids []string
+	require.NoError(t, db.Model(&schemaMigration{}).Order("id").Pluck("id", &ids).Error)
+	return ids
+}
+
+func TestMigrate_AppliesInOrderOnce(t *testing.T) {
+	db := newMigrationTestDB(t)
+	ctx := context.Background()
+
+	require.NoError(t, Migrate(ctx, db, testMigrations()))
+	require.NoError(t, Migrate(ctx, db, testMigrations()), "re-running skips applied migrations")
+
+	assert.True(t, db.Migrator().HasColumn("audit_entries", "actor"))
+	assert.Equal(t, []string{"0001_create_audit_entries", "0002_add_audit_actor"}, appliedIDs(t, db))
+}
+
+func TestRollback_RestoresSchema(t *testing.T) {
+	db := newMigrationTestDB(t)
+	ctx := context.Background()
+	migrations := testMigrations()
+
+	require.NoError(t, Migrate(ctx, db, migrations))
+
+	require.NoError(t, Rollback(ctx, db, migrations))
+	assert.True(t, db.Migrator().HasTable("audit_entries"))
+	assert.False(t, db.Migrator().HasColumn("audit_entries", "actor"))
+	assert.Equal(t, []string{"0001_create_audit_entries"}, appliedIDs(t, db))
+
+	require.NoError(t, Rollback(ctx, db, migrations))
+	assert.False(t, db.Migrator().HasTable("audit_entries"))
+	assert.Empty(t, appliedIDs(t, db))
+
+	assert.NoError(t, Rollback(ctx, db, migrations), "nothing left to roll back")
+}
+
+func TestMigrate_FailedMigrationIsNotRecorded(t *testing.T) {
+	db := newMigrationTestDB(t)
+	ctx := context.Background()
+
+	migrations := append(testMigrations(), Migration{
+		ID: "0003_broken",
+		Up: func(tx *gorm.DB) error { return errors.New("backfill failed") },
+	})
+
+	err := Migrate(ctx, db, migrations)
+
+	assert.ErrorContains(t, err, "migration 0003_broken failed")
+	assert.Equal(t, []string{"0001_create_audit_entries", "0002_add_audit_actor"}, appliedIDs(t, db))
+}
+
+func TestMigrate_TablePrefix(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), newGormConfig(&DBConfig{TablePrefix: "myapi_"}, "test"))
+	require.NoError(t, err)
+
+	require.NoError(t, Migrate(context.Background(), db, nil))
+
+	assert.True(t, db.Migrator().HasTable("myapi_schema_migrations"))
+	assert.False(t, db.Migrator().HasTable("schema_migrations"))
+}
\ No newline at end of file
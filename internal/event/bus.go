@@ -0,0 +1,96 @@
+package event
+
+import (
+	"sync"
+
+	"github.com/ariam/my-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// All subscribes to every event type.
+const All = "*"
+
+// queueSize is how many events may wait for a subscriber before further
+// events are dropped for it.
+const queueSize = 256
+
+// Event is something that happened in the domain. Type names the kind of
+// event and is what subscribers select on.
+type Event interface {
+	Type() string
+}
+
+type Handler func(Event)
+
+type subscription struct {
+	eventType string
+	handler   Handler
+	queue     chan Event
+}
+
+// Bus delivers published events to in-process subscribers. Each subscriber
+// has its own goroutine, so handlers run in publish order but never block the
+// publisher, and a handler that panics only loses the event it panicked on.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[*subscription]struct{}
+}
+
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*subscription]struct{})}
+}
+
+// Subscribe calls h for every event of eventType, or every event for All,
+// published until the returned function is called.
+func (b *Bus) Subscribe(eventType string, h Handler) (unsubscribe func()) {
+	sub := &subscription{eventType: eventType, handler: h, queue: make(chan Event, queueSize)}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	go sub.run()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, sub)
+			close(sub.queue)
+			b.mu.Unlock()
+		})
+	}
+}
+
+// Publish queues e for every subscriber to its type and returns without
+// waiting for them.
+func (b *Bus) Publish(e Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subs {
+		if sub.eventType != All && sub.eventType != e.Type() {
+			continue
+		}
+		select {
+		case sub.queue <- e:
+		default:
+			logger.Warn("Event dropped, subscriber is not keeping up", zap.String("type", e.Type()))
+		}
+	}
+}
+
+func (s *subscription) run() {
+	for e := range s.queue {
+		s.deliver(e)
+	}
+}
+
+func (s *subscription) deliver(e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Event handler panicked", zap.String("type", e.Type()), zap.Any("panic", r))
+		}
+	}()
+	s.handler(e)
+}
\ No newline at end of file
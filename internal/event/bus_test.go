@@ -0,0 +1,89 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEvent struct {
+	kind string
+	n    int
+}
+
+func (e testEvent) Type() string { return e.kind }
+
+func receive(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(time.Second):
+		require.FailNow(t, "no event delivered")
+		return nil
+	}
+}
+
+func TestBus_DeliversToSubscribersOfType(t *testing.T) {
+	bus := NewBus()
+
+	created := make(chan Event, 10)
+	deleted := make(chan Event, 10)
+	all := make(chan Event, 10)
+	bus.Subscribe("created", func(e Event) { created <- e })
+	bus.Subscribe("deleted", func(e Event) { deleted <- e })
+	bus.Subscribe(All, func(e Event) { all <- e })
+
+	bus.Publish(testEvent{kind: "created", n: 1})
+	bus.Publish(testEvent{kind: "updated", n: 2})
+	bus.Publish(testEvent{kind: "created", n: 3})
+
+	assert.Equal(t, testEvent{kind: "created", n: 1}, receive(t, created))
+	assert.Equal(t, testEvent{kind: "created", n: 3}, receive(t, created), "delivered in publish order")
+	for n := 1; n <= 3; n++ {
+		assert.Equal(t, n, receive(t, all).(testEvent).n)
+	}
+	assert.Empty(t, deleted)
+}
+
+func TestBus_PanickingSubscriberIsIsolated(t *testing.T) {
+	bus := NewBus()
+
+	healthy := make(chan Event, 10)
+	recovered := make(chan Event, 10)
+	bus.Subscribe("created", func(e Event) { healthy <- e })
+	bus.Subscribe("created", func(e Event) {
+		if e.(testEvent).n == 1 {
+			panic("boom")
+		}
+		recovered <- e
+	})
+
+	assert.NotPanics(t, func() {
+		bus.Publish(testEvent{kind: "created", n: 1})
+		bus.Publish(testEvent{kind: "created", n: 2})
+	})
+
+	assert.Equal(t, 1, receive(t, healthy).(testEvent).n)
+	assert.Equal(t, 2, receive(t, healthy).(testEvent).n)
+	assert.Equal(t, 2, receive(t, recovered).(testEvent).n, "the subscriber keeps receiving after a panic")
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	bus := NewBus()
+
+	ch := make(chan Event, 10)
+	unsubscribe := bus.Subscribe("created", func(e Event) { ch <- e })
+	unsubscribe()
+	unsubscribe()
+
+	bus.Publish(testEvent{kind: "created"})
+
+	select {
+	case <-ch:
+		t.Fatal("unsubscribed handler received an event")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
\ No newline at end of file
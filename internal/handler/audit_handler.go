@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/ariam/my-api/internal/service"
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+	pageLimits   service.PageLimits
+}
+
+type AuditHandlerOption func(*AuditHandler)
+
+// WithAuditPageLimits bounds the per_page query parameter.
+func WithAuditPageLimits(limits service.PageLimits) AuditHandlerOption {
+	return func(h *AuditHandler) {
+		h.pageLimits = limits
+	}
+}
+
+func NewAuditHandler(auditService service.AuditService, opts ...AuditHandlerOption) *AuditHandler {
+	h := &AuditHandler{auditService: auditService}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// FindAll godoc
+// @Summary List audit log
+// @Description Get audit log entries, newest first (admin only)
+// @Tags Audit
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(10)
+// @Success 200 {object} response.Response{data=response.PaginatedData{items=[]model.AuditLog}}
+// @Header 200 {integer} X-Total-Count "Total number of items"
+// @Header 200 {integer} X-Page "Current page"
+// @Header 200 {integer} X-Per-Page "Items per page"
+// @Header 200 {integer} X-Total-Pages "Total number of pages"
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /audit [get]
+func (h *AuditHandler) FindAll(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	perPage, _ := strconv.Atoi(c.Query("per_page"))
+	page, perPage = h.pageLimits.Normalize(page, perPage)
+
+	entries, total, err := h.auditService.FindAll(requestContext(c), page, perPage)
+	if err != nil {
+		return response.InternalServerError(c, "Failed to fetch audit log")
+	}
+
+	return response.PaginatedWithLinks(c, entries, total, page, perPage)
+}
\ No newline at end of file
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) FindAll(ctx context.Context, page, perPage int) ([]model.AuditLog, int64, error) {
+	args := m.Called(ctx, page, perPage)
+	return args.Get(0).([]model.AuditLog), args.Get(1).(int64), args.Error(2)
+}
+
+func TestAuditHandler_FindAll(t *testing.T) {
+	mockService := new(MockAuditService)
+	mockService.On("FindAll", mock.Anything, 2, 5).
+		Return([]model.AuditLog{{Action: model.AuditActionUserDelete, TargetType: model.AuditTargetUser, TargetID: testUserID}}, int64(6), nil)
+
+	app := fiber.New()
+	app.Get("/audit", NewAuditHandler(mockService).FindAll)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/audit?page=2&per_page=5", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body response.Response
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	data := body.Data.(map[string]interface{})
+	assert.Equal(t, float64(6), data["total"])
+	assert.Equal(t, float64(2), data["total_pages"])
+	items := data["items"].([]interface{})
+	assert.Equal(t, "user.delete", items[0].(map[string]interface{})["action"])
+	mockService.AssertExpectations(t)
+}
\ No newline at end of file
@@ -3,18 +3,103 @@ package handler
 import (
 	"errors"
 
+	"github.com/ariam/my-api/internal/authz"
+	"github.com/ariam/my-api/internal/model"
 	"github.com/ariam/my-api/internal/service"
 	"github.com/ariam/my-api/pkg/response"
-	"github.com/ariam/my-api/pkg/validator"
 	"github.com/gofiber/fiber/v2"
 )
 
+var defaultPublicUserFields = []string{"id", "name", "email"}
+
 type AuthHandler struct {
-	authService service.AuthService
+	authService  service.AuthService
+	userService  service.UserService
+	publicFields []string
+}
+
+type AuthHandlerOption func(*AuthHandler)
+
+// WithPublicUserFields sets the safe-list of user fields shown to a caller
+// who registers without being signed in, such as while the account awaits
+// verification or approval.
+func WithPublicUserFields(fields ...string) AuthHandlerOption {
+	return func(h *AuthHandler) {
+		if len(fields) > 0 {
+			h.publicFields = fields
+		}
+	}
 }
 
-func NewAuthHandler(authService service.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
+func NewAuthHandler(authService service.AuthService, userService service.UserService, opts ...AuthHandlerOption) *AuthHandler {
+	h := &AuthHandler{
+		authService:  authService,
+		userService:  userService,
+		publicFields: defaultPublicUserFields,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Register godoc
+// @Summary Register an account
+// @Description Create an account and sign in to it. No token is returned while the account awaits email verification or approval. With validate_only=true the details are checked, including email uniqueness, but no account is created, so signup forms can validate as the user types.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.CreateUserInput true "Account details"
+// @Param validate_only query bool false "Validate the details without creating the account"
+// @Success 200 {object} response.Response "validate_only: the details are valid"
+// @Success 201 {object} response.Response{data=service.AuthResponse}
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Router /auth/register [post]
+func (h *AuthHandler) Register(c *fiber.Ctx) error {
+	input, err := BindAndValidateStrict[service.CreateUserInput](c)
+	if err != nil {
+		return handled(err)
+	}
+
+	if c.QueryBool("validate_only") {
+		return validateNewUser(c, h.userService, input)
+	}
+
+	ctx := requestContext(c)
+	var warnings []string
+
+	user, err := h.userService.Register(ctx, input)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrVerificationEmailNotSent) && user != nil:
+			warnings = append(warnings, "Account created, but the verification email could not be sent")
+		case errors.Is(err, service.ErrEmailAlreadyExists):
+			return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeEmailExists, err.Error())
+		case errors.Is(err, service.ErrEmailDeletedAccount):
+			return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeEmailDeletedAccount, err.Error())
+		default:
+			return response.InternalServerError(c, "Registration failed")
+		}
+	}
+
+	// Login would refuse these accounts, so don't hand out a token either. The
+	// caller stays anonymous and sees only the public fields.
+	if !user.IsActive || user.ApprovalStatus == model.ApprovalPending {
+		view, err := response.Project(user, h.publicFields)
+		if err != nil {
+			return response.InternalServerError(c, "Registration failed")
+		}
+		return response.CreatedWithWarnings(c, fiber.Map{"user": view}, warnings...)
+	}
+
+	result, err := h.authService.StartSession(ctx, user.ID, c.IP(), c.Get("User-Agent"))
+	if err != nil {
+		return response.InternalServerError(c, "Account created, but signing in failed")
+	}
+
+	return response.CreatedWithWarnings(c, result, warnings...)
 }
 
 // Login godoc
@@ -27,22 +112,35 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 // @Success 200 {object} response.Response{data=service.AuthResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 429 {object} response.Response
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
-	var input service.LoginInput
-
-	if err := c.BodyParser(&input); err != nil {
-		return response.BadRequest(c, "Invalid request body")
+	input, err := BindAndValidate[service.LoginInput](c)
+	if err != nil {
+		return handled(err)
 	}
 
-	if errs := validator.Validate(&input); len(errs) > 0 {
-		return response.ValidationError(c, errs)
-	}
+	input.IP = c.IP()
+	input.UserAgent = c.Get("User-Agent")
 
-	result, err := h.authService.Login(c.Context(), &input)
+	result, err := h.authService.Login(requestContext(c), input)
 	if err != nil {
 		if errors.Is(err, service.ErrInvalidCredentials) {
-			return response.Unauthorized(c, "Invalid email or password")
+			return response.ErrorCode(c, fiber.StatusUnauthorized, response.CodeInvalidCredentials, "Invalid email or password")
+		}
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			return response.ErrorCode(c, fiber.StatusForbidden, response.CodeEmailNotVerified, "Email address has not been verified")
+		}
+		if errors.Is(err, service.ErrPendingApproval) {
+			return response.ErrorCode(c, fiber.StatusForbidden, response.CodePendingApproval, "Account is pending approval")
+		}
+		if errors.Is(err, service.ErrAccountRejected) {
+			return response.ErrorCode(c, fiber.StatusForbidden, response.CodeAccountRejected, "Account registration was rejected")
+		}
+		if errors.Is(err, service.ErrSessionLimitReached) {
+			return response.ErrorCode(c, fiber.StatusConflict, response.CodeSessionLimitReached, "Maximum number of active sessions reached")
 		}
 		return response.InternalServerError(c, "Login failed")
 	}
@@ -52,18 +150,105 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 
 // Me godoc
 // @Summary Get current user
-// @Description Get authenticated user info from token
+// @Description Get the authenticated user's current record
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} response.Response
+// @Success 200 {object} response.Response{data=service.UserResponse}
 // @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Router /auth/me [get]
 func (h *AuthHandler) Me(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	// the token's claims may predate changes to the account, so read it fresh
+	user, err := h.userService.FindByID(requestContext(c), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return response.ErrorCode(c, fiber.StatusNotFound, response.CodeUserNotFound, err.Error())
+		}
+		return response.InternalServerError(c, "Failed to fetch user")
+	}
+
+	if !user.IsActive {
+		return response.Unauthorized(c, "Account is deactivated")
+	}
+
+	return response.Success(c, user)
+}
+
+// Permissions godoc
+// @Summary Get current user's permissions
+// @Description Get the permission set resolved from the authenticated user's role
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /auth/permissions [get]
+func (h *AuthHandler) Permissions(c *fiber.Ctx) error {
+	role, _ := c.Locals("role").(string)
+
 	return response.Success(c, fiber.Map{
-		"user_id": c.Locals("user_id"),
-		"email":   c.Locals("email"),
-		"role":    c.Locals("role"),
+		"role":        role,
+		"permissions": authz.PermissionsFor(role),
 	})
+}
+
+// Sessions godoc
+// @Summary List active sessions
+// @Description List the authenticated user's active sessions, oldest first. The session the request was made with is marked current.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]service.SessionResponse}
+// @Failure 401 {object} response.Response
+// @Failure 501 {object} response.Response
+// @Router /auth/sessions [get]
+func (h *AuthHandler) Sessions(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+	currentID, _ := c.Locals("session_id").(string)
+
+	sessions, err := h.authService.ListSessions(requestContext(c), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrSessionsDisabled) {
+			return response.Error(c, fiber.StatusNotImplemented, "Session tracking is not enabled")
+		}
+		return response.InternalServerError(c, "Failed to fetch sessions")
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+
+	return response.Success(c, sessions)
+}
+
+// RevokeSession godoc
+// @Summary Revoke a session
+// @Description Sign out one of the authenticated user's sessions. Tokens issued for it stop working immediately.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 501 {object} response.Response
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	if err := h.authService.RevokeSession(requestContext(c), userID, c.Params("id")); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			return response.ErrorCode(c, fiber.StatusNotFound, response.CodeSessionNotFound, "Session not found")
+		}
+		if errors.Is(err, service.ErrSessionsDisabled) {
+			return response.Error(c, fiber.StatusNotImplemented, "Session tracking is not enabled")
+		}
+		return response.InternalServerError(c, "Failed to revoke session")
+	}
+
+	return response.NoContent(c)
 }
\ No newline at end of file
@@ -14,6 +14,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockAuthService implements service.AuthService interface for testing
@@ -30,12 +31,43 @@ func (m *MockAuthService) Login(ctx context.Context, input *service.LoginInput)
 	return args.Get(0).(*service.AuthResponse), args.Error(1)
 }
 
+// IsSessionActive implements service.AuthService.IsSessionActive
+func (m *MockAuthService) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+// StartSession implements service.AuthService.StartSession
+func (m *MockAuthService) StartSession(ctx context.Context, userID, ip, userAgent string) (*service.AuthResponse, error) {
+	args := m.Called(ctx, userID, ip, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.AuthResponse), args.Error(1)
+}
+
+// ListSessions implements service.AuthService.ListSessions
+func (m *MockAuthService) ListSessions(ctx context.Context, userID string) ([]service.SessionResponse, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]service.SessionResponse), args.Error(1)
+}
+
+// RevokeSession implements service.AuthService.RevokeSession
+func (m *MockAuthService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Error(0)
+}
+
 // setupAuthTestApp creates a Fiber app with auth routes for testing
 func setupAuthTestApp(handler *AuthHandler) *fiber.App {
 	validator.Init()
 	app := fiber.New()
 
 	// Auth routes
+	app.Post("/auth/register", handler.Register)
 	app.Post("/auth/login", handler.Login)
 	app.Get("/auth/me", handler.Me)
 
@@ -45,7 +77,7 @@ func setupAuthTestApp(handler *AuthHandler) *fiber.App {
 // TestAuthHandler_Login_Success tests successful login
 func TestAuthHandler_Login_Success(t *testing.T) {
 	mockService := new(MockAuthService)
-	handler := NewAuthHandler(mockService)
+	handler := NewAuthHandler(mockService, new(MockUserService))
 	app := setupAuthTestApp(handler)
 
 	input := map[string]string{
@@ -79,7 +111,7 @@ func TestAuthHandler_Login_Success(t *testing.T) {
 // TestAuthHandler_Login_InvalidJSON tests login with invalid JSON body
 func TestAuthHandler_Login_InvalidJSON(t *testing.T) {
 	mockService := new(MockAuthService)
-	handler := NewAuthHandler(mockService)
+	handler := NewAuthHandler(mockService, new(MockUserService))
 	app := setupAuthTestApp(handler)
 
 	req := httptest.NewRequest("POST", "/auth/login", bytes.NewReader([]byte("invalid json")))
@@ -94,7 +126,7 @@ func TestAuthHandler_Login_InvalidJSON(t *testing.T) {
 // TestAuthHandler_Login_ValidationError tests login with validation failure
 func TestAuthHandler_Login_ValidationError(t *testing.T) {
 	mockService := new(MockAuthService)
-	handler := NewAuthHandler(mockService)
+	handler := NewAuthHandler(mockService, new(MockUserService))
 	app := setupAuthTestApp(handler)
 
 	input := map[string]string{
@@ -115,7 +147,7 @@ func TestAuthHandler_Login_ValidationError(t *testing.T) {
 // TestAuthHandler_Login_InvalidCredentials tests login with wrong credentials
 func TestAuthHandler_Login_InvalidCredentials(t *testing.T) {
 	mockService := new(MockAuthService)
-	handler := NewAuthHandler(mockService)
+	handler := NewAuthHandler(mockService, new(MockUserService))
 	app := setupAuthTestApp(handler)
 
 	input := map[string]string{
@@ -140,7 +172,7 @@ func TestAuthHandler_Login_InvalidCredentials(t *testing.T) {
 // Requirements: 1.5
 func TestAuthHandler_Login_ServiceError(t *testing.T) {
 	mockService := new(MockAuthService)
-	handler := NewAuthHandler(mockService)
+	handler := NewAuthHandler(mockService, new(MockUserService))
 	app := setupAuthTestApp(handler)
 
 	input := map[string]string{
@@ -175,109 +207,441 @@ func TestAuthHandler_Login_ServiceError(t *testing.T) {
 func TestAuthHandler_Me(t *testing.T) {
 	tests := []struct {
 		name           string
-		setupContext   func(*fiber.App) *fiber.App
+		setupMock      func(*MockUserService)
 		expectedStatus int
 		checkResponse  func(*testing.T, response.Response)
 	}{
 		{
-			name: "returns user context values with 200 status",
-			setupContext: func(app *fiber.App) *fiber.App {
-				// Create a new app with middleware that sets context values
-				mockService := new(MockAuthService)
-				handler := NewAuthHandler(mockService)
-				validator.Init()
-				newApp := fiber.New()
-
-				// Middleware to simulate authenticated user context
-				newApp.Use(func(c *fiber.Ctx) error {
-					c.Locals("user_id", "test-user-id-123")
-					c.Locals("email", "test@example.com")
-					c.Locals("role", "user")
-					return c.Next()
-				})
-
-				newApp.Get("/auth/me", handler.Me)
-				return newApp
+			name: "returns the live user record rather than the token claims",
+			setupMock: func(m *MockUserService) {
+				m.On("FindByID", mock.Anything, "test-user-id-123").Return(&service.UserResponse{
+					ID:       "test-user-id-123",
+					Name:     "Renamed User",
+					Email:    "test@example.com",
+					Role:     "admin",
+					IsActive: true,
+				}, nil)
 			},
 			expectedStatus: fiber.StatusOK,
 			checkResponse: func(t *testing.T, resp response.Response) {
 				assert.True(t, resp.Success)
 				data, ok := resp.Data.(map[string]interface{})
 				assert.True(t, ok, "Data should be a map")
-				assert.Equal(t, "test-user-id-123", data["user_id"])
-				assert.Equal(t, "test@example.com", data["email"])
-				assert.Equal(t, "user", data["role"])
+				assert.Equal(t, "test-user-id-123", data["id"])
+				assert.Equal(t, "Renamed User", data["name"])
+				assert.Equal(t, "admin", data["role"], "role comes from the database, not the token")
 			},
 		},
 		{
-			name: "includes all context fields (user_id, email, role)",
-			setupContext: func(app *fiber.App) *fiber.App {
-				// Create a new app with middleware that sets all context fields
-				mockService := new(MockAuthService)
-				handler := NewAuthHandler(mockService)
-				validator.Init()
-				newApp := fiber.New()
-
-				// Middleware to simulate authenticated admin user context
-				newApp.Use(func(c *fiber.Ctx) error {
-					c.Locals("user_id", "admin-uuid-456")
-					c.Locals("email", "admin@example.com")
-					c.Locals("role", "admin")
-					return c.Next()
-				})
-
-				newApp.Get("/auth/me", handler.Me)
-				return newApp
+			name: "deleted user returns 404",
+			setupMock: func(m *MockUserService) {
+				m.On("FindByID", mock.Anything, "test-user-id-123").Return(nil, service.ErrUserNotFound)
 			},
-			expectedStatus: fiber.StatusOK,
+			expectedStatus: fiber.StatusNotFound,
 			checkResponse: func(t *testing.T, resp response.Response) {
-				assert.True(t, resp.Success)
-				data, ok := resp.Data.(map[string]interface{})
-				assert.True(t, ok, "Data should be a map")
+				assert.False(t, resp.Success)
+				assert.Equal(t, response.CodeUserNotFound, resp.Code)
+			},
+		},
+		{
+			name: "deactivated user returns 401",
+			setupMock: func(m *MockUserService) {
+				m.On("FindByID", mock.Anything, "test-user-id-123").Return(&service.UserResponse{
+					ID:    "test-user-id-123",
+					Email: "test@example.com",
+					Role:  "user",
+				}, nil)
+			},
+			expectedStatus: fiber.StatusUnauthorized,
+			checkResponse: func(t *testing.T, resp response.Response) {
+				assert.False(t, resp.Success)
+			},
+		},
+		{
+			name: "service error returns 500",
+			setupMock: func(m *MockUserService) {
+				m.On("FindByID", mock.Anything, "test-user-id-123").Return(nil, errors.New("database connection failed"))
+			},
+			expectedStatus: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userService := new(MockUserService)
+			tt.setupMock(userService)
+			handler := NewAuthHandler(new(MockAuthService), userService)
+
+			app := fiber.New()
+			// Middleware to simulate the claims of an older token
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals("user_id", "test-user-id-123")
+				c.Locals("email", "test@example.com")
+				c.Locals("role", "user")
+				return c.Next()
+			})
+			app.Get("/auth/me", handler.Me)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/auth/me", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var respBody response.Response
+			err = json.NewDecoder(resp.Body).Decode(&respBody)
+			assert.NoError(t, err)
+
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, respBody)
+			}
+			userService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestAuthHandler_Login_SessionLimitReached tests login rejected by the session cap
+func TestAuthHandler_Login_SessionLimitReached(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, new(MockUserService))
+	app := setupAuthTestApp(handler)
+
+	mockService.On("Login", mock.Anything, mock.AnythingOfType("*service.LoginInput")).Return(nil, service.ErrSessionLimitReached)
+
+	body, _ := json.Marshal(map[string]string{
+		"email":    "test@example.com",
+		"password": "password123",
+	})
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	mockService.AssertExpectations(t)
+}
+
+// TestAuthHandler_Permissions tests the permission set resolved from the caller's role
+func TestAuthHandler_Permissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		role        string
+		contains    []string
+		notContains []string
+	}{
+		{name: "admin has management permissions", role: "admin", contains: []string{"users:read", "users:delete", "users:import"}},
+		{name: "user cannot delete or import", role: "user", contains: []string{"users:read", "profile:export"}, notContains: []string{"users:delete", "users:import"}},
+		{name: "unknown role has none", role: "guest", notContains: []string{"users:read"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAuthHandler(new(MockAuthService), new(MockUserService))
+			app := fiber.New()
+			app.Get("/auth/permissions", func(c *fiber.Ctx) error {
+				c.Locals("role", tt.role)
+				return c.Next()
+			}, handler.Permissions)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/auth/permissions", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+			var respBody response.Response
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+			data := respBody.Data.(map[string]interface{})
+			assert.Equal(t, tt.role, data["role"])
+
+			perms, _ := data["permissions"].([]interface{})
+			for _, p := range tt.contains {
+				assert.Contains(t, perms, p)
+			}
+			for _, p := range tt.notContains {
+				assert.NotContains(t, perms, p)
+			}
+		})
+	}
+}
+
+// TestAuthHandler_Login_EmailNotVerified tests that unverified accounts get a distinct 403
+func TestAuthHandler_Login_EmailNotVerified(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, new(MockUserService))
+	app := setupAuthTestApp(handler)
+
+	mockService.On("Login", mock.Anything, mock.AnythingOfType("*service.LoginInput")).Return(nil, service.ErrEmailNotVerified)
+
+	body, _ := json.Marshal(map[string]string{
+		"email":    "test@example.com",
+		"password": "password123",
+	})
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	var respBody response.Response
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	assert.Equal(t, "Email address has not been verified", respBody.Error)
+}
+
+// TestAuthHandler_Login_PendingApproval tests that accounts awaiting approval cannot log in
+func TestAuthHandler_Login_PendingApproval(t *testing.T) {
+	mockService := new(MockAuthService)
+	handler := NewAuthHandler(mockService, new(MockUserService))
+	app := setupAuthTestApp(handler)
+
+	mockService.On("Login", mock.Anything, mock.AnythingOfType("*service.LoginInput")).Return(nil, service.ErrPendingApproval)
+
+	body, _ := json.Marshal(map[string]string{
+		"email":    "test@example.com",
+		"password": "password123",
+	})
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
 
-				// Verify all three context fields are present
-				_, hasUserID := data["user_id"]
-				_, hasEmail := data["email"]
-				_, hasRole := data["role"]
+	var respBody response.Response
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	assert.Equal(t, "Account is pending approval", respBody.Error)
+}
 
-				assert.True(t, hasUserID, "Response should include user_id")
-				assert.True(t, hasEmail, "Response should include email")
-				assert.True(t, hasRole, "Response should include role")
+// TestAuthHandler_Register covers self-signup through /auth/register
+func TestAuthHandler_Register(t *testing.T) {
+	active := &service.UserResponse{ID: "user-uuid", Name: "Test User", Email: "test@example.com", Role: "user", IsActive: true}
+	unverified := &service.UserResponse{ID: "user-uuid", Name: "Test User", Email: "test@example.com", Role: "user"}
 
-				// Verify the values
-				assert.Equal(t, "admin-uuid-456", data["user_id"])
-				assert.Equal(t, "admin@example.com", data["email"])
-				assert.Equal(t, "admin", data["role"])
+	tests := []struct {
+		name           string
+		setupMocks     func(auth *MockAuthService, users *MockUserService)
+		expectedStatus int
+		expectedCode   string
+		expectToken    bool
+	}{
+		{
+			name: "active account is signed in",
+			setupMocks: func(auth *MockAuthService, users *MockUserService) {
+				users.On("Register", mock.Anything, mock.AnythingOfType("*service.CreateUserInput")).Return(active, nil)
+				auth.On("StartSession", mock.Anything, "user-uuid", mock.Anything, "test-agent").
+					Return(&service.AuthResponse{Token: "jwt-token-here", User: active, SessionID: "session-1"}, nil)
+			},
+			expectedStatus: fiber.StatusCreated,
+			expectToken:    true,
+		},
+		{
+			name: "account awaiting verification gets no token",
+			setupMocks: func(auth *MockAuthService, users *MockUserService) {
+				users.On("Register", mock.Anything, mock.AnythingOfType("*service.CreateUserInput")).Return(unverified, nil)
+			},
+			expectedStatus: fiber.StatusCreated,
+		},
+		{
+			name: "duplicate email",
+			setupMocks: func(auth *MockAuthService, users *MockUserService) {
+				users.On("Register", mock.Anything, mock.AnythingOfType("*service.CreateUserInput")).Return(nil, service.ErrEmailAlreadyExists)
 			},
+			expectedStatus: fiber.StatusBadRequest,
+			expectedCode:   response.CodeEmailExists,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Setup app with context
-			mockService := new(MockAuthService)
-			handler := NewAuthHandler(mockService)
-			baseApp := setupAuthTestApp(handler)
-			app := tt.setupContext(baseApp)
+			authService := new(MockAuthService)
+			userService := new(MockUserService)
+			tt.setupMocks(authService, userService)
+			app := setupAuthTestApp(NewAuthHandler(authService, userService))
+
+			body, _ := json.Marshal(map[string]string{
+				"name":     "Test User",
+				"email":    "test@example.com",
+				"password": "Password123!",
+			})
+			req := httptest.NewRequest("POST", "/auth/register", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("User-Agent", "test-agent")
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var respBody struct {
+				response.Response
+				Data *service.AuthResponse `json:"data"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+			assert.Equal(t, tt.expectedCode, respBody.Code)
+			if tt.expectedStatus == fiber.StatusCreated {
+				assert.Equal(t, "user-uuid", respBody.Data.User.ID)
+				assert.Equal(t, tt.expectToken, respBody.Data.Token != "")
+			}
+
+			authService.AssertExpectations(t)
+			userService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestAuthHandler_Register_ValidateOnly tests that a signup dry run reports validity without creating the account
+func TestAuthHandler_Register_AnonymousViewIsSafeListed(t *testing.T) {
+	pending := &service.UserResponse{ID: "user-uuid", Name: "Test User", Email: "test@example.com", Role: "user", ApprovalStatus: "pending_approval"}
+
+	tests := []struct {
+		name         string
+		opts         []AuthHandlerOption
+		expectedKeys []string
+	}{
+		{name: "default safe-list", expectedKeys: []string{"id", "name", "email"}},
+		{name: "custom safe-list", opts: []AuthHandlerOption{WithPublicUserFields("id")}, expectedKeys: []string{"id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userService := new(MockUserService)
+			userService.On("Register", mock.Anything, mock.AnythingOfType("*service.CreateUserInput")).Return(pending, nil)
+			app := setupAuthTestApp(NewAuthHandler(new(MockAuthService), userService, tt.opts...))
+
+			body, _ := json.Marshal(map[string]string{
+				"name":     "Test User",
+				"email":    "test@example.com",
+				"password": "Password123!",
+			})
+			req := httptest.NewRequest("POST", "/auth/register", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+			var respBody struct {
+				Data struct {
+					User  map[string]interface{} `json:"user"`
+					Token string                 `json:"token"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+			assert.Empty(t, respBody.Data.Token)
+			keys := make([]string, 0, len(respBody.Data.User))
+			for k := range respBody.Data.User {
+				keys = append(keys, k)
+			}
+			assert.ElementsMatch(t, tt.expectedKeys, keys)
+		})
+	}
+}
+
+func TestAuthHandler_Register_ValidateOnly(t *testing.T) {
+	tests := []struct {
+		name           string
+		checkErr       error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "available email", expectedStatus: fiber.StatusOK},
+		{name: "taken email", checkErr: service.ErrEmailAlreadyExists, expectedStatus: fiber.StatusBadRequest, expectedCode: response.CodeEmailExists},
+	}
 
-			// Create request
-			req := httptest.NewRequest("GET", "/auth/me", nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authService := new(MockAuthService)
+			userService := new(MockUserService)
+			userService.On("CheckEmailAvailable", mock.Anything, "test@example.com").Return(tt.checkErr)
+			app := setupAuthTestApp(NewAuthHandler(authService, userService))
+
+			body, _ := json.Marshal(map[string]string{
+				"name":     "Test User",
+				"email":    "test@example.com",
+				"password": "Password123!",
+			})
+			req := httptest.NewRequest("POST", "/auth/register?validate_only=true", bytes.NewReader(body))
 			req.Header.Set("Content-Type", "application/json")
 
-			// Execute request
 			resp, err := app.Test(req)
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
 
-			// Parse response
 			var respBody response.Response
-			err = json.NewDecoder(resp.Body).Decode(&respBody)
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+			assert.Equal(t, tt.expectedCode, respBody.Code)
+
+			userService.AssertNotCalled(t, "Register", mock.Anything, mock.Anything)
+			authService.AssertNotCalled(t, "StartSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// sessionTestApp routes the session endpoints as the caller with the given session
+func sessionTestApp(handler *AuthHandler) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "user-uuid")
+		c.Locals("session_id", "session-2")
+		return c.Next()
+	})
+	app.Get("/auth/sessions", handler.Sessions)
+	app.Delete("/auth/sessions/:id", handler.RevokeSession)
+	return app
+}
+
+// TestAuthHandler_Sessions tests listing the caller's sessions with the current one marked
+func TestAuthHandler_Sessions(t *testing.T) {
+	mockService := new(MockAuthService)
+	app := sessionTestApp(NewAuthHandler(mockService, new(MockUserService)))
+
+	mockService.On("ListSessions", mock.Anything, "user-uuid").Return([]service.SessionResponse{
+		{ID: "session-1"},
+		{ID: "session-2"},
+	}, nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/auth/sessions", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var respBody struct {
+		response.Response
+		Data []service.SessionResponse `json:"data"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	assert.Len(t, respBody.Data, 2)
+	assert.False(t, respBody.Data[0].Current)
+	assert.True(t, respBody.Data[1].Current)
+	mockService.AssertExpectations(t)
+}
+
+// TestAuthHandler_RevokeSession tests revoking a session and the error mapping
+func TestAuthHandler_RevokeSession(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "revoked", expectedStatus: fiber.StatusNoContent},
+		{name: "not found", serviceErr: service.ErrSessionNotFound, expectedStatus: fiber.StatusNotFound, expectedCode: response.CodeSessionNotFound},
+		{name: "sessions disabled", serviceErr: service.ErrSessionsDisabled, expectedStatus: fiber.StatusNotImplemented, expectedCode: "NOT_IMPLEMENTED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAuthService)
+			app := sessionTestApp(NewAuthHandler(mockService, new(MockUserService)))
+
+			mockService.On("RevokeSession", mock.Anything, "user-uuid", "session-1").Return(tt.serviceErr)
+
+			resp, err := app.Test(httptest.NewRequest("DELETE", "/auth/sessions/session-1", nil))
 			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
 
-			// Check response
-			if tt.checkResponse != nil {
-				tt.checkResponse(t, respBody)
+			if tt.expectedCode != "" {
+				var respBody response.Response
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+				assert.Equal(t, tt.expectedCode, respBody.Code)
 			}
+			mockService.AssertExpectations(t)
 		})
 	}
 }
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/ariam/my-api/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrInputRejected is returned by BindAndValidate once it has answered the
+// request with 400 or 422. Handlers return handled(err), which turns it into
+// nil, so the error handler doesn't render a second response.
+var ErrInputRejected = errors.New("request input rejected")
+
+// BindAndValidate parses the request body into a new T and validates it,
+// answering 400 for an unparseable body and 422 for validation errors.
+func BindAndValidate[T any](c *fiber.Ctx) (*T, error) {
+	return bindAndValidate[T](c, c.BodyParser)
+}
+
+// BindAndValidateStrict is BindAndValidate with parseStrict, so unknown
+// JSON fields are rejected with the same 422 RequestSchema gives them.
+func BindAndValidateStrict[T any](c *fiber.Ctx) (*T, error) {
+	return bindAndValidate[T](c, func(out interface{}) error { return parseStrict(c, out) })
+}
+
+func bindAndValidate[T any](c *fiber.Ctx, parse func(out interface{}) error) (*T, error) {
+	input := new(T)
+	if err := parse(input); err != nil {
+		return nil, rejectInput(invalidBody(c, err))
+	}
+	if errs := validator.Validate(input); len(errs) > 0 {
+		return nil, rejectInput(validationError(c, errs))
+	}
+	return input, nil
+}
+
+// rejectInput reports ErrInputRejected, or the error from writing the
+// response if that failed.
+func rejectInput(writeErr error) error {
+	if writeErr != nil {
+		return writeErr
+	}
+	return ErrInputRejected
+}
+
+// handled returns nil for ErrInputRejected, whose response has already been
+// written, and err otherwise.
+func handled(err error) error {
+	if errors.Is(err, ErrInputRejected) {
+		return nil
+	}
+	return err
+}
+
+// unknownFieldError is returned by parseStrict for a JSON field the target
+// struct doesn't declare.
+type unknownFieldError struct {
+	field string
+}
+
+func (e *unknownFieldError) Error() string {
+	return "unknown field: " + e.field
+}
+
+// parseStrict is c.BodyParser for handlers that opt in to rejecting unknown
+// fields, so a typo such as "emai" is reported as such rather than as a
+// missing email. Non-JSON bodies are parsed by c.BodyParser as before.
+func parseStrict(c *fiber.Ctx, out interface{}) error {
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+		return c.BodyParser(out)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(c.Body()))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(out); err != nil {
+		// encoding/json has no typed error for this case
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			if unquoted, uerr := strconv.Unquote(field); uerr == nil {
+				field = unquoted
+			}
+			return &unknownFieldError{field: field}
+		}
+		return err
+	}
+	return nil
+}
+
+// invalidBody responds to a parse error with 400, or with a 422 validation
+// error naming the field when parseStrict found an unknown one.
+func invalidBody(c *fiber.Ctx, err error) error {
+	var unknown *unknownFieldError
+	if errors.As(err, &unknown) {
+		return validationError(c, []validator.ErrorResponse{validator.UnknownField(unknown.field)})
+	}
+	return response.BadRequest(c, "Invalid request body")
+}
\ No newline at end of file
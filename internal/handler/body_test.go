@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ariam/my-api/internal/middleware"
+	"github.com/ariam/my-api/pkg/openapi"
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/ariam/my-api/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindAndValidate(t *testing.T) {
+	type input struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	tests := []struct {
+		name           string
+		bind           func(c *fiber.Ctx) (*input, error)
+		body           string
+		expectedStatus int
+	}{
+		{name: "valid body", bind: BindAndValidate[input], body: `{"email":"john@example.com"}`, expectedStatus: fiber.StatusOK},
+		{name: "malformed JSON", bind: BindAndValidate[input], body: `{"email":`, expectedStatus: fiber.StatusBadRequest},
+		{name: "failed validation", bind: BindAndValidate[input], body: `{"email":"not-an-email"}`, expectedStatus: fiber.StatusUnprocessableEntity},
+		{name: "unknown field ignored", bind: BindAndValidate[input], body: `{"email":"john@example.com","emai":"x"}`, expectedStatus: fiber.StatusOK},
+		{name: "unknown field rejected when strict", bind: BindAndValidateStrict[input], body: `{"email":"john@example.com","emai":"x"}`, expectedStatus: fiber.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the production error handler would turn a leaked sentinel into a 500
+			app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler("production")})
+			app.Post("/", func(c *fiber.Ctx) error {
+				in, err := tt.bind(c)
+				if err != nil {
+					return handled(err)
+				}
+				return c.SendString(in.Email)
+			})
+
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestBindAndValidateStrict_UnknownField(t *testing.T) {
+	type input struct {
+		Name  string `json:"name"`
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "extra field", body: `{"email":"john@example.com","emai":"x"}`},
+		// reported as the typo rather than as a missing email
+		{name: "misspelled field", body: `{"emai":"john@example.com"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/", func(c *fiber.Ctx) error {
+				_, err := BindAndValidateStrict[input](c)
+				return handled(err)
+			})
+
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+			var body struct {
+				Code  string                    `json:"code"`
+				Error []validator.ErrorResponse `json:"error"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, response.CodeValidationFailed, body.Code)
+			assert.Equal(t, []validator.ErrorResponse{{Field: "emai", Tag: "unknown", Message: "emai is not allowed"}}, body.Error)
+		})
+	}
+}
+
+func TestBindAndValidateStrict_MatchesRequestSchema(t *testing.T) {
+	type input struct {
+		Email string `json:"email"`
+	}
+	spec, err := openapi.Parse([]byte(`{
+		"paths": {"/schema": {"post": {"parameters": [{"in": "body", "schema": {"$ref": "#/definitions/Input"}}]}}},
+		"definitions": {"Input": {"type": "object", "properties": {"email": {"type": "string"}}}}
+	}`))
+	require.NoError(t, err)
+
+	app := fiber.New()
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+	app.Post("/schema", middleware.RequestSchema(spec), ok)
+	app.Post("/strict", func(c *fiber.Ctx) error {
+		_, err := BindAndValidateStrict[input](c)
+		return handled(err)
+	})
+
+	post := func(path string) (int, string) {
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"emai":"john@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	schemaStatus, schemaBody := post("/schema")
+	strictStatus, strictBody := post("/strict")
+	assert.Equal(t, fiber.StatusUnprocessableEntity, schemaStatus)
+	assert.Equal(t, schemaStatus, strictStatus)
+	assert.JSONEq(t, schemaBody, strictBody)
+}
\ No newline at end of file
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/ariam/my-api/internal/service"
+	"github.com/gofiber/fiber/v2"
+)
+
+// requestContext is the context handlers pass to services. It carries the
+// authenticated user, when there is one, as the actor for audit entries.
+func requestContext(c *fiber.Ctx) context.Context {
+	ctx := c.UserContext()
+	if userID, ok := c.Locals("user_id").(string); ok && userID != "" {
+		ctx = service.ContextWithActor(ctx, userID)
+	}
+	return ctx
+}
\ No newline at end of file
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/ariam/my-api/internal/event"
+	"github.com/ariam/my-api/internal/service"
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// eventBufferSize is how many events may queue for a slow client before
+	// further events are dropped for it.
+	eventBufferSize = 64
+	pingInterval    = 30 * time.Second
+	pongWait        = 2 * pingInterval
+	writeWait       = 10 * time.Second
+)
+
+// eventMessage is the frame sent to WebSocket clients for each event.
+type eventMessage struct {
+	Type string      `json:"type"`
+	Data event.Event `json:"data"`
+}
+
+type EventHandler struct {
+	bus     *event.Bus
+	upgrade fiber.Handler
+}
+
+func NewEventHandler(bus *event.Bus) *EventHandler {
+	h := &EventHandler{bus: bus}
+	h.upgrade = websocket.New(h.streamUserEvents)
+	return h
+}
+
+// UserEvents godoc
+// @Summary Stream user events
+// @Description WebSocket stream of user.created, user.updated and user.deleted events for users of the admin's tenant (admin only). Authenticate with the token query parameter.
+// @Tags Events
+// @Param token query string true "JWT access token"
+// @Success 101
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 426 {object} response.Response
+// @Router /ws/users [get]
+func (h *EventHandler) UserEvents(c *fiber.Ctx) error {
+	return h.upgrade(c)
+}
+
+// eventUser returns the user a user lifecycle event is about, or nil for any
+// other event.
+func eventUser(e event.Event) *service.UserResponse {
+	switch e := e.(type) {
+	case service.UserCreated:
+		return e.User
+	case service.UserUpdated:
+		return e.User
+	case service.UserDeleted:
+		return e.User
+	}
+	return nil
+}
+
+func (h *EventHandler) streamUserEvents(conn *websocket.Conn) {
+	// admins only see users of their own tenant, as on the REST endpoints
+	tenantID, _ := conn.Locals("tenant_id").(string)
+
+	events := make(chan event.Event, eventBufferSize)
+	unsubscribe := h.bus.Subscribe(event.All, func(e event.Event) {
+		user := eventUser(e)
+		if user == nil || user.TenantID != tenantID {
+			return
+		}
+		select {
+		case events <- e:
+		default:
+			// a stalled client misses events rather than backing up the bus
+		}
+	})
+	defer unsubscribe()
+
+	// clients only send pongs and close frames; reading is what processes
+	// them and notices when the client goes away
+	closed := make(chan struct{})
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e := <-events:
+			_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteJSON(eventMessage{Type: e.Type(), Data: e}); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/internal/event"
+	"github.com/ariam/my-api/internal/middleware"
+	"github.com/ariam/my-api/internal/service"
+	"github.com/ariam/my-api/pkg/jwt"
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startEventServer serves the user event stream on a real listener, since
+// app.Test cannot upgrade connections.
+func startEventServer(t *testing.T, bus *event.Bus, jwtManager *jwt.JWTManager) string {
+	t.Helper()
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/ws/users", middleware.WebSocketAuth(jwtManager, nil), middleware.RoleRequired("admin"), NewEventHandler(bus).UserEvents)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = app.Listener(ln) }()
+	t.Cleanup(func() { _ = app.Shutdown() })
+
+	return "ws://" + ln.Addr().String() + "/ws/users"
+}
+
+func TestEventHandler_UserEvents(t *testing.T) {
+	bus := event.NewBus()
+	jwtManager := jwt.NewJWTManager("test-secret-key-min-32-characters", 1)
+	url := startEventServer(t, bus, jwtManager)
+
+	token, err := jwtManager.Generate("admin-uuid", "admin@example.com", "admin")
+	require.NoError(t, err)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?token="+token, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// the server subscribes just after the handshake, so keep publishing
+	// until the client sees an event
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				bus.Publish(service.UserCreated{User: &service.UserResponse{ID: "user-uuid", Email: "john@example.com"}})
+			}
+		}
+	}()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var received struct {
+		Type string              `json:"type"`
+		Data service.UserCreated `json:"data"`
+	}
+	require.NoError(t, conn.ReadJSON(&received))
+
+	assert.Equal(t, service.EventUserCreated, received.Type)
+	assert.Equal(t, "user-uuid", received.Data.User.ID)
+
+	// a clean close from the client ends the stream without error
+	assert.NoError(t, conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")))
+}
+
+func TestEventHandler_UserEvents_TenantScoped(t *testing.T) {
+	bus := event.NewBus()
+	jwtManager := jwt.NewJWTManager("test-secret-key-min-32-characters", 1)
+	url := startEventServer(t, bus, jwtManager)
+
+	token, err := jwtManager.Generate("admin-uuid", "admin@example.com", "admin", map[string]interface{}{"tenant_id": "tenant-a"})
+	require.NoError(t, err)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?token="+token, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				// the other tenant's event is always published first
+				bus.Publish(service.UserCreated{User: &service.UserResponse{ID: "other-uuid", TenantID: "tenant-b"}})
+				bus.Publish(service.UserCreated{User: &service.UserResponse{ID: "own-uuid", TenantID: "tenant-a"}})
+			}
+		}
+	}()
+
+	for range 3 {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		var received struct {
+			Data service.UserCreated `json:"data"`
+		}
+		require.NoError(t, conn.ReadJSON(&received))
+		assert.Equal(t, "own-uuid", received.Data.User.ID)
+	}
+}
+
+func TestEventHandler_UserEvents_RequiresAdminToken(t *testing.T) {
+	bus := event.NewBus()
+	jwtManager := jwt.NewJWTManager("test-secret-key-min-32-characters", 1)
+	url := startEventServer(t, bus, jwtManager)
+
+	userToken, err := jwtManager.Generate("user-uuid", "john@example.com", "user")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{name: "missing token", query: "", expectedStatus: fiber.StatusUnauthorized},
+		{name: "invalid token", query: "?token=not-a-jwt", expectedStatus: fiber.StatusUnauthorized},
+		{name: "non-admin token", query: "?token=" + userToken, expectedStatus: fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, resp, err := websocket.DefaultDialer.Dial(url+tt.query, nil)
+			require.ErrorIs(t, err, websocket.ErrBadHandshake)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
\ No newline at end of file
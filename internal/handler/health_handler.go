@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// HealthChecker is a dependency the app needs to serve traffic, such as the
+// database or Redis. Check returns nil when the dependency is reachable.
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// HealthCheck adapts a function to a HealthChecker called name.
+func HealthCheck(name string, check func(ctx context.Context) error) HealthChecker {
+	return healthCheck{name: name, check: check}
+}
+
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func (h healthCheck) Name() string { return h.name }
+
+func (h healthCheck) Check(ctx context.Context) error { return h.check(ctx) }
+
+// ReadinessData reports the status of each dependency, keyed by checker
+// name, as "ok" or "error".
+type ReadinessData struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+type HealthHandler struct {
+	checkers []HealthChecker
+	timeout  time.Duration
+}
+
+// NewHealthHandler returns a handler that runs checkers on every readiness
+// probe, each bounded by timeout.
+func NewHealthHandler(timeout time.Duration, checkers ...HealthChecker) *HealthHandler {
+	return &HealthHandler{checkers: checkers, timeout: timeout}
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Checks every dependency in parallel and reports each one's status. Failure details are logged, not returned.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} response.Response{data=ReadinessData}
+// @Failure 503 {object} response.Response{data=ReadinessData}
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	data := ReadinessData{Status: "ok", Checks: make(map[string]string, len(h.checkers))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, checker := range h.checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, h.timeout)
+			defer cancel()
+
+			status := "ok"
+			if err := checker.Check(checkCtx); err != nil {
+				logger.WithContext(ctx).Warn("Health check failed", zap.String("dependency", checker.Name()), zap.Error(err))
+				status = "error"
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			data.Checks[checker.Name()] = status
+			if status != "ok" {
+				data.Status = "unavailable"
+			}
+		}()
+	}
+	wg.Wait()
+
+	if data.Status != "ok" {
+		return response.ErrorWithData(c, fiber.StatusServiceUnavailable, "Service not ready", data)
+	}
+	return response.Success(c, data)
+}
\ No newline at end of file
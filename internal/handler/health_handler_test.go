@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthHandler_Ready(t *testing.T) {
+	ok := HealthCheck("database", func(context.Context) error { return nil })
+	failing := HealthCheck("redis", func(context.Context) error { return errors.New("connection refused") })
+	slow := HealthCheck("mailer", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	tests := []struct {
+		name           string
+		checkers       []HealthChecker
+		expectedStatus int
+		expectedChecks map[string]interface{}
+	}{
+		{
+			name:           "all dependencies up",
+			checkers:       []HealthChecker{ok},
+			expectedStatus: fiber.StatusOK,
+			expectedChecks: map[string]interface{}{"database": "ok"},
+		},
+		{
+			name:           "failing dependency",
+			checkers:       []HealthChecker{ok, failing},
+			expectedStatus: fiber.StatusServiceUnavailable,
+			expectedChecks: map[string]interface{}{"database": "ok", "redis": "error"},
+		},
+		{
+			name:           "dependency exceeding the timeout",
+			checkers:       []HealthChecker{ok, slow},
+			expectedStatus: fiber.StatusServiceUnavailable,
+			expectedChecks: map[string]interface{}{"database": "ok", "mailer": "error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/health/ready", NewHealthHandler(50*time.Millisecond, tt.checkers...).Ready)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/health/ready", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var respBody response.Response
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+			assert.Equal(t, tt.expectedStatus == fiber.StatusOK, respBody.Success)
+			data, ok := respBody.Data.(map[string]interface{})
+			assert.True(t, ok, "Data should be a map")
+			assert.Equal(t, tt.expectedChecks, data["checks"])
+		})
+	}
+}
\ No newline at end of file
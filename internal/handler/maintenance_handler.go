@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// MaintenanceSwitch reads and changes the maintenance mode enforced by the
+// middleware.MaintenanceMode middleware.
+type MaintenanceSwitch interface {
+	Mode() string
+	SetMode(mode string) error
+}
+
+// MaintenanceData is the current maintenance mode: off, read_only or full.
+type MaintenanceData struct {
+	Mode string `json:"mode" example:"read_only"`
+}
+
+type MaintenanceHandler struct {
+	maintenance MaintenanceSwitch
+}
+
+func NewMaintenanceHandler(maintenance MaintenanceSwitch) *MaintenanceHandler {
+	return &MaintenanceHandler{maintenance: maintenance}
+}
+
+// Get godoc
+// @Summary Get maintenance mode
+// @Description Report whether the instance serving the request is serving normally, read-only or down for maintenance (admin only). The mode is held per process, so instances behind a load balancer may differ.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=MaintenanceData}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /admin/maintenance [get]
+func (h *MaintenanceHandler) Get(c *fiber.Ctx) error {
+	return response.Success(c, MaintenanceData{Mode: h.maintenance.Mode()})
+}
+
+// Set godoc
+// @Summary Set maintenance mode
+// @Description Switch the instance serving the request to off, read_only (writes get 503) or full (all requests get 503). The mode is held per process and is not shared: with several instances, each must be switched, and a restart reverts to MAINTENANCE_MODE. /health, login and this endpoint are always served (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MaintenanceData true "New mode"
+// @Success 200 {object} response.Response{data=MaintenanceData}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /admin/maintenance [put]
+func (h *MaintenanceHandler) Set(c *fiber.Ctx) error {
+	var input MaintenanceData
+	if err := c.BodyParser(&input); err != nil {
+		return response.BadRequest(c, "Invalid request body")
+	}
+	if err := h.maintenance.SetMode(input.Mode); err != nil {
+		return response.BadRequest(c, err.Error())
+	}
+
+	userID, _ := c.Locals("user_id").(string)
+	logger.WithContext(requestContext(c)).Info("Maintenance mode changed",
+		zap.String("mode", input.Mode),
+		zap.String("user_id", userID),
+	)
+	return response.Success(c, MaintenanceData{Mode: h.maintenance.Mode()})
+}
\ No newline at end of file
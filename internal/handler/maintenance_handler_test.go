@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ariam/my-api/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceHandler_Set(t *testing.T) {
+	maintenance := middleware.NewMaintenance(middleware.MaintenanceOff)
+	app := fiber.New()
+	app.Use(middleware.MaintenanceMode(maintenance, 0, "/maintenance"))
+	app.Put("/maintenance", NewMaintenanceHandler(maintenance).Set)
+	app.Post("/users", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+
+	put := func(body string) int {
+		req := httptest.NewRequest("PUT", "/maintenance", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+	post := func() int {
+		resp, err := app.Test(httptest.NewRequest("POST", "/users", nil))
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusBadRequest, put(`{"mode":"sideways"}`))
+	assert.Equal(t, fiber.StatusOK, put(`{"mode":"full"}`))
+	assert.Equal(t, fiber.StatusServiceUnavailable, post())
+	assert.Equal(t, fiber.StatusOK, put(`{"mode":"off"}`), "the switch stays reachable in full maintenance")
+	assert.Equal(t, fiber.StatusCreated, post())
+}
\ No newline at end of file
@@ -1,74 +1,243 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
+	"io"
 	"strconv"
+	"strings"
 
+	"github.com/ariam/my-api/internal/authz"
+	"github.com/ariam/my-api/internal/model"
 	"github.com/ariam/my-api/internal/service"
 	"github.com/ariam/my-api/pkg/response"
 	"github.com/ariam/my-api/pkg/validator"
 	"github.com/gofiber/fiber/v2"
 )
 
+// selectableUserFields whitelists the service.UserResponse fields ?fields=
+// may select.
+var selectableUserFields = map[string]bool{
+	"id":              true,
+	"tenant_id":       true,
+	"name":            true,
+	"email":           true,
+	"role":            true,
+	"is_active":       true,
+	"approval_status": true,
+	"version":         true,
+	"created_at":      true,
+	"updated_at":      true,
+}
+
+// userSortColumns whitelists the columns GET /users may be ordered by.
+var userSortColumns = map[string]bool{
+	"name":       true,
+	"email":      true,
+	"created_at": true,
+}
+
 type UserHandler struct {
 	userService service.UserService
+	pageLimits  service.PageLimits
 }
 
-func NewUserHandler(userService service.UserService) *UserHandler {
-	return &UserHandler{userService: userService}
+type UserHandlerOption func(*UserHandler)
+
+// WithPageLimits bounds the per_page and limit query parameters.
+func WithPageLimits(limits service.PageLimits) UserHandlerOption {
+	return func(h *UserHandler) {
+		h.pageLimits = limits
+	}
+}
+
+func NewUserHandler(userService service.UserService, opts ...UserHandlerOption) *UserHandler {
+	h := &UserHandler{userService: userService}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Create godoc
 // @Summary Create new user
-// @Description Register a new user
+// @Description Create a user on someone's behalf (admin only). The account is active straight away; email verification and approval apply only to self-signup through /auth/register. With validate_only=true the payload is checked, including email uniqueness, but nothing is saved.
 // @Tags Users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param request body service.CreateUserInput true "User data"
+// @Param validate_only query bool false "Validate the payload without creating the user"
+// @Param Idempotency-Key header string false "Replays the first response for repeats of this key"
+// @Success 200 {object} response.Response "validate_only: the payload is valid"
 // @Success 201 {object} response.Response{data=service.UserResponse}
+// @Header 201 {string} Location "URL of the created user"
 // @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Failure 422 {object} response.Response
 // @Router /users [post]
 func (h *UserHandler) Create(c *fiber.Ctx) error {
-	var input service.CreateUserInput
-
-	if err := c.BodyParser(&input); err != nil {
-		return response.BadRequest(c, "Invalid request body")
+	input, err := BindAndValidateStrict[service.CreateUserInput](c)
+	if err != nil {
+		return handled(err)
 	}
 
-	if errs := validator.Validate(&input); len(errs) > 0 {
-		return response.ValidationError(c, errs)
+	if c.QueryBool("validate_only") {
+		return validateNewUser(c, h.userService, input)
 	}
 
-	user, err := h.userService.Create(c.Context(), &input)
+	user, err := h.userService.Create(requestContext(c), input)
 	if err != nil {
 		if errors.Is(err, service.ErrEmailAlreadyExists) {
-			return response.BadRequest(c, err.Error())
+			return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeEmailExists, err.Error())
+		}
+		if errors.Is(err, service.ErrEmailDeletedAccount) {
+			return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeEmailDeletedAccount, err.Error())
 		}
 		return response.InternalServerError(c, "Failed to create user")
 	}
 
-	return response.Created(c, user)
+	return response.CreatedAt(c, userLocation(c, user.ID), user)
+}
+
+// validateNewUser finishes a validate_only Create or Register with the email
+// checks the service would make, answering with the error Create would give.
+func validateNewUser(c *fiber.Ctx, users service.UserService, input *service.CreateUserInput) error {
+	err := users.CheckEmailAvailable(requestContext(c), input.Email)
+	switch {
+	case err == nil:
+		return response.Success(c, fiber.Map{"valid": true})
+	case errors.Is(err, service.ErrEmailAlreadyExists):
+		return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeEmailExists, err.Error())
+	case errors.Is(err, service.ErrEmailDeletedAccount):
+		return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeEmailDeletedAccount, err.Error())
+	default:
+		return response.InternalServerError(c, "Failed to validate user")
+	}
+}
+
+// BulkCreate godoc
+// @Summary Bulk import users
+// @Description Stream a JSON array of users and create them in batches (admin only)
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body []service.CreateUserInput true "Users to import"
+// @Success 200 {object} response.Response{data=service.BulkCreateResult}
+// @Failure 400 {object} response.Response{data=service.BulkCreateResult}
+// @Failure 413 {object} response.Response{data=service.BulkCreateResult}
+// @Router /users/bulk [post]
+func (h *UserHandler) BulkCreate(c *fiber.Ctx) error {
+	var body io.Reader = c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+
+	result, err := h.userService.BulkCreate(requestContext(c), body)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidBulkPayload) {
+			return response.ErrorCodeWithData(c, fiber.StatusBadRequest, response.CodeInvalidBulkPayload, err.Error(), result)
+		}
+		if errors.Is(err, service.ErrBulkImportTooLarge) {
+			return response.ErrorCodeWithData(c, fiber.StatusRequestEntityTooLarge, response.CodeBulkImportTooLarge, err.Error(), result)
+		}
+		return response.InternalServerError(c, "Failed to import users")
+	}
+
+	return response.Success(c, result)
 }
 
 // FindByID godoc
 // @Summary Get user by ID
-// @Description Get user details by ID
+// @Description Get user details by ID. Non-admins may only fetch their own record.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "User ID"
+// @Param fields query string false "Comma-separated fields to return, e.g. id,name"
 // @Success 200 {object} response.Response{data=service.UserResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Router /users/{id} [get]
 func (h *UserHandler) FindByID(c *fiber.Ctx) error {
-	id := c.Params("id")
+	id, ok := userIDParam(c)
+	if !ok {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+	fields, unknown := userFieldsQuery(c)
+	if unknown != "" {
+		return response.BadRequest(c, "Unknown field: "+unknown)
+	}
+
+	user, err := h.userService.FindByID(requestContext(c), id)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return response.ErrorCode(c, fiber.StatusNotFound, response.CodeUserNotFound, err.Error())
+		}
+		return response.InternalServerError(c, "Failed to fetch user")
+	}
+
+	if fields == nil {
+		return response.Success(c, user)
+	}
+	view, err := response.Project(user, fields)
+	if err != nil {
+		return response.InternalServerError(c, "Failed to fetch user")
+	}
+	return response.Success(c, view)
+}
 
-	user, err := h.userService.FindByID(c.Context(), id)
+// Exists godoc
+// @Summary Check that a user exists
+// @Description Answer 200 with no body when the user exists and 404 otherwise, for existence probes that don't need the record. Non-admins may only check their own record.
+// @Tags Users
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200
+// @Failure 400
+// @Failure 403
+// @Failure 404
+// @Router /users/{id} [head]
+func (h *UserHandler) Exists(c *fiber.Ctx) error {
+	id, ok := userIDParam(c)
+	if !ok {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+
+	if _, err := h.userService.FindByID(requestContext(c), id); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	}
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// Me godoc
+// @Summary Get own user
+// @Description Get the authenticated user's full record without knowing its ID
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=service.UserResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /users/me [get]
+func (h *UserHandler) Me(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	user, err := h.userService.FindByID(requestContext(c), userID)
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
-			return response.NotFound(c, err.Error())
+			return response.ErrorCode(c, fiber.StatusNotFound, response.CodeUserNotFound, err.Error())
 		}
 		return response.InternalServerError(c, "Failed to fetch user")
 	}
@@ -78,70 +247,422 @@ func (h *UserHandler) FindByID(c *fiber.Ctx) error {
 
 // FindAll godoc
 // @Summary Get all users
-// @Description Get paginated list of users
+// @Description Get paginated list of users. Passing cursor or limit switches to cursor pagination, which always orders newest first and returns response.CursorData.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param per_page query int false "Items per page" default(10)
+// @Param cursor query string false "Opaque cursor from a previous next_cursor"
+// @Param limit query int false "Items per page in cursor mode" default(10)
+// @Param role query string false "Filter by role"
+// @Param is_active query bool false "Filter by active status"
+// @Param q query string false "Search name or email"
+// @Param sort_by query string false "Sort column" Enums(name, email, created_at) default(created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc) default(desc)
+// @Param fields query string false "Comma-separated fields to return of each user, e.g. id,name"
 // @Success 200 {object} response.Response{data=response.PaginatedData}
+// @Header 200 {integer} X-Total-Count "Total number of items"
+// @Header 200 {integer} X-Page "Current page"
+// @Header 200 {integer} X-Per-Page "Items per page"
+// @Header 200 {integer} X-Total-Pages "Total number of pages"
+// @Failure 400 {object} response.Response
 // @Router /users [get]
 func (h *UserHandler) FindAll(c *fiber.Ctx) error {
+	filter, ok := userFilterQuery(c)
+	if !ok {
+		return response.BadRequest(c, "Invalid is_active value")
+	}
+	fields, unknown := userFieldsQuery(c)
+	if unknown != "" {
+		return response.BadRequest(c, "Unknown field: "+unknown)
+	}
+
+	if c.Query("cursor") != "" || c.Query("limit") != "" {
+		return h.findAfter(c, filter, fields)
+	}
+
 	page, _ := strconv.Atoi(c.Query("page", "1"))
-	perPage, _ := strconv.Atoi(c.Query("per_page", "10"))
+	perPage, _ := strconv.Atoi(c.Query("per_page"))
+	page, perPage = h.pageLimits.Normalize(page, perPage)
 
-	if page < 1 {
-		page = 1
+	sort := service.Sort{Column: c.Query("sort_by", "created_at")}
+	if !userSortColumns[sort.Column] {
+		return response.BadRequest(c, "Invalid sort_by value")
 	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 10
+	switch strings.ToLower(c.Query("order", "desc")) {
+	case "asc":
+	case "desc":
+		sort.Desc = true
+	default:
+		return response.BadRequest(c, "Invalid order value")
 	}
 
-	users, total, err := h.userService.FindAll(c.Context(), page, perPage)
+	users, total, err := h.userService.FindAll(requestContext(c), filter, sort, page, perPage)
 	if err != nil {
 		return response.InternalServerError(c, "Failed to fetch users")
 	}
 
-	return response.Paginated(c, users, total, page, perPage)
+	items, err := projectUsers(users, fields)
+	if err != nil {
+		return response.InternalServerError(c, "Failed to fetch users")
+	}
+	return response.PaginatedWithLinks(c, items, total, page, perPage)
+}
+
+// projectUsers limits each user to fields, or returns users as they are when
+// fields is nil.
+func projectUsers(users []service.UserResponse, fields []string) (interface{}, error) {
+	if fields == nil {
+		return users, nil
+	}
+	return response.ProjectEach(users, fields)
+}
+
+func (h *UserHandler) findAfter(c *fiber.Ctx, filter service.UserFilter, fields []string) error {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	limit = h.pageLimits.NormalizePerPage(limit)
+
+	users, next, err := h.userService.FindAfter(requestContext(c), filter, c.Query("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCursor) {
+			return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeInvalidCursor, "Invalid cursor")
+		}
+		return response.InternalServerError(c, "Failed to fetch users")
+	}
+
+	items, err := projectUsers(users, fields)
+	if err != nil {
+		return response.InternalServerError(c, "Failed to fetch users")
+	}
+	return response.CursorPaginated(c, items, next, limit)
+}
+
+// Search godoc
+// @Summary Search users
+// @Description Case-insensitive search of name and email. Users whose email matches q exactly are listed first, the rest by name.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search term"
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(10)
+// @Success 200 {object} response.Response{data=response.PaginatedData{items=[]service.UserResponse}}
+// @Header 200 {integer} X-Total-Count "Total number of items"
+// @Header 200 {integer} X-Page "Current page"
+// @Header 200 {integer} X-Per-Page "Items per page"
+// @Header 200 {integer} X-Total-Pages "Total number of pages"
+// @Failure 400 {object} response.Response
+// @Router /users/search [get]
+func (h *UserHandler) Search(c *fiber.Ctx) error {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		return response.BadRequest(c, "Search query is required")
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	perPage, _ := strconv.Atoi(c.Query("per_page"))
+	page, perPage = h.pageLimits.Normalize(page, perPage)
+
+	users, total, err := h.userService.Search(requestContext(c), query, page, perPage)
+	if err != nil {
+		return response.InternalServerError(c, "Failed to search users")
+	}
+
+	return response.PaginatedWithLinks(c, users, total, page, perPage)
+}
+
+// FindByEmail godoc
+// @Summary Get user by email
+// @Description Look up a user by email address (admin only)
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param email query string true "Email address"
+// @Success 200 {object} response.Response{data=service.UserResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /users/by-email [get]
+func (h *UserHandler) FindByEmail(c *fiber.Ctx) error {
+	email := strings.TrimSpace(c.Query("email"))
+	if errs := validator.ValidateVar(email, "required,email"); len(errs) > 0 {
+		return response.BadRequest(c, "A valid email is required")
+	}
+
+	user, err := h.userService.FindByEmail(requestContext(c), email)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return response.ErrorCode(c, fiber.StatusNotFound, response.CodeUserNotFound, err.Error())
+		}
+		return response.InternalServerError(c, "Failed to fetch user")
+	}
+
+	return response.Success(c, user)
+}
+
+// Export godoc
+// @Summary Export users
+// @Description Download every user matching the FindAll filters as CSV or as a JSON array
+// @Tags Users
+// @Produce text/csv
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "File format" Enums(csv, json) default(csv)
+// @Param role query string false "Filter by role"
+// @Param is_active query bool false "Filter by active status"
+// @Param q query string false "Search name or email"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /users/export [get]
+func (h *UserHandler) Export(c *fiber.Ctx) error {
+	filter, ok := userFilterQuery(c)
+	if !ok {
+		return response.BadRequest(c, "Invalid is_active value")
+	}
+
+	// the body is written after the handler returns, when the request context
+	// may already be cancelled by the timeout middleware
+	ctx := context.WithoutCancel(requestContext(c))
+
+	switch c.Query("format", "csv") {
+	case "csv":
+		return response.Download(c, "text/csv; charset=utf-8", "users.csv", func(w io.Writer) error {
+			return h.writeUsersCSV(ctx, w, filter)
+		})
+	case "json":
+		return response.Download(c, fiber.MIMEApplicationJSONCharsetUTF8, "users.json", func(w io.Writer) error {
+			return h.writeUsersJSON(ctx, w, filter)
+		})
+	default:
+		return response.BadRequest(c, "Invalid format value")
+	}
+}
+
+var userCSVHeader = []string{"id", "name", "email", "role", "is_active", "created_at"}
+
+func (h *UserHandler) writeUsersCSV(ctx context.Context, w io.Writer, filter service.UserFilter) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(userCSVHeader); err != nil {
+		return err
+	}
+
+	err := h.userService.ExportUsers(ctx, filter, func(users []service.UserResponse) error {
+		for _, u := range users {
+			if err := cw.Write([]string{u.ID, csvText(u.Name), csvText(u.Email), u.Role, strconv.FormatBool(u.IsActive), u.CreatedAt}); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvText keeps a user-supplied cell from being evaluated as a formula when
+// the export is opened in a spreadsheet, by prefixing the characters that
+// start one with a quote.
+func csvText(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+// writeUsersJSON streams a JSON array one element at a time.
+func (h *UserHandler) writeUsersJSON(ctx context.Context, w io.Writer, filter service.UserFilter) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	first := true
+	enc := json.NewEncoder(w)
+	err := h.userService.ExportUsers(ctx, filter, func(users []service.UserResponse) error {
+		for _, u := range users {
+			if !first {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			first = false
+			if err := enc.Encode(u); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]\n")
+	return err
 }
 
 // Update godoc
-// @Summary Update user
-// @Description Update user by ID
+// @Summary Replace user
+// @Description Replace a user's profile; every field is required. Non-admins may only update their own record.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "User ID"
-// @Param request body service.UpdateUserInput true "User data"
+// @Param request body service.UpdateUserInput true "Complete user profile"
+// @Param If-Match header string false "Version the client last read; a stale version fails with 409"
 // @Success 200 {object} response.Response{data=service.UserResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Failure 422 {object} response.Response
 // @Router /users/{id} [put]
 func (h *UserHandler) Update(c *fiber.Ctx) error {
-	id := c.Params("id")
+	id, ok := userIDParam(c)
+	if !ok {
+		return response.BadRequest(c, "Invalid user ID")
+	}
 
-	var input service.UpdateUserInput
-	if err := c.BodyParser(&input); err != nil {
-		return response.BadRequest(c, "Invalid request body")
+	input, err := BindAndValidateStrict[service.UpdateUserInput](c)
+	if err != nil {
+		return handled(err)
 	}
 
-	if errs := validator.Validate(&input); len(errs) > 0 {
-		return response.ValidationError(c, errs)
+	if !ifMatchVersion(c, &input.Version) {
+		return response.BadRequest(c, "Invalid If-Match header")
 	}
 
-	user, err := h.userService.Update(c.Context(), id, &input)
+	user, err := h.userService.Update(requestContext(c), id, input)
 	if err != nil {
-		if errors.Is(err, service.ErrUserNotFound) {
-			return response.NotFound(c, err.Error())
-		}
-		return response.InternalServerError(c, "Failed to update user")
+		return h.updateFailed(c, err)
+	}
+
+	return response.Success(c, user)
+}
+
+// Patch godoc
+// @Summary Partially update user
+// @Description Update only the fields present in the body; changing role requires admin. Non-admins may only update their own record.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body service.PatchUserInput true "Fields to change"
+// @Param If-Match header string false "Version the client last read; a stale version fails with 409"
+// @Success 200 {object} response.Response{data=service.UserResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /users/{id} [patch]
+func (h *UserHandler) Patch(c *fiber.Ctx) error {
+	id, ok := userIDParam(c)
+	if !ok {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+
+	input, err := BindAndValidateStrict[service.PatchUserInput](c)
+	if err != nil {
+		return handled(err)
+	}
+
+	role, _ := c.Locals("role").(string)
+	if input.Role != nil && !authz.HasRole(role, "admin") {
+		return response.Forbidden(c, "Only admins can change roles")
+	}
+
+	if !ifMatchVersion(c, &input.Version) {
+		return response.BadRequest(c, "Invalid If-Match header")
+	}
+
+	user, err := h.userService.Patch(requestContext(c), id, input)
+	if err != nil {
+		return h.updateFailed(c, err)
+	}
+
+	return response.Success(c, user)
+}
+
+// SetRole godoc
+// @Summary Change user role
+// @Description Assign a role to a user; the last admin cannot be demoted (admin only)
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body service.SetRoleInput true "New role (user or admin)"
+// @Success 200 {object} response.Response{data=service.UserResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /users/{id}/role [patch]
+func (h *UserHandler) SetRole(c *fiber.Ctx) error {
+	id, ok := userIDParam(c)
+	if !ok {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+
+	input, err := BindAndValidateStrict[service.SetRoleInput](c)
+	if err != nil {
+		return handled(err)
+	}
+
+	user, err := h.userService.SetRole(requestContext(c), id, input.Role)
+	if err != nil {
+		return h.updateFailed(c, err)
 	}
 
 	return response.Success(c, user)
 }
 
+func (h *UserHandler) updateFailed(c *fiber.Ctx, err error) error {
+	if errors.Is(err, service.ErrUserNotFound) {
+		return response.ErrorCode(c, fiber.StatusNotFound, response.CodeUserNotFound, err.Error())
+	}
+	if errors.Is(err, service.ErrEmailAlreadyExists) {
+		return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeEmailExists, err.Error())
+	}
+	if errors.Is(err, service.ErrInvalidRole) {
+		return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeInvalidRole, err.Error())
+	}
+	if errors.Is(err, service.ErrLastAdmin) {
+		return response.ErrorCode(c, fiber.StatusConflict, response.CodeLastAdmin, err.Error())
+	}
+	if errors.Is(err, service.ErrVersionConflict) {
+		return response.ErrorCode(c, fiber.StatusConflict, response.CodeVersionConflict, err.Error())
+	}
+	return response.InternalServerError(c, "Failed to update user")
+}
+
+// ifMatchVersion reads the version the client expects to be updating from an
+// If-Match header such as "3" into version, taking precedence over any
+// version in the body. A missing header or * leaves version as it is; it
+// reports false for a header that isn't a version.
+func ifMatchVersion(c *fiber.Ctx, version **uint) bool {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" || header == "*" {
+		return true
+	}
+
+	v, err := strconv.ParseUint(strings.Trim(strings.TrimPrefix(header, "W/"), `"`), 10, 0)
+	if err != nil {
+		return false
+	}
+	n := uint(v)
+	*version = &n
+	return true
+}
+
 // Delete godoc
 // @Summary Delete user
 // @Description Delete user by ID (admin only)
@@ -151,18 +672,305 @@ func (h *UserHandler) Update(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param id path string true "User ID"
 // @Success 204 "No Content"
+// @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Router /users/{id} [delete]
 func (h *UserHandler) Delete(c *fiber.Ctx) error {
-	id := c.Params("id")
+	id, ok := userIDParam(c)
+	if !ok {
+		return response.BadRequest(c, "Invalid user ID")
+	}
 
-	err := h.userService.Delete(c.Context(), id)
+	err := h.userService.Delete(requestContext(c), id)
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
-			return response.NotFound(c, err.Error())
+			return response.ErrorCode(c, fiber.StatusNotFound, response.CodeUserNotFound, err.Error())
 		}
 		return response.InternalServerError(c, "Failed to delete user")
 	}
 
 	return response.NoContent(c)
+}
+
+// ExportPersonalData godoc
+// @Summary Export my personal data
+// @Description Download all data held about the authenticated user as a JSON bundle
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} service.PersonalDataExport
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Router /users/me/export [get]
+func (h *UserHandler) ExportPersonalData(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	export, err := h.userService.ExportPersonalData(requestContext(c), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return response.ErrorCode(c, fiber.StatusNotFound, response.CodeUserNotFound, err.Error())
+		}
+		return response.InternalServerError(c, "Failed to export personal data")
+	}
+
+	return response.Download(c, fiber.MIMEApplicationJSON, "personal-data-"+userID+".json", func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(export)
+	})
+}
+
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Activate the account the verification token was issued for
+// @Tags Auth
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /auth/verify [get]
+func (h *UserHandler) VerifyEmail(c *fiber.Ctx) error {
+	err := h.userService.VerifyEmail(requestContext(c), c.Query("token"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidToken), errors.Is(err, service.ErrUserNotFound):
+			return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeInvalidToken, "Invalid verification token")
+		case errors.Is(err, service.ErrTokenExpired):
+			return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeTokenExpired, "Verification token has expired")
+		case errors.Is(err, service.ErrTokenUsed):
+			return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeTokenUsed, "Verification token has already been used")
+		}
+		return response.InternalServerError(c, "Failed to verify email")
+	}
+
+	return response.SuccessWithMessage(c, "Email verified", nil)
+}
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Email a password reset link. Always returns 200 so registered emails cannot be discovered.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.ForgotPasswordInput true "Account email"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Router /auth/forgot-password [post]
+func (h *UserHandler) ForgotPassword(c *fiber.Ctx) error {
+	input, err := BindAndValidate[service.ForgotPasswordInput](c)
+	if err != nil {
+		return handled(err)
+	}
+
+	if err := h.userService.RequestPasswordReset(requestContext(c), input.Email); err != nil {
+		return response.InternalServerError(c, "Failed to request password reset")
+	}
+
+	return response.SuccessWithMessage(c, "If the email is registered, a reset link has been sent", nil)
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password using a token from a reset email
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body service.ResetPasswordInput true "Reset token and new password"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /auth/reset-password [post]
+func (h *UserHandler) ResetPassword(c *fiber.Ctx) error {
+	input, err := BindAndValidate[service.ResetPasswordInput](c)
+	if err != nil {
+		return handled(err)
+	}
+
+	err = h.userService.ResetPassword(requestContext(c), input.Token, input.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidToken), errors.Is(err, service.ErrUserNotFound):
+			return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeInvalidToken, "Invalid reset token")
+		case errors.Is(err, service.ErrTokenExpired):
+			return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeTokenExpired, "Reset token has expired")
+		case errors.Is(err, service.ErrTokenUsed):
+			return response.ErrorCode(c, fiber.StatusBadRequest, response.CodeTokenUsed, "Reset token has already been used")
+		}
+		return response.InternalServerError(c, "Failed to reset password")
+	}
+
+	return response.SuccessWithMessage(c, "Password has been reset", nil)
+}
+
+// AdminResetPassword godoc
+// @Summary Set a user's password
+// @Description Replace a locked-out user's password without the email flow, optionally signing them out everywhere. Admins cannot use it on their own account (admin only)
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body service.AdminSetPasswordInput true "New password"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /users/{id}/reset-password [post]
+func (h *UserHandler) AdminResetPassword(c *fiber.Ctx) error {
+	id, ok := userIDParam(c)
+	if !ok {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+
+	input, err := BindAndValidateStrict[service.AdminSetPasswordInput](c)
+	if err != nil {
+		return handled(err)
+	}
+
+	if err := h.userService.AdminSetPassword(requestContext(c), id, input); err != nil {
+		if errors.Is(err, service.ErrResetOwnPassword) {
+			return response.ErrorCode(c, fiber.StatusForbidden, response.CodeResetOwnPassword, err.Error())
+		}
+		return h.updateFailed(c, err)
+	}
+
+	return response.SuccessWithMessage(c, "Password has been reset", nil)
+}
+
+// FindPending godoc
+// @Summary List accounts awaiting approval
+// @Description Get paginated list of self-registered accounts pending admin approval (admin only)
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(10)
+// @Success 200 {object} response.Response{data=response.PaginatedData}
+// @Header 200 {integer} X-Total-Count "Total number of items"
+// @Header 200 {integer} X-Page "Current page"
+// @Header 200 {integer} X-Per-Page "Items per page"
+// @Header 200 {integer} X-Total-Pages "Total number of pages"
+// @Router /users/pending [get]
+func (h *UserHandler) FindPending(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	perPage, _ := strconv.Atoi(c.Query("per_page"))
+	page, perPage = h.pageLimits.Normalize(page, perPage)
+
+	filter := service.UserFilter{ApprovalStatus: model.ApprovalPending}
+	sort := service.Sort{Column: "created_at"}
+
+	users, total, err := h.userService.FindAll(requestContext(c), filter, sort, page, perPage)
+	if err != nil {
+		return response.InternalServerError(c, "Failed to fetch users")
+	}
+
+	return response.PaginatedWithLinks(c, users, total, page, perPage)
+}
+
+// Approve godoc
+// @Summary Approve account
+// @Description Approve a pending account and notify the user (admin only)
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response{data=service.UserResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /users/{id}/approve [post]
+func (h *UserHandler) Approve(c *fiber.Ctx) error {
+	return h.decideApproval(c, h.userService.ApproveUser)
+}
+
+// Reject godoc
+// @Summary Reject account
+// @Description Reject a pending account and notify the user (admin only)
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response{data=service.UserResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /users/{id}/reject [post]
+func (h *UserHandler) Reject(c *fiber.Ctx) error {
+	return h.decideApproval(c, h.userService.RejectUser)
+}
+
+func (h *UserHandler) decideApproval(c *fiber.Ctx, decide func(ctx context.Context, id string) (*service.UserResponse, error)) error {
+	id, ok := userIDParam(c)
+	if !ok {
+		return response.BadRequest(c, "Invalid user ID")
+	}
+
+	user, err := decide(requestContext(c), id)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return response.ErrorCode(c, fiber.StatusNotFound, response.CodeUserNotFound, err.Error())
+		}
+		if errors.Is(err, service.ErrNotPendingApproval) {
+			return response.ErrorCode(c, fiber.StatusConflict, response.CodeNotPendingApproval, err.Error())
+		}
+		if errors.Is(err, service.ErrVersionConflict) {
+			return response.ErrorCode(c, fiber.StatusConflict, response.CodeVersionConflict, err.Error())
+		}
+		return response.InternalServerError(c, "Failed to update approval")
+	}
+
+	return response.Success(c, user)
+}
+
+// userFilterQuery reads the role, is_active and q filters shared by the user
+// list endpoints. It reports false when is_active is not a boolean.
+func userFilterQuery(c *fiber.Ctx) (service.UserFilter, bool) {
+	filter := service.UserFilter{
+		Role:   c.Query("role"),
+		Search: c.Query("q"),
+	}
+	if raw := c.Query("is_active"); raw != "" {
+		isActive, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, false
+		}
+		filter.IsActive = &isActive
+	}
+	return filter, true
+}
+
+// userFieldsQuery reads ?fields=id,name, the fields to return of each user.
+// fields is nil when the parameter is absent; unknown names the first field
+// that can't be selected.
+func userFieldsQuery(c *fiber.Ctx) (fields []string, unknown string) {
+	for _, field := range strings.Split(c.Query("fields"), ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !selectableUserFields[field] {
+			return nil, field
+		}
+		fields = append(fields, field)
+	}
+	return fields, ""
+}
+
+// userLocation is the URL of the user with id, relative to the collection
+// the request was posted to, e.g. /api/v1/users/{id}.
+func userLocation(c *fiber.Ctx, id string) string {
+	return strings.TrimSuffix(c.Path(), "/") + "/" + id
+}
+
+// userIDParam returns the :id route parameter, reporting false when it is not
+// a UUID so malformed IDs never reach the service or database.
+func userIDParam(c *fiber.Ctx) (string, bool) {
+	id := c.Params("id")
+	if errs := validator.ValidateVar(id, "required,uuid"); len(errs) > 0 {
+		return "", false
+	}
+	return id, true
 }
\ No newline at end of file
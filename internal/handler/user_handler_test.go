@@ -3,17 +3,23 @@ package handler
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/ariam/my-api/internal/authz"
+	"github.com/ariam/my-api/internal/middleware"
 	"github.com/ariam/my-api/internal/service"
 	"github.com/ariam/my-api/pkg/response"
 	"github.com/ariam/my-api/pkg/validator"
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type MockUserService struct {
@@ -28,6 +34,27 @@ func (m *MockUserService) Create(ctx context.Context, input *service.CreateUserI
 	return args.Get(0).(*service.UserResponse), args.Error(1)
 }
 
+func (m *MockUserService) Register(ctx context.Context, input *service.CreateUserInput) (*service.UserResponse, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) EnsureAdmin(ctx context.Context, input *service.CreateUserInput) (bool, error) {
+	args := m.Called(ctx, input)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserService) BulkCreate(ctx context.Context, r io.Reader) (*service.BulkCreateResult, error) {
+	args := m.Called(ctx, r)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.BulkCreateResult), args.Error(1)
+}
+
 func (m *MockUserService) FindByID(ctx context.Context, id string) (*service.UserResponse, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -36,8 +63,31 @@ func (m *MockUserService) FindByID(ctx context.Context, id string) (*service.Use
 	return args.Get(0).(*service.UserResponse), args.Error(1)
 }
 
-func (m *MockUserService) FindAll(ctx context.Context, page, perPage int) ([]service.UserResponse, int64, error) {
-	args := m.Called(ctx, page, perPage)
+func (m *MockUserService) FindAll(ctx context.Context, filter service.UserFilter, sort service.Sort, page, perPage int) ([]service.UserResponse, int64, error) {
+	args := m.Called(ctx, filter, sort, page, perPage)
+	return args.Get(0).([]service.UserResponse), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserService) FindAfter(ctx context.Context, filter service.UserFilter, cursor string, limit int) ([]service.UserResponse, string, error) {
+	args := m.Called(ctx, filter, cursor, limit)
+	return args.Get(0).([]service.UserResponse), args.String(1), args.Error(2)
+}
+
+func (m *MockUserService) CheckEmailAvailable(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockUserService) FindByEmail(ctx context.Context, email string) (*service.UserResponse, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) Search(ctx context.Context, query string, page, perPage int) ([]service.UserResponse, int64, error) {
+	args := m.Called(ctx, query, page, perPage)
 	return args.Get(0).([]service.UserResponse), args.Get(1).(int64), args.Error(2)
 }
 
@@ -49,15 +99,98 @@ func (m *MockUserService) Update(ctx context.Context, id string, input *service.
 	return args.Get(0).(*service.UserResponse), args.Error(1)
 }
 
+func (m *MockUserService) Patch(ctx context.Context, id string, input *service.PatchUserInput) (*service.UserResponse, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) SetRole(ctx context.Context, id, role string) (*service.UserResponse, error) {
+	args := m.Called(ctx, id, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.UserResponse), args.Error(1)
+}
+
 func (m *MockUserService) Delete(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockUserService) GenerateVerificationToken(ctx context.Context, userID string) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserService) VerifyEmail(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockUserService) RequestPasswordReset(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockUserService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	args := m.Called(ctx, token, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockUserService) AdminSetPassword(ctx context.Context, id string, input *service.AdminSetPasswordInput) error {
+	args := m.Called(ctx, id, input)
+	return args.Error(0)
+}
+
+func (m *MockUserService) ApproveUser(ctx context.Context, id string) (*service.UserResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) RejectUser(ctx context.Context, id string) (*service.UserResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) ExportPersonalData(ctx context.Context, id string) (*service.PersonalDataExport, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*service.PersonalDataExport), args.Error(1)
+}
+
+func (m *MockUserService) ExportUsers(ctx context.Context, filter service.UserFilter, fn func([]service.UserResponse) error) error {
+	args := m.Called(ctx, filter)
+	if users, ok := args.Get(0).([]service.UserResponse); ok {
+		if err := fn(users); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+var defaultSort = service.Sort{Column: "created_at", Desc: true}
+
+const (
+	testUserID    = "5f0c6a4e-3a1b-4c2d-9e8f-0a1b2c3d4e5f"
+	missingUserID = "9b2d7c1e-8f3a-4e5b-a6c7-d8e9f0a1b2c3"
+)
+
 func setupTestApp(handler *UserHandler) *fiber.App {
 	validator.Init()
 	app := fiber.New()
 	app.Post("/users", handler.Create)
+	app.Post("/users/bulk", handler.BulkCreate)
 	app.Get("/users", handler.FindAll)
 	app.Get("/users/:id", handler.FindByID)
 	app.Put("/users/:id", handler.Update)
@@ -89,7 +222,7 @@ func TestUserHandler_Create(t *testing.T) {
 			body: map[string]string{
 				"name":     "John Doe",
 				"email":    "john@example.com",
-				"password": "password123",
+				"password": "Password123!",
 			},
 			expectedStatus: fiber.StatusCreated,
 			checkResponse: func(t *testing.T, resp response.Response) {
@@ -133,12 +266,13 @@ func TestUserHandler_Create(t *testing.T) {
 			body: map[string]string{
 				"name":     "John Doe",
 				"email":    "existing@example.com",
-				"password": "password123",
+				"password": "Password123!",
 			},
 			expectedStatus: fiber.StatusBadRequest,
 			checkResponse: func(t *testing.T, resp response.Response) {
 				assert.False(t, resp.Success)
 				assert.Equal(t, "email already exists", resp.Error)
+				assert.Equal(t, response.CodeEmailExists, resp.Code)
 			},
 		},
 		{
@@ -150,7 +284,7 @@ func TestUserHandler_Create(t *testing.T) {
 			body: map[string]string{
 				"name":     "John Doe",
 				"email":    "john@example.com",
-				"password": "password123",
+				"password": "Password123!",
 			},
 			expectedStatus: fiber.StatusInternalServerError,
 			checkResponse: func(t *testing.T, resp response.Response) {
@@ -198,6 +332,122 @@ func TestUserHandler_Create(t *testing.T) {
 	}
 }
 
+// TestUserHandler_Create_Location tests that a 201 points at the new user
+func TestUserHandler_Create_Location(t *testing.T) {
+	mockService := new(MockUserService)
+	mockService.On("Create", mock.Anything, mock.AnythingOfType("*service.CreateUserInput")).
+		Return(&service.UserResponse{ID: testUserID, Name: "John Doe", Email: "john@example.com"}, nil)
+
+	validator.Init()
+	app := fiber.New()
+	app.Group("/api/v1").Group("/users").Post("/", NewUserHandler(mockService).Create)
+
+	body, _ := json.Marshal(map[string]string{"name": "John Doe", "email": "john@example.com", "password": "Password123!"})
+	req := httptest.NewRequest("POST", "/api/v1/users", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "/api/v1/users/"+testUserID, resp.Header.Get("Location"))
+	mockService.AssertExpectations(t)
+}
+
+// TestUserHandler_Create_ValidateOnly tests that a dry run reports validity without creating the user
+func TestUserHandler_Create_ValidateOnly(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           map[string]string
+		setupMock      func(*MockUserService)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "valid payload",
+			body: map[string]string{"name": "John Doe", "email": "john@example.com", "password": "Password123!"},
+			setupMock: func(m *MockUserService) {
+				m.On("CheckEmailAvailable", mock.Anything, "john@example.com").Return(nil)
+			},
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name: "duplicate email",
+			body: map[string]string{"name": "John Doe", "email": "john@example.com", "password": "Password123!"},
+			setupMock: func(m *MockUserService) {
+				m.On("CheckEmailAvailable", mock.Anything, "john@example.com").Return(service.ErrEmailAlreadyExists)
+			},
+			expectedStatus: fiber.StatusBadRequest,
+			expectedCode:   response.CodeEmailExists,
+		},
+		{
+			name: "email of a deleted account",
+			body: map[string]string{"name": "John Doe", "email": "john@example.com", "password": "Password123!"},
+			setupMock: func(m *MockUserService) {
+				m.On("CheckEmailAvailable", mock.Anything, "john@example.com").Return(service.ErrEmailDeletedAccount)
+			},
+			expectedStatus: fiber.StatusBadRequest,
+			expectedCode:   response.CodeEmailDeletedAccount,
+		},
+		{
+			name:           "invalid payload",
+			body:           map[string]string{"name": "", "email": "invalid", "password": "weak"},
+			expectedStatus: fiber.StatusUnprocessableEntity,
+			expectedCode:   response.CodeValidationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			if tt.setupMock != nil {
+				tt.setupMock(mockService)
+			}
+			app := setupTestApp(NewUserHandler(mockService))
+
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest("POST", "/users?validate_only=true", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var respBody response.Response
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+			assert.Equal(t, tt.expectedCode, respBody.Code)
+			if tt.expectedStatus == fiber.StatusOK {
+				assert.Equal(t, map[string]interface{}{"valid": true}, respBody.Data)
+			}
+
+			mockService.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_Create_ValidationErrorMap(t *testing.T) {
+	app := setupTestApp(NewUserHandler(new(MockUserService)))
+
+	body, _ := json.Marshal(map[string]string{"name": "", "email": "invalid", "password": "weak"})
+	req := httptest.NewRequest("POST", "/users", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ValidationFormatHeader, "map")
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+	var respBody struct {
+		Success bool                `json:"success"`
+		Error   map[string][]string `json:"error"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	assert.False(t, respBody.Success)
+	assert.Equal(t, []string{"name is required"}, respBody.Error["name"])
+	assert.Equal(t, []string{"email must be a valid email"}, respBody.Error["email"])
+	assert.Len(t, respBody.Error["password"], 1)
+}
+
 // TestUserHandler_FindByID implements table-driven tests for the FindByID endpoint
 // Requirements: 4.1, 4.2, 4.3
 func TestUserHandler_FindByID(t *testing.T) {
@@ -210,11 +460,11 @@ func TestUserHandler_FindByID(t *testing.T) {
 	}{
 		{
 			name:   "valid user ID returns 200 with user data",
-			userID: "test-uuid",
+			userID: testUserID,
 			setupMock: func(m *MockUserService) {
-				m.On("FindByID", mock.Anything, "test-uuid").
+				m.On("FindByID", mock.Anything, testUserID).
 					Return(&service.UserResponse{
-						ID:    "test-uuid",
+						ID:    testUserID,
 						Name:  "John Doe",
 						Email: "john@example.com",
 						Role:  "user",
@@ -225,29 +475,40 @@ func TestUserHandler_FindByID(t *testing.T) {
 				assert.True(t, resp.Success)
 				data, ok := resp.Data.(map[string]interface{})
 				assert.True(t, ok, "Data should be a map")
-				assert.Equal(t, "test-uuid", data["id"])
+				assert.Equal(t, testUserID, data["id"])
 				assert.Equal(t, "John Doe", data["name"])
 				assert.Equal(t, "john@example.com", data["email"])
 			},
 		},
+		{
+			name:           "malformed user ID returns 400 without calling the service",
+			userID:         "not-a-uuid",
+			setupMock:      nil,
+			expectedStatus: fiber.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp response.Response) {
+				assert.False(t, resp.Success)
+				assert.Equal(t, "Invalid user ID", resp.Error)
+			},
+		},
 		{
 			name:   "non-existent user ID returns 404",
-			userID: "invalid-id",
+			userID: missingUserID,
 			setupMock: func(m *MockUserService) {
-				m.On("FindByID", mock.Anything, "invalid-id").
+				m.On("FindByID", mock.Anything, missingUserID).
 					Return(nil, service.ErrUserNotFound)
 			},
 			expectedStatus: fiber.StatusNotFound,
 			checkResponse: func(t *testing.T, resp response.Response) {
 				assert.False(t, resp.Success)
 				assert.Equal(t, "user not found", resp.Error)
+				assert.Equal(t, response.CodeUserNotFound, resp.Code)
 			},
 		},
 		{
 			name:   "service error returns 500",
-			userID: "test-uuid",
+			userID: testUserID,
 			setupMock: func(m *MockUserService) {
-				m.On("FindByID", mock.Anything, "test-uuid").
+				m.On("FindByID", mock.Anything, testUserID).
 					Return(nil, errors.New("database connection failed"))
 			},
 			expectedStatus: fiber.StatusInternalServerError,
@@ -287,6 +548,150 @@ func TestUserHandler_FindByID(t *testing.T) {
 	}
 }
 
+// TestUserHandler_Me tests fetching the caller's own record
+func TestUserHandler_Exists(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		expectedStatus int
+	}{
+		{name: "existing user", userID: testUserID, expectedStatus: fiber.StatusOK},
+		{name: "missing user", userID: missingUserID, expectedStatus: fiber.StatusNotFound},
+		{name: "malformed user ID", userID: "not-a-uuid", expectedStatus: fiber.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			mockService.On("FindByID", mock.Anything, testUserID).Return(&service.UserResponse{ID: testUserID}, nil).Maybe()
+			mockService.On("FindByID", mock.Anything, missingUserID).Return(nil, service.ErrUserNotFound).Maybe()
+			app := fiber.New()
+			app.Head("/users/:id", NewUserHandler(mockService).Exists)
+
+			resp, err := app.Test(httptest.NewRequest("HEAD", "/users/"+tt.userID, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			assert.Empty(t, body)
+		})
+	}
+}
+
+func TestUserHandler_Me(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(m *MockUserService)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name: "authenticated fetch returns the caller",
+			setupMock: func(m *MockUserService) {
+				m.On("FindByID", mock.Anything, testUserID).
+					Return(&service.UserResponse{ID: testUserID, Name: "John Doe", Email: "john@example.com", Role: "user", IsActive: true}, nil)
+			},
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name: "deleted account returns 404",
+			setupMock: func(m *MockUserService) {
+				m.On("FindByID", mock.Anything, testUserID).Return(nil, service.ErrUserNotFound)
+			},
+			expectedStatus: fiber.StatusNotFound,
+			expectedCode:   response.CodeUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			tt.setupMock(mockService)
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals("user_id", testUserID)
+				return c.Next()
+			})
+			app.Get("/users/me", NewUserHandler(mockService).Me)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/users/me", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var respBody response.Response
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+			if tt.expectedCode != "" {
+				assert.Equal(t, tt.expectedCode, respBody.Code)
+			} else {
+				data, ok := respBody.Data.(map[string]interface{})
+				assert.True(t, ok, "Data should be a map")
+				assert.Equal(t, testUserID, data["id"])
+				assert.Equal(t, "john@example.com", data["email"])
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUserHandler_OwnershipGuard tests that non-admins can only read and update their own record
+func TestUserHandler_OwnershipGuard(t *testing.T) {
+	const otherUserID = "7a1d2e3f-4b5c-4d6e-8f90-1a2b3c4d5e6f"
+
+	tests := []struct {
+		name           string
+		callerID       string
+		callerRole     string
+		method         string
+		targetID       string
+		expectedStatus int
+	}{
+		{name: "user reads own record", callerID: testUserID, callerRole: "user", method: "GET", targetID: testUserID, expectedStatus: fiber.StatusOK},
+		{name: "user reads another user", callerID: testUserID, callerRole: "user", method: "GET", targetID: otherUserID, expectedStatus: fiber.StatusForbidden},
+		{name: "admin reads anyone", callerID: testUserID, callerRole: "admin", method: "GET", targetID: otherUserID, expectedStatus: fiber.StatusOK},
+		{name: "user updates own record", callerID: testUserID, callerRole: "user", method: "PUT", targetID: testUserID, expectedStatus: fiber.StatusOK},
+		{name: "user updates another user", callerID: testUserID, callerRole: "user", method: "PUT", targetID: otherUserID, expectedStatus: fiber.StatusForbidden},
+		{name: "user patches another user", callerID: testUserID, callerRole: "user", method: "PATCH", targetID: otherUserID, expectedStatus: fiber.StatusForbidden},
+		{name: "admin updates anyone", callerID: testUserID, callerRole: "admin", method: "PUT", targetID: otherUserID, expectedStatus: fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			user := &service.UserResponse{ID: tt.targetID, Name: "John Doe", Email: "john@example.com", Role: "user"}
+			mockService.On("FindByID", mock.Anything, tt.targetID).Return(user, nil).Maybe()
+			mockService.On("Update", mock.Anything, tt.targetID, mock.AnythingOfType("*service.UpdateUserInput")).Return(user, nil).Maybe()
+
+			handler := NewUserHandler(mockService)
+			validator.Init()
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals("user_id", tt.callerID)
+				c.Locals("role", tt.callerRole)
+				return c.Next()
+			})
+			guard := middleware.SelfOrRole("id", "admin")
+			app.Get("/users/:id", guard, handler.FindByID)
+			app.Put("/users/:id", guard, handler.Update)
+			app.Patch("/users/:id", guard, handler.Patch)
+
+			body, _ := json.Marshal(map[string]string{"name": "John Doe", "email": "john@example.com"})
+			req := httptest.NewRequest(tt.method, "/users/"+tt.targetID, bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedStatus == fiber.StatusForbidden {
+				mockService.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+				mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
 // TestUserHandler_FindAll implements table-driven tests for the FindAll endpoint
 // Requirements: 5.1, 5.2, 5.3, 5.4, 5.5
 func TestUserHandler_FindAll(t *testing.T) {
@@ -301,7 +706,7 @@ func TestUserHandler_FindAll(t *testing.T) {
 			name:        "default pagination (no params) returns 200",
 			queryParams: "",
 			setupMock: func(m *MockUserService) {
-				m.On("FindAll", mock.Anything, 1, 10).
+				m.On("FindAll", mock.Anything, service.UserFilter{}, defaultSort, 1, 10).
 					Return([]service.UserResponse{
 						{ID: "user-1", Name: "User One", Email: "user1@example.com", Role: "user"},
 						{ID: "user-2", Name: "User Two", Email: "user2@example.com", Role: "user"},
@@ -324,7 +729,7 @@ func TestUserHandler_FindAll(t *testing.T) {
 			name:        "custom pagination params returns 200",
 			queryParams: "?page=2&per_page=5",
 			setupMock: func(m *MockUserService) {
-				m.On("FindAll", mock.Anything, 2, 5).
+				m.On("FindAll", mock.Anything, service.UserFilter{}, defaultSort, 2, 5).
 					Return([]service.UserResponse{
 						{ID: "user-6", Name: "User Six", Email: "user6@example.com", Role: "user"},
 					}, int64(6), nil)
@@ -344,7 +749,7 @@ func TestUserHandler_FindAll(t *testing.T) {
 			name:        "invalid page (< 1) normalized to 1",
 			queryParams: "?page=0&per_page=10",
 			setupMock: func(m *MockUserService) {
-				m.On("FindAll", mock.Anything, 1, 10).
+				m.On("FindAll", mock.Anything, service.UserFilter{}, defaultSort, 1, 10).
 					Return([]service.UserResponse{}, int64(0), nil)
 			},
 			expectedStatus: fiber.StatusOK,
@@ -359,7 +764,7 @@ func TestUserHandler_FindAll(t *testing.T) {
 			name:        "invalid per_page (< 1) normalized to 10",
 			queryParams: "?page=1&per_page=0",
 			setupMock: func(m *MockUserService) {
-				m.On("FindAll", mock.Anything, 1, 10).
+				m.On("FindAll", mock.Anything, service.UserFilter{}, defaultSort, 1, 10).
 					Return([]service.UserResponse{}, int64(0), nil)
 			},
 			expectedStatus: fiber.StatusOK,
@@ -371,10 +776,10 @@ func TestUserHandler_FindAll(t *testing.T) {
 			},
 		},
 		{
-			name:        "invalid per_page (> 100) normalized to 10",
+			name:        "per_page above 100 clamped to 100",
 			queryParams: "?page=1&per_page=150",
 			setupMock: func(m *MockUserService) {
-				m.On("FindAll", mock.Anything, 1, 10).
+				m.On("FindAll", mock.Anything, service.UserFilter{}, defaultSort, 1, 100).
 					Return([]service.UserResponse{}, int64(0), nil)
 			},
 			expectedStatus: fiber.StatusOK,
@@ -382,14 +787,14 @@ func TestUserHandler_FindAll(t *testing.T) {
 				assert.True(t, resp.Success)
 				data, ok := resp.Data.(map[string]interface{})
 				assert.True(t, ok, "Data should be a map")
-				assert.Equal(t, float64(10), data["per_page"])
+				assert.Equal(t, float64(100), data["per_page"])
 			},
 		},
 		{
 			name:        "service error returns 500",
 			queryParams: "",
 			setupMock: func(m *MockUserService) {
-				m.On("FindAll", mock.Anything, 1, 10).
+				m.On("FindAll", mock.Anything, service.UserFilter{}, defaultSort, 1, 10).
 					Return([]service.UserResponse{}, int64(0), errors.New("database connection failed"))
 			},
 			expectedStatus: fiber.StatusInternalServerError,
@@ -398,35 +803,202 @@ func TestUserHandler_FindAll(t *testing.T) {
 				assert.Equal(t, "Failed to fetch users", resp.Error)
 			},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mockService := new(MockUserService)
-			if tt.setupMock != nil {
-				tt.setupMock(mockService)
-			}
-			handler := NewUserHandler(mockService)
-			app := setupTestApp(handler)
-
-			req := httptest.NewRequest("GET", "/users"+tt.queryParams, nil)
-
-			resp, err := app.Test(req)
-
-			assert.NoError(t, err)
-			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
-
-			var respBody response.Response
-			err = json.NewDecoder(resp.Body).Decode(&respBody)
-			assert.NoError(t, err)
-
-			if tt.checkResponse != nil {
-				tt.checkResponse(t, respBody)
-			}
-
-			mockService.AssertExpectations(t)
-		})
-	}
+		{
+			name:        "filter params are passed to service",
+			queryParams: "?role=admin&is_active=true&q=john",
+			setupMock: func(m *MockUserService) {
+				isActive := true
+				m.On("FindAll", mock.Anything, service.UserFilter{Role: "admin", IsActive: &isActive, Search: "john"}, defaultSort, 1, 10).
+					Return([]service.UserResponse{}, int64(0), nil)
+			},
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:        "sort params are passed to service",
+			queryParams: "?sort_by=name&order=asc",
+			setupMock: func(m *MockUserService) {
+				m.On("FindAll", mock.Anything, service.UserFilter{}, service.Sort{Column: "name"}, 1, 10).
+					Return([]service.UserResponse{}, int64(0), nil)
+			},
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:           "unknown sort column returns 400",
+			queryParams:    "?sort_by=password",
+			expectedStatus: fiber.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp response.Response) {
+				assert.False(t, resp.Success)
+				assert.Equal(t, "Invalid sort_by value", resp.Error)
+			},
+		},
+		{
+			name:           "invalid order returns 400",
+			queryParams:    "?sort_by=email&order=sideways",
+			expectedStatus: fiber.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp response.Response) {
+				assert.False(t, resp.Success)
+				assert.Equal(t, "Invalid order value", resp.Error)
+			},
+		},
+		{
+			name:        "limit switches to cursor pagination",
+			queryParams: "?limit=2",
+			setupMock: func(m *MockUserService) {
+				m.On("FindAfter", mock.Anything, service.UserFilter{}, "", 2).
+					Return([]service.UserResponse{{ID: "user-2"}, {ID: "user-1"}}, "next-token", nil)
+			},
+			expectedStatus: fiber.StatusOK,
+			checkResponse: func(t *testing.T, resp response.Response) {
+				assert.True(t, resp.Success)
+				data := resp.Data.(map[string]interface{})
+				assert.Equal(t, "next-token", data["next_cursor"])
+				assert.Equal(t, float64(2), data["limit"])
+				assert.Len(t, data["items"], 2)
+			},
+		},
+		{
+			name:        "last cursor page has empty next_cursor",
+			queryParams: "?cursor=abc&limit=5",
+			setupMock: func(m *MockUserService) {
+				m.On("FindAfter", mock.Anything, service.UserFilter{}, "abc", 5).
+					Return([]service.UserResponse{{ID: "user-1"}}, "", nil)
+			},
+			expectedStatus: fiber.StatusOK,
+			checkResponse: func(t *testing.T, resp response.Response) {
+				data := resp.Data.(map[string]interface{})
+				assert.Equal(t, "", data["next_cursor"])
+			},
+		},
+		{
+			name:        "invalid cursor returns 400",
+			queryParams: "?cursor=garbage",
+			setupMock: func(m *MockUserService) {
+				m.On("FindAfter", mock.Anything, service.UserFilter{}, "garbage", 10).
+					Return([]service.UserResponse(nil), "", service.ErrInvalidCursor)
+			},
+			expectedStatus: fiber.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp response.Response) {
+				assert.Equal(t, "Invalid cursor", resp.Error)
+			},
+		},
+		{
+			name:           "invalid is_active returns 400",
+			queryParams:    "?is_active=maybe",
+			expectedStatus: fiber.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp response.Response) {
+				assert.False(t, resp.Success)
+				assert.Equal(t, "Invalid is_active value", resp.Error)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			if tt.setupMock != nil {
+				tt.setupMock(mockService)
+			}
+			handler := NewUserHandler(mockService)
+			app := setupTestApp(handler)
+
+			req := httptest.NewRequest("GET", "/users"+tt.queryParams, nil)
+
+			resp, err := app.Test(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var respBody response.Response
+			err = json.NewDecoder(resp.Body).Decode(&respBody)
+			assert.NoError(t, err)
+
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, respBody)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUserHandler_FindAll_ConfiguredMaxPerPage tests that per_page honours the deployment's limits
+func TestUserHandler_FindAll_ConfiguredMaxPerPage(t *testing.T) {
+	mockService := new(MockUserService)
+	for _, perPage := range []int{250, 500, 25} {
+		mockService.On("FindAll", mock.Anything, service.UserFilter{}, defaultSort, 1, perPage).
+			Return([]service.UserResponse{}, int64(0), nil)
+	}
+	handler := NewUserHandler(mockService, WithPageLimits(service.PageLimits{DefaultPerPage: 25, MaxPerPage: 500}))
+	app := setupTestApp(handler)
+
+	for query, want := range map[string]float64{"?per_page=250": 250, "?per_page=501": 500, "?per_page=0": 25, "": 25} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/users"+query, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var respBody response.Response
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		assert.Equal(t, want, respBody.Data.(map[string]interface{})["per_page"], query)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_FieldSelection(t *testing.T) {
+	user := service.UserResponse{ID: testUserID, Name: "John Doe", Email: "john@example.com", Role: "user", IsActive: true}
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedData   func(data interface{}) interface{}
+	}{
+		{
+			name:           "single user limited to the requested fields",
+			url:            "/users/" + testUserID + "?fields=id,name",
+			expectedStatus: fiber.StatusOK,
+			expectedData:   func(data interface{}) interface{} { return data },
+		},
+		{
+			name:           "list items limited to the requested fields",
+			url:            "/users?fields=id,%20name",
+			expectedStatus: fiber.StatusOK,
+			expectedData:   func(data interface{}) interface{} { return data.(map[string]interface{})["items"].([]interface{})[0] },
+		},
+		{
+			name:           "unknown field on a single user is rejected",
+			url:            "/users/" + testUserID + "?fields=id,password",
+			expectedStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:           "unknown field on the list is rejected",
+			url:            "/users?fields=password",
+			expectedStatus: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			mockService.On("FindByID", mock.Anything, testUserID).Return(&user, nil).Maybe()
+			mockService.On("FindAll", mock.Anything, service.UserFilter{}, defaultSort, 1, service.DefaultPerPage).
+				Return([]service.UserResponse{user}, int64(1), nil).Maybe()
+			app := setupTestApp(NewUserHandler(mockService))
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.url, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var respBody response.Response
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+			if tt.expectedStatus != fiber.StatusOK {
+				assert.Equal(t, "Unknown field: password", respBody.Error)
+				mockService.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+				mockService.AssertNotCalled(t, "FindAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.Equal(t, map[string]interface{}{"id": testUserID, "name": "John Doe"}, tt.expectedData(respBody.Data))
+		})
+	}
 }
 
 // TestUserHandler_Update implements table-driven tests for the Update endpoint
@@ -442,31 +1014,32 @@ func TestUserHandler_Update(t *testing.T) {
 	}{
 		{
 			name:   "valid update returns 200",
-			userID: "test-uuid",
+			userID: testUserID,
 			setupMock: func(m *MockUserService) {
-				m.On("Update", mock.Anything, "test-uuid", mock.AnythingOfType("*service.UpdateUserInput")).
+				m.On("Update", mock.Anything, testUserID, mock.AnythingOfType("*service.UpdateUserInput")).
 					Return(&service.UserResponse{
-						ID:    "test-uuid",
+						ID:    testUserID,
 						Name:  "Updated Name",
 						Email: "john@example.com",
 						Role:  "user",
 					}, nil)
 			},
 			body: map[string]string{
-				"name": "Updated Name",
+				"name":  "Updated Name",
+				"email": "john@example.com",
 			},
 			expectedStatus: fiber.StatusOK,
 			checkResponse: func(t *testing.T, resp response.Response) {
 				assert.True(t, resp.Success)
 				data, ok := resp.Data.(map[string]interface{})
 				assert.True(t, ok, "Data should be a map")
-				assert.Equal(t, "test-uuid", data["id"])
+				assert.Equal(t, testUserID, data["id"])
 				assert.Equal(t, "Updated Name", data["name"])
 			},
 		},
 		{
 			name:      "invalid JSON returns 400",
-			userID:    "test-uuid",
+			userID:    testUserID,
 			setupMock: nil,
 			body:      "invalid json",
 			expectedStatus: fiber.StatusBadRequest,
@@ -477,7 +1050,7 @@ func TestUserHandler_Update(t *testing.T) {
 		},
 		{
 			name:      "validation failure returns 422",
-			userID:    "test-uuid",
+			userID:    testUserID,
 			setupMock: nil,
 			body: map[string]string{
 				"name": "A",
@@ -487,15 +1060,30 @@ func TestUserHandler_Update(t *testing.T) {
 				assert.False(t, resp.Success)
 			},
 		},
+		{
+			name:      "malformed user ID returns 400 without calling the service",
+			userID:    "not-a-uuid",
+			setupMock: nil,
+			body: map[string]string{
+				"name":  "Updated Name",
+				"email": "john@example.com",
+			},
+			expectedStatus: fiber.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp response.Response) {
+				assert.False(t, resp.Success)
+				assert.Equal(t, "Invalid user ID", resp.Error)
+			},
+		},
 		{
 			name:   "not found returns 404",
-			userID: "non-existent-id",
+			userID: missingUserID,
 			setupMock: func(m *MockUserService) {
-				m.On("Update", mock.Anything, "non-existent-id", mock.AnythingOfType("*service.UpdateUserInput")).
+				m.On("Update", mock.Anything, missingUserID, mock.AnythingOfType("*service.UpdateUserInput")).
 					Return(nil, service.ErrUserNotFound)
 			},
 			body: map[string]string{
-				"name": "Updated Name",
+				"name":  "Updated Name",
+				"email": "john@example.com",
 			},
 			expectedStatus: fiber.StatusNotFound,
 			checkResponse: func(t *testing.T, resp response.Response) {
@@ -503,15 +1091,45 @@ func TestUserHandler_Update(t *testing.T) {
 				assert.Equal(t, "user not found", resp.Error)
 			},
 		},
+		{
+			name:      "missing email returns 422",
+			userID:    testUserID,
+			setupMock: nil,
+			body: map[string]string{
+				"name": "Updated Name",
+			},
+			expectedStatus: fiber.StatusUnprocessableEntity,
+			checkResponse: func(t *testing.T, resp response.Response) {
+				assert.False(t, resp.Success)
+			},
+		},
+		{
+			name:   "email taken by another user returns 400",
+			userID: testUserID,
+			setupMock: func(m *MockUserService) {
+				m.On("Update", mock.Anything, testUserID, mock.AnythingOfType("*service.UpdateUserInput")).
+					Return(nil, service.ErrEmailAlreadyExists)
+			},
+			body: map[string]string{
+				"name":  "Updated Name",
+				"email": "taken@example.com",
+			},
+			expectedStatus: fiber.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp response.Response) {
+				assert.False(t, resp.Success)
+				assert.Equal(t, "email already exists", resp.Error)
+			},
+		},
 		{
 			name:   "service error returns 500",
-			userID: "test-uuid",
+			userID: testUserID,
 			setupMock: func(m *MockUserService) {
-				m.On("Update", mock.Anything, "test-uuid", mock.AnythingOfType("*service.UpdateUserInput")).
+				m.On("Update", mock.Anything, testUserID, mock.AnythingOfType("*service.UpdateUserInput")).
 					Return(nil, errors.New("database connection failed"))
 			},
 			body: map[string]string{
-				"name": "Updated Name",
+				"name":  "Updated Name",
+				"email": "john@example.com",
 			},
 			expectedStatus: fiber.StatusInternalServerError,
 			checkResponse: func(t *testing.T, resp response.Response) {
@@ -559,48 +1177,126 @@ func TestUserHandler_Update(t *testing.T) {
 	}
 }
 
-// TestUserHandler_Delete implements table-driven tests for the Delete endpoint
-// Requirements: 7.1, 7.2, 7.3
-func TestUserHandler_Delete(t *testing.T) {
+// TestUserHandler_Update_IfMatch tests that If-Match carries the expected version and a stale one yields 409
+func TestUserHandler_Update_IfMatch(t *testing.T) {
+	tests := []struct {
+		name            string
+		ifMatch         string
+		serviceErr      error
+		expectedVersion *uint
+		expectedStatus  int
+		expectedCode    string
+	}{
+		{name: "current version", ifMatch: `"3"`, expectedVersion: func() *uint { v := uint(3); return &v }(), expectedStatus: fiber.StatusOK},
+		{name: "stale version", ifMatch: `"2"`, serviceErr: service.ErrVersionConflict, expectedVersion: func() *uint { v := uint(2); return &v }(), expectedStatus: fiber.StatusConflict, expectedCode: response.CodeVersionConflict},
+		{name: "wildcard skips the check", ifMatch: "*", expectedStatus: fiber.StatusOK},
+		{name: "malformed header", ifMatch: `"abc"`, expectedStatus: fiber.StatusBadRequest, expectedCode: "BAD_REQUEST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			if tt.expectedStatus != fiber.StatusBadRequest {
+				var result *service.UserResponse
+				if tt.serviceErr == nil {
+					result = &service.UserResponse{ID: testUserID, Version: 4}
+				}
+				mockService.On("Update", mock.Anything, testUserID, mock.MatchedBy(func(input *service.UpdateUserInput) bool {
+					return assert.ObjectsAreEqual(tt.expectedVersion, input.Version)
+				})).Return(result, tt.serviceErr)
+			}
+			app := setupTestApp(NewUserHandler(mockService))
+
+			body, _ := json.Marshal(map[string]string{"name": "Updated Name", "email": "john@example.com"})
+			req := httptest.NewRequest("PUT", "/users/"+testUserID, bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", tt.ifMatch)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var respBody response.Response
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+			assert.Equal(t, tt.expectedCode, respBody.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_Patch(t *testing.T) {
 	tests := []struct {
 		name           string
-		userID         string
+		role           string
+		body           string
 		setupMock      func(*MockUserService)
 		expectedStatus int
-		checkResponse  func(*testing.T, *response.Response)
 	}{
 		{
-			name:   "valid delete returns 204",
-			userID: "test-uuid",
+			name: "only provided fields reach the service",
+			role: "user",
+			body: `{"name":"New Name"}`,
 			setupMock: func(m *MockUserService) {
-				m.On("Delete", mock.Anything, "test-uuid").Return(nil)
+				m.On("Patch", mock.Anything, testUserID, mock.MatchedBy(func(in *service.PatchUserInput) bool {
+					return in.Name != nil && *in.Name == "New Name" && in.Email == nil && in.Role == nil
+				})).Return(&service.UserResponse{ID: testUserID, Name: "New Name"}, nil)
 			},
-			expectedStatus: fiber.StatusNoContent,
-			checkResponse:  nil,
+			expectedStatus: fiber.StatusOK,
 		},
 		{
-			name:   "not found returns 404",
-			userID: "non-existent-id",
+			name:           "empty name is rejected",
+			role:           "user",
+			body:           `{"name":""}`,
+			expectedStatus: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name:           "empty email is rejected",
+			role:           "user",
+			body:           `{"email":""}`,
+			expectedStatus: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name:           "empty role is rejected",
+			role:           "admin",
+			body:           `{"role":""}`,
+			expectedStatus: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name: "explicit null leaves the field unchanged",
+			role: "user",
+			body: `{"name":null,"email":"new@example.com"}`,
 			setupMock: func(m *MockUserService) {
-				m.On("Delete", mock.Anything, "non-existent-id").Return(service.ErrUserNotFound)
-			},
-			expectedStatus: fiber.StatusNotFound,
-			checkResponse: func(t *testing.T, resp *response.Response) {
-				assert.False(t, resp.Success)
-				assert.Equal(t, "user not found", resp.Error)
+				m.On("Patch", mock.Anything, testUserID, mock.MatchedBy(func(in *service.PatchUserInput) bool {
+					return in.Name == nil && in.Email != nil && *in.Email == "new@example.com"
+				})).Return(&service.UserResponse{ID: testUserID, Email: "new@example.com"}, nil)
 			},
+			expectedStatus: fiber.StatusOK,
 		},
 		{
-			name:   "service error returns 500",
-			userID: "test-uuid",
+			name: "email taken by another user returns 400",
+			role: "user",
+			body: `{"email":"taken@example.com"}`,
 			setupMock: func(m *MockUserService) {
-				m.On("Delete", mock.Anything, "test-uuid").Return(errors.New("database connection failed"))
+				m.On("Patch", mock.Anything, testUserID, mock.Anything).Return(nil, service.ErrEmailAlreadyExists)
 			},
-			expectedStatus: fiber.StatusInternalServerError,
-			checkResponse: func(t *testing.T, resp *response.Response) {
-				assert.False(t, resp.Success)
-				assert.Equal(t, "Failed to delete user", resp.Error)
+			expectedStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:           "role change by a non-admin is forbidden",
+			role:           "user",
+			body:           `{"role":"admin"}`,
+			expectedStatus: fiber.StatusForbidden,
+		},
+		{
+			name: "role change by an admin is applied",
+			role: "admin",
+			body: `{"role":"admin"}`,
+			setupMock: func(m *MockUserService) {
+				m.On("Patch", mock.Anything, testUserID, mock.MatchedBy(func(in *service.PatchUserInput) bool {
+					return in.Role != nil && *in.Role == "admin"
+				})).Return(&service.UserResponse{ID: testUserID, Role: "admin"}, nil)
 			},
+			expectedStatus: fiber.StatusOK,
 		},
 	}
 
@@ -610,24 +1306,752 @@ func TestUserHandler_Delete(t *testing.T) {
 			if tt.setupMock != nil {
 				tt.setupMock(mockService)
 			}
-			handler := NewUserHandler(mockService)
-			app := setupTestApp(handler)
+			validator.Init()
+			app := fiber.New()
+			app.Patch("/users/:id", func(c *fiber.Ctx) error {
+				c.Locals("role", tt.role)
+				return c.Next()
+			}, NewUserHandler(mockService).Patch)
 
-			req := httptest.NewRequest("DELETE", "/users/"+tt.userID, nil)
+			req := httptest.NewRequest("PATCH", "/users/"+testUserID, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
 
 			resp, err := app.Test(req)
 
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
-
-			if tt.checkResponse != nil {
-				var respBody response.Response
-				err = json.NewDecoder(resp.Body).Decode(&respBody)
-				assert.NoError(t, err)
-				tt.checkResponse(t, &respBody)
-			}
-
 			mockService.AssertExpectations(t)
 		})
 	}
+}
+
+func TestUserHandler_Patch_RoleChangeFollowsHierarchy(t *testing.T) {
+	previous := authz.Hierarchy
+	authz.Hierarchy = authz.RoleHierarchy{"owner": {"admin"}, "admin": {"user"}}
+	t.Cleanup(func() { authz.Hierarchy = previous })
+
+	mockService := new(MockUserService)
+	mockService.On("Patch", mock.Anything, testUserID, mock.Anything).Return(&service.UserResponse{ID: testUserID, Role: "admin"}, nil)
+	validator.Init()
+	app := fiber.New()
+	app.Patch("/users/:id", func(c *fiber.Ctx) error {
+		c.Locals("role", "owner")
+		return c.Next()
+	}, NewUserHandler(mockService).Patch)
+
+	req := httptest.NewRequest("PATCH", "/users/"+testUserID, bytes.NewReader([]byte(`{"role":"admin"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode, "a role implying admin may change roles")
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_SetRole(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		setupMock      func(*MockUserService)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:   "valid role returns 200",
+			userID: testUserID,
+			body:   `{"role":"admin"}`,
+			setupMock: func(m *MockUserService) {
+				m.On("SetRole", mock.Anything, testUserID, "admin").Return(&service.UserResponse{ID: testUserID, Role: "admin"}, nil)
+			},
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:           "unknown role returns 422",
+			userID:         testUserID,
+			body:           `{"role":"root"}`,
+			expectedStatus: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name:           "missing role returns 422",
+			userID:         testUserID,
+			body:           `{}`,
+			expectedStatus: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name:           "unknown field returns 422",
+			userID:         testUserID,
+			body:           `{"role":"admin","rol":"admin"}`,
+			expectedStatus: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name:           "malformed body returns 400",
+			userID:         testUserID,
+			body:           `{"role":`,
+			expectedStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:   "demoting the last admin returns 409",
+			userID: testUserID,
+			body:   `{"role":"user"}`,
+			setupMock: func(m *MockUserService) {
+				m.On("SetRole", mock.Anything, testUserID, "user").Return(nil, service.ErrLastAdmin)
+			},
+			expectedStatus: fiber.StatusConflict,
+			expectedError:  "cannot demote the last admin",
+		},
+		{
+			name:           "malformed user ID returns 400",
+			userID:         "not-a-uuid",
+			body:           `{"role":"admin"}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedError:  "Invalid user ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			if tt.setupMock != nil {
+				tt.setupMock(mockService)
+			}
+			app := fiber.New()
+			app.Patch("/users/:id/role", NewUserHandler(mockService).SetRole)
+
+			req := httptest.NewRequest("PATCH", "/users/"+tt.userID+"/role", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedError != "" {
+				var respBody response.Response
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+				assert.Equal(t, tt.expectedError, respBody.Error)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestUserHandler_Delete implements table-driven tests for the Delete endpoint
+// Requirements: 7.1, 7.2, 7.3
+func TestUserHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		setupMock      func(*MockUserService)
+		expectedStatus int
+		checkResponse  func(*testing.T, *response.Response)
+	}{
+		{
+			name:   "valid delete returns 204",
+			userID: testUserID,
+			setupMock: func(m *MockUserService) {
+				m.On("Delete", mock.Anything, testUserID).Return(nil)
+			},
+			expectedStatus: fiber.StatusNoContent,
+			checkResponse:  nil,
+		},
+		{
+			name:           "malformed user ID returns 400 without calling the service",
+			userID:         "not-a-uuid",
+			setupMock:      nil,
+			expectedStatus: fiber.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp *response.Response) {
+				assert.False(t, resp.Success)
+				assert.Equal(t, "Invalid user ID", resp.Error)
+			},
+		},
+		{
+			name:   "not found returns 404",
+			userID: missingUserID,
+			setupMock: func(m *MockUserService) {
+				m.On("Delete", mock.Anything, missingUserID).Return(service.ErrUserNotFound)
+			},
+			expectedStatus: fiber.StatusNotFound,
+			checkResponse: func(t *testing.T, resp *response.Response) {
+				assert.False(t, resp.Success)
+				assert.Equal(t, "user not found", resp.Error)
+			},
+		},
+		{
+			name:   "service error returns 500",
+			userID: testUserID,
+			setupMock: func(m *MockUserService) {
+				m.On("Delete", mock.Anything, testUserID).Return(errors.New("database connection failed"))
+			},
+			expectedStatus: fiber.StatusInternalServerError,
+			checkResponse: func(t *testing.T, resp *response.Response) {
+				assert.False(t, resp.Success)
+				assert.Equal(t, "Failed to delete user", resp.Error)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			if tt.setupMock != nil {
+				tt.setupMock(mockService)
+			}
+			handler := NewUserHandler(mockService)
+			app := setupTestApp(handler)
+
+			req := httptest.NewRequest("DELETE", "/users/"+tt.userID, nil)
+
+			resp, err := app.Test(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.checkResponse != nil {
+				var respBody response.Response
+				err = json.NewDecoder(resp.Body).Decode(&respBody)
+				assert.NoError(t, err)
+				tt.checkResponse(t, &respBody)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_BulkCreate(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*MockUserService)
+		expectedStatus int
+		checkResponse  func(*testing.T, response.Response)
+	}{
+		{
+			name: "successful import returns 200 with summary",
+			setupMock: func(m *MockUserService) {
+				m.On("BulkCreate", mock.Anything, mock.Anything).
+					Return(&service.BulkCreateResult{Processed: 2, Created: 2}, nil)
+			},
+			expectedStatus: fiber.StatusOK,
+			checkResponse: func(t *testing.T, resp response.Response) {
+				assert.True(t, resp.Success)
+				data := resp.Data.(map[string]interface{})
+				assert.Equal(t, float64(2), data["created"])
+			},
+		},
+		{
+			name: "malformed payload returns 400 with partial result",
+			setupMock: func(m *MockUserService) {
+				m.On("BulkCreate", mock.Anything, mock.Anything).
+					Return(&service.BulkCreateResult{Processed: 1, Created: 1}, service.ErrInvalidBulkPayload)
+			},
+			expectedStatus: fiber.StatusBadRequest,
+			checkResponse: func(t *testing.T, resp response.Response) {
+				assert.False(t, resp.Success)
+				data := resp.Data.(map[string]interface{})
+				assert.Equal(t, float64(1), data["created"])
+			},
+		},
+		{
+			name: "too many items returns 413",
+			setupMock: func(m *MockUserService) {
+				m.On("BulkCreate", mock.Anything, mock.Anything).
+					Return(&service.BulkCreateResult{Processed: 10, Created: 10}, service.ErrBulkImportTooLarge)
+			},
+			expectedStatus: fiber.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			tt.setupMock(mockService)
+			handler := NewUserHandler(mockService)
+			app := setupTestApp(handler)
+
+			req := httptest.NewRequest("POST", "/users/bulk", bytes.NewReader([]byte(`[]`)))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var respBody response.Response
+			err = json.NewDecoder(resp.Body).Decode(&respBody)
+			assert.NoError(t, err)
+
+			if tt.checkResponse != nil {
+				tt.checkResponse(t, respBody)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_Create_AdminOnly(t *testing.T) {
+	tests := []struct {
+		name           string
+		role           string
+		expectedStatus int
+	}{
+		{name: "admin can create users", role: "admin", expectedStatus: fiber.StatusCreated},
+		{name: "regular user is forbidden", role: "user", expectedStatus: fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			if tt.expectedStatus == fiber.StatusCreated {
+				mockService.On("Create", mock.Anything, mock.AnythingOfType("*service.CreateUserInput")).
+					Return(&service.UserResponse{ID: "test-uuid", Name: "John Doe", Email: "john@example.com"}, nil)
+			}
+			handler := NewUserHandler(mockService)
+
+			validator.Init()
+			app := fiber.New()
+			app.Post("/users", func(c *fiber.Ctx) error {
+				c.Locals("user_id", "caller-uuid")
+				c.Locals("role", tt.role)
+				return c.Next()
+			}, middleware.RoleRequired("admin"), handler.Create)
+
+			body, _ := json.Marshal(map[string]string{
+				"name":     "John Doe",
+				"email":    "john@example.com",
+				"password": "Password123!",
+			})
+			req := httptest.NewRequest("POST", "/users", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_ExportPersonalData(t *testing.T) {
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService)
+
+	app := fiber.New()
+	app.Get("/users/me/export", func(c *fiber.Ctx) error {
+		c.Locals("user_id", "test-uuid")
+		return c.Next()
+	}, handler.ExportPersonalData)
+
+	mockService.On("ExportPersonalData", mock.Anything, "test-uuid").Return(&service.PersonalDataExport{
+		Profile:  service.PersonalDataProfile{ID: "test-uuid", Email: "john@example.com"},
+		Sessions: []service.PersonalDataSession{{ID: "session-1"}},
+	}, nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/me/export", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), "personal-data-test-uuid.json")
+
+	var bundle map[string]interface{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&bundle))
+	assert.Contains(t, bundle, "profile")
+	assert.Contains(t, bundle, "sessions")
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_Search(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(m *MockUserService)
+		expectedStatus int
+	}{
+		{
+			name:  "returns paginated matches",
+			query: "?q=+john@example.com+&page=2&per_page=5",
+			setupMock: func(m *MockUserService) {
+				m.On("Search", mock.Anything, "john@example.com", 2, 5).Return([]service.UserResponse{{ID: testUserID, Email: "john@example.com"}}, int64(6), nil)
+			},
+			expectedStatus: fiber.StatusOK,
+		},
+		{name: "missing q", query: "", expectedStatus: fiber.StatusBadRequest},
+		{name: "blank q", query: "?q=++", expectedStatus: fiber.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			if tt.setupMock != nil {
+				tt.setupMock(mockService)
+			}
+			handler := NewUserHandler(mockService)
+
+			app := fiber.New()
+			app.Get("/users/search", handler.Search)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/users/search"+tt.query, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedStatus == fiber.StatusOK {
+				var body struct {
+					Data response.PaginatedData `json:"data"`
+				}
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, int64(6), body.Data.Total)
+				assert.Equal(t, 2, body.Data.TotalPages)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_FindByEmail(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(m *MockUserService)
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:  "found",
+			query: "?email=john@example.com",
+			setupMock: func(m *MockUserService) {
+				m.On("FindByEmail", mock.Anything, "john@example.com").Return(&service.UserResponse{ID: testUserID, Email: "john@example.com"}, nil)
+			},
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:  "not found",
+			query: "?email=nobody@example.com",
+			setupMock: func(m *MockUserService) {
+				m.On("FindByEmail", mock.Anything, "nobody@example.com").Return(nil, service.ErrUserNotFound)
+			},
+			expectedStatus: fiber.StatusNotFound,
+			expectedCode:   response.CodeUserNotFound,
+		},
+		{name: "invalid email", query: "?email=not-an-email", expectedStatus: fiber.StatusBadRequest, expectedCode: "BAD_REQUEST"},
+		{name: "missing email", query: "", expectedStatus: fiber.StatusBadRequest, expectedCode: "BAD_REQUEST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			if tt.setupMock != nil {
+				tt.setupMock(mockService)
+			}
+			handler := NewUserHandler(mockService)
+
+			validator.Init()
+			app := fiber.New()
+			app.Get("/users/by-email", handler.FindByEmail)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/users/by-email"+tt.query, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var body response.Response
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, tt.expectedCode, body.Code)
+			if tt.expectedStatus == fiber.StatusOK {
+				assert.Equal(t, testUserID, body.Data.(map[string]interface{})["id"])
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_Export(t *testing.T) {
+	users := []service.UserResponse{
+		{ID: testUserID, Name: "John Doe", Email: "john@example.com", Role: "user", IsActive: true, CreatedAt: "2025-01-02T03:04:05Z"},
+	}
+	isActive := true
+
+	tests := []struct {
+		name                string
+		query               string
+		expectedStatus      int
+		expectedType        string
+		expectedDisposition string
+	}{
+		{name: "csv by default", query: "?is_active=true", expectedStatus: fiber.StatusOK, expectedType: "text/csv; charset=utf-8", expectedDisposition: `attachment; filename="users.csv"`},
+		{name: "json", query: "?is_active=true&format=json", expectedStatus: fiber.StatusOK, expectedType: fiber.MIMEApplicationJSONCharsetUTF8, expectedDisposition: `attachment; filename="users.json"`},
+		{name: "unknown format", query: "?format=xml", expectedStatus: fiber.StatusBadRequest},
+		{name: "invalid filter", query: "?is_active=maybe", expectedStatus: fiber.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			mockService.On("ExportUsers", mock.Anything, service.UserFilter{IsActive: &isActive}).Return(users, nil)
+			handler := NewUserHandler(mockService)
+
+			app := fiber.New()
+			app.Get("/users/export", handler.Export)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/users/export"+tt.query, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus != fiber.StatusOK {
+				mockService.AssertNotCalled(t, "ExportUsers", mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.Equal(t, tt.expectedType, resp.Header.Get(fiber.HeaderContentType))
+			assert.Equal(t, tt.expectedDisposition, resp.Header.Get(fiber.HeaderContentDisposition))
+
+			body, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			if strings.Contains(tt.query, "format=json") {
+				var exported []service.UserResponse
+				assert.NoError(t, json.Unmarshal(body, &exported))
+				assert.Equal(t, users, exported)
+				return
+			}
+
+			lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+			assert.Equal(t, []string{
+				"id,name,email,role,is_active,created_at",
+				testUserID + ",John Doe,john@example.com,user,true,2025-01-02T03:04:05Z",
+			}, lines)
+		})
+	}
+}
+
+func TestUserHandler_Export_EscapesFormulas(t *testing.T) {
+	users := []service.UserResponse{
+		{ID: testUserID, Name: "=HYPERLINK(\"http://evil.example\")", Email: "@sum@example.com", Role: "user", CreatedAt: "2025-01-02T03:04:05Z"},
+		{ID: testUserID, Name: "+1 Jane", Email: "-jane@example.com", Role: "user", CreatedAt: "2025-01-02T03:04:05Z"},
+	}
+	mockService := new(MockUserService)
+	mockService.On("ExportUsers", mock.Anything, service.UserFilter{}).Return(users, nil)
+	handler := NewUserHandler(mockService)
+
+	app := fiber.New()
+	app.Get("/users/export", handler.Export)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/export", nil))
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"'=HYPERLINK(\"http://evil.example\")", "'@sum@example.com"}, records[1][1:3])
+	assert.Equal(t, []string{"'+1 Jane", "'-jane@example.com"}, records[2][1:3])
+}
+
+func TestUserHandler_VerifyEmail(t *testing.T) {
+	tests := []struct {
+		name            string
+		token           string
+		serviceErr      error
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{name: "valid token", token: "good", expectedStatus: fiber.StatusOK},
+		{name: "unknown token", token: "bad", serviceErr: service.ErrInvalidToken, expectedStatus: fiber.StatusBadRequest, expectedMessage: "Invalid verification token"},
+		{name: "expired token", token: "old", serviceErr: service.ErrTokenExpired, expectedStatus: fiber.StatusBadRequest, expectedMessage: "Verification token has expired"},
+		{name: "reused token", token: "used", serviceErr: service.ErrTokenUsed, expectedStatus: fiber.StatusBadRequest, expectedMessage: "Verification token has already been used"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			mockService.On("VerifyEmail", mock.Anything, tt.token).Return(tt.serviceErr)
+			handler := NewUserHandler(mockService)
+
+			app := fiber.New()
+			app.Get("/auth/verify", handler.VerifyEmail)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/auth/verify?token="+tt.token, nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedMessage != "" {
+				var respBody response.Response
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+				assert.Equal(t, tt.expectedMessage, respBody.Error)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_ForgotPassword(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*MockUserService)
+		expectedStatus int
+	}{
+		{
+			name: "unknown email still returns 200",
+			body: `{"email":"nobody@example.com"}`,
+			setupMock: func(m *MockUserService) {
+				m.On("RequestPasswordReset", mock.Anything, "nobody@example.com").Return(nil)
+			},
+			expectedStatus: fiber.StatusOK,
+		},
+		{
+			name:           "invalid email returns 422",
+			body:           `{"email":"not-an-email"}`,
+			expectedStatus: fiber.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			if tt.setupMock != nil {
+				tt.setupMock(mockService)
+			}
+			handler := NewUserHandler(mockService)
+			validator.Init()
+			app := fiber.New()
+			app.Post("/auth/forgot-password", handler.ForgotPassword)
+
+			req := httptest.NewRequest("POST", "/auth/forgot-password", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_ResetPassword(t *testing.T) {
+	tests := []struct {
+		name            string
+		serviceErr      error
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{name: "valid token", expectedStatus: fiber.StatusOK},
+		{name: "expired token", serviceErr: service.ErrTokenExpired, expectedStatus: fiber.StatusBadRequest, expectedMessage: "Reset token has expired"},
+		{name: "reused token", serviceErr: service.ErrTokenUsed, expectedStatus: fiber.StatusBadRequest, expectedMessage: "Reset token has already been used"},
+		{name: "unknown token", serviceErr: service.ErrInvalidToken, expectedStatus: fiber.StatusBadRequest, expectedMessage: "Invalid reset token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			mockService.On("ResetPassword", mock.Anything, "reset-token", "New-passw0rd").Return(tt.serviceErr)
+			handler := NewUserHandler(mockService)
+			validator.Init()
+			app := fiber.New()
+			app.Post("/auth/reset-password", handler.ResetPassword)
+
+			req := httptest.NewRequest("POST", "/auth/reset-password", bytes.NewReader([]byte(`{"token":"reset-token","password":"New-passw0rd"}`)))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedMessage != "" {
+				var respBody response.Response
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+				assert.Equal(t, tt.expectedMessage, respBody.Error)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_Approve(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setupMock      func(*MockUserService)
+		expectedStatus int
+		expectedState  string
+	}{
+		{
+			name: "approve pending user",
+			path: "/users/" + testUserID + "/approve",
+			setupMock: func(m *MockUserService) {
+				m.On("ApproveUser", mock.Anything, testUserID).Return(&service.UserResponse{ID: testUserID, ApprovalStatus: "approved"}, nil)
+			},
+			expectedStatus: fiber.StatusOK,
+			expectedState:  "approved",
+		},
+		{
+			name: "reject pending user",
+			path: "/users/" + testUserID + "/reject",
+			setupMock: func(m *MockUserService) {
+				m.On("RejectUser", mock.Anything, testUserID).Return(&service.UserResponse{ID: testUserID, ApprovalStatus: "rejected"}, nil)
+			},
+			expectedStatus: fiber.StatusOK,
+			expectedState:  "rejected",
+		},
+		{
+			name: "user not pending returns 409",
+			path: "/users/" + testUserID + "/approve",
+			setupMock: func(m *MockUserService) {
+				m.On("ApproveUser", mock.Anything, testUserID).Return(nil, service.ErrNotPendingApproval)
+			},
+			expectedStatus: fiber.StatusConflict,
+		},
+		{
+			name: "concurrent decision returns 409",
+			path: "/users/" + testUserID + "/reject",
+			setupMock: func(m *MockUserService) {
+				m.On("RejectUser", mock.Anything, testUserID).Return(nil, service.ErrVersionConflict)
+			},
+			expectedStatus: fiber.StatusConflict,
+		},
+		{
+			name: "unknown user returns 404",
+			path: "/users/" + testUserID + "/reject",
+			setupMock: func(m *MockUserService) {
+				m.On("RejectUser", mock.Anything, testUserID).Return(nil, service.ErrUserNotFound)
+			},
+			expectedStatus: fiber.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			tt.setupMock(mockService)
+			handler := NewUserHandler(mockService)
+			app := fiber.New()
+			app.Post("/users/:id/approve", handler.Approve)
+			app.Post("/users/:id/reject", handler.Reject)
+
+			resp, err := app.Test(httptest.NewRequest("POST", tt.path, nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedState != "" {
+				var respBody response.Response
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+				assert.Equal(t, tt.expectedState, respBody.Data.(map[string]interface{})["approval_status"])
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserHandler_FindPending(t *testing.T) {
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService)
+	app := fiber.New()
+	app.Get("/users/pending", handler.FindPending)
+
+	filter := service.UserFilter{ApprovalStatus: "pending_approval"}
+	mockService.On("FindAll", mock.Anything, filter, service.Sort{Column: "created_at"}, 1, 10).
+		Return([]service.UserResponse{{ID: "pending-uuid", ApprovalStatus: "pending_approval"}}, int64(1), nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/pending", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	mockService.AssertExpectations(t)
 }
\ No newline at end of file
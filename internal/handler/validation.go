@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/ariam/my-api/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ValidationFormatHeader lets a client opt in to validation errors keyed by
+// field name by sending "map"; without it the flat list is returned.
+const ValidationFormatHeader = response.ValidationFormatHeader
+
+func validationError(c *fiber.Ctx, errs []validator.ErrorResponse) error {
+	if strings.EqualFold(c.Get(ValidationFormatHeader), "map") {
+		return response.ValidationErrorMap(c, validator.GroupByField(errs))
+	}
+	return response.ValidationError(c, errs)
+}
\ No newline at end of file
@@ -1,48 +1,194 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strings"
 
+	"github.com/ariam/my-api/internal/authz"
+	"github.com/ariam/my-api/internal/tenant"
 	"github.com/ariam/my-api/pkg/jwt"
+	"github.com/ariam/my-api/pkg/logger"
 	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
 )
 
-func Auth(jwtManager *jwt.JWTManager) fiber.Handler {
+// errSessionCheck is returned by authenticate when the session store could
+// not be read, which says nothing about the token, so it isn't answered 401.
+var errSessionCheck = errors.New("session check failed")
+
+// SessionChecker reports whether the server-side session a token was issued
+// for is still active.
+type SessionChecker interface {
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+// Auth requires a valid bearer token. When sessions is non-nil the token's
+// session must also still be active, so revoked tokens are rejected. The
+// token's tenant_id claim is bound to the request context, scoping the
+// repositories to that tenant.
+func Auth(jwtManager *jwt.JWTManager, sessions SessionChecker) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
 			return response.Unauthorized(c, "Missing authorization header")
 		}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			return response.Unauthorized(c, "Invalid authorization format")
+		if err := authenticate(c, jwtManager, sessions, authHeader); err != nil {
+			return authenticationFailed(c, err)
 		}
 
-		claims, err := jwtManager.Validate(parts[1])
-		if err != nil {
-			return response.Unauthorized(c, err.Error())
+		return c.Next()
+	}
+}
+
+// OptionalAuth populates the user locals when a bearer token is supplied but
+// lets anonymous requests through. A malformed or invalid token is still rejected.
+func OptionalAuth(jwtManager *jwt.JWTManager, sessions SessionChecker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authHeader := c.Get("Authorization")
+		if authHeader == "" {
+			return c.Next()
 		}
 
-		c.Locals("user_id", claims.UserID)
-		c.Locals("email", claims.Email)
-		c.Locals("role", claims.Role)
+		if err := authenticate(c, jwtManager, sessions, authHeader); err != nil {
+			return authenticationFailed(c, err)
+		}
 
 		return c.Next()
 	}
 }
 
+// WebSocketAuth authenticates a WebSocket handshake from the token query
+// parameter, since browsers cannot set headers on the upgrade request. Plain
+// HTTP requests get 426.
+func WebSocketAuth(jwtManager *jwt.JWTManager, sessions SessionChecker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(c) {
+			return response.Error(c, fiber.StatusUpgradeRequired, "WebSocket upgrade required")
+		}
+
+		token := c.Query("token")
+		if token == "" {
+			return response.Unauthorized(c, "Missing token")
+		}
+
+		if err := authenticate(c, jwtManager, sessions, "Bearer "+token); err != nil {
+			return authenticationFailed(c, err)
+		}
+
+		return c.Next()
+	}
+}
+
+func authenticate(c *fiber.Ctx, jwtManager *jwt.JWTManager, sessions SessionChecker, authHeader string) error {
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return errors.New("Invalid authorization format")
+	}
+
+	claims, err := jwtManager.Validate(parts[1])
+	if err != nil {
+		return err
+	}
+
+	if sessions != nil {
+		if claims.ID == "" {
+			return jwt.ErrInvalidToken
+		}
+		active, err := sessions.IsSessionActive(c.Context(), claims.ID)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errSessionCheck, err)
+		}
+		if !active {
+			return errors.New("Session has been revoked")
+		}
+		c.Locals("session_id", claims.ID)
+	}
+
+	c.Locals("user_id", claims.UserID)
+	c.Locals("email", claims.Email)
+	c.Locals("role", claims.Role)
+
+	// a token without a tenant_id claim acts within no tenant, not across all
+	tenantID, _ := claims.Extra["tenant_id"].(string)
+	c.Locals("tenant_id", tenantID)
+	c.SetUserContext(tenant.WithID(c.UserContext(), tenantID))
+
+	return nil
+}
+
+// authenticationFailed answers an authenticate error: 503 when the session
+// store failed, so clients retry instead of discarding a valid token, and 401
+// otherwise.
+func authenticationFailed(c *fiber.Ctx, err error) error {
+	if errors.Is(err, errSessionCheck) {
+		logger.WithContext(c.UserContext()).Error("Failed to check session", zap.Error(err))
+		return response.Error(c, fiber.StatusServiceUnavailable, "Unable to verify session, please try again")
+	}
+	return response.Unauthorized(c, err.Error())
+}
+
+// RoleRequired allows the request through when the authenticated user has one
+// of roles, directly or through authz.Hierarchy. It must run after Auth; a
+// request without a role is unauthenticated.
 func RoleRequired(roles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		userRole := c.Locals("role").(string)
+		userRole, ok := c.Locals("role").(string)
+		if !ok || userRole == "" {
+			return response.Unauthorized(c, "Authentication required")
+		}
 
 		for _, role := range roles {
-			if userRole == role {
+			if authz.HasRole(userRole, role) {
 				return c.Next()
 			}
 		}
 
 		return response.Forbidden(c, "Insufficient permissions")
 	}
+}
+
+// SelfOrRole allows the request through when the path parameter param is the
+// authenticated user's own ID, or when the user has one of roles. Other users'
+// records are forbidden rather than hidden, since their IDs are listed
+// elsewhere. It must run after Auth.
+func SelfOrRole(param string, roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userRole, ok := c.Locals("role").(string)
+		if !ok || userRole == "" {
+			return response.Unauthorized(c, "Authentication required")
+		}
+
+		if userID, _ := c.Locals("user_id").(string); userID != "" && userID == c.Params(param) {
+			return c.Next()
+		}
+		for _, role := range roles {
+			if authz.HasRole(userRole, role) {
+				return c.Next()
+			}
+		}
+
+		return response.Forbidden(c, "You can only access your own account")
+	}
+}
+
+// RequirePermission allows the request through when the authenticated user's
+// role grants perm. It must run after Auth.
+func RequirePermission(perm authz.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userRole, ok := c.Locals("role").(string)
+		if !ok || userRole == "" {
+			return response.Unauthorized(c, "Authentication required")
+		}
+
+		if !authz.Can(userRole, perm) {
+			return response.Forbidden(c, "Insufficient permissions")
+		}
+
+		return c.Next()
+	}
 }
\ No newline at end of file
@@ -0,0 +1,194 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ariam/my-api/internal/authz"
+	"github.com/ariam/my-api/internal/tenant"
+	"github.com/ariam/my-api/pkg/jwt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// guardedApp serves GET / behind guard, with role set as if Auth had run.
+func guardedApp(role interface{}, guard fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		if role != nil {
+			c.Locals("role", role)
+		}
+		return c.Next()
+	}, guard, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func TestRoleRequired(t *testing.T) {
+	tests := []struct {
+		name           string
+		role           interface{}
+		required       string
+		expectedStatus int
+	}{
+		{name: "no auth ran", required: "admin", expectedStatus: fiber.StatusUnauthorized},
+		{name: "empty role", role: "", required: "admin", expectedStatus: fiber.StatusUnauthorized},
+		{name: "insufficient role", role: "user", required: "admin", expectedStatus: fiber.StatusForbidden},
+		{name: "allowed role", role: "admin", required: "admin", expectedStatus: fiber.StatusOK},
+		{name: "admin implies user", role: "admin", required: "user", expectedStatus: fiber.StatusOK},
+		{name: "unknown role", role: "guest", required: "user", expectedStatus: fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := guardedApp(tt.role, RoleRequired(tt.required))
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name           string
+		role           interface{}
+		perm           authz.Permission
+		expectedStatus int
+	}{
+		{name: "no auth ran", perm: authz.UsersRead, expectedStatus: fiber.StatusUnauthorized},
+		{name: "granted directly", role: "user", perm: authz.UsersRead, expectedStatus: fiber.StatusOK},
+		{name: "granted through an implied role", role: "admin", perm: authz.UsersRead, expectedStatus: fiber.StatusOK},
+		{name: "not granted", role: "user", perm: authz.AuditRead, expectedStatus: fiber.StatusForbidden},
+		{name: "unknown role", role: "guest", perm: authz.UsersRead, expectedStatus: fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := guardedApp(tt.role, RequirePermission(tt.perm))
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+func TestSelfOrRole(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		role           string
+		path           string
+		expectedStatus int
+	}{
+		{name: "no auth ran", path: "/users/u1", expectedStatus: fiber.StatusUnauthorized},
+		{name: "own record", userID: "u1", role: "user", path: "/users/u1", expectedStatus: fiber.StatusOK},
+		{name: "another user's record", userID: "u1", role: "user", path: "/users/u2", expectedStatus: fiber.StatusForbidden},
+		{name: "admin on anyone", userID: "u1", role: "admin", path: "/users/u2", expectedStatus: fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/users/:id", func(c *fiber.Ctx) error {
+				if tt.role != "" {
+					c.Locals("user_id", tt.userID)
+					c.Locals("role", tt.role)
+				}
+				return c.Next()
+			}, SelfOrRole("id", "admin"), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestAuth_BindsTenant(t *testing.T) {
+	manager := jwt.NewJWTManager("test-secret-key-min-32-characters", 24)
+
+	app := fiber.New()
+	app.Get("/", Auth(manager, nil), func(c *fiber.Ctx) error {
+		id, ok := tenant.FromContext(c.UserContext())
+		if !ok {
+			return c.SendString("unbound")
+		}
+		return c.SendString("tenant=" + id)
+	})
+
+	tests := []struct {
+		name     string
+		extra    map[string]interface{}
+		expected string
+	}{
+		{name: "tenant claim", extra: map[string]interface{}{"tenant_id": "tenant-a"}, expected: "tenant=tenant-a"},
+		{name: "no tenant claim binds no tenant", expected: "tenant="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := manager.Generate("user-123", "test@example.com", "user", tt.extra)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			body, _ := io.ReadAll(resp.Body)
+			assert.Equal(t, tt.expected, string(body))
+		})
+	}
+}
+
+// sessionCheckerFunc adapts a function to SessionChecker.
+type sessionCheckerFunc func(ctx context.Context, sessionID string) (bool, error)
+
+func (f sessionCheckerFunc) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return f(ctx, sessionID)
+}
+
+func TestAuth_SessionCheck(t *testing.T) {
+	manager := jwt.NewJWTManager("test-secret-key-min-32-characters", 24)
+	token, err := manager.GenerateWithID("session-1", "user-123", "test@example.com", "user")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		active         bool
+		err            error
+		expectedStatus int
+	}{
+		{name: "active session", active: true, expectedStatus: fiber.StatusOK},
+		{name: "revoked session", active: false, expectedStatus: fiber.StatusUnauthorized},
+		{name: "session store unavailable", err: errors.New("connection refused"), expectedStatus: fiber.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := sessionCheckerFunc(func(ctx context.Context, sessionID string) (bool, error) {
+				assert.Equal(t, "session-1", sessionID)
+				return tt.active, tt.err
+			})
+			app := fiber.New()
+			app.Get("/", Auth(manager, sessions), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
\ No newline at end of file
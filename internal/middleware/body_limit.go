@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"io"
+
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit rejects request bodies over maxBytes with 413. The app streams
+// request bodies, so Fiber's own BodyLimit only decides when to stop
+// buffering; this enforces the cap before a handler reads the body into
+// memory. Bodies of unknown length are read up to the limit. exemptPaths are
+// routes whose handlers consume the body as a stream and bound it themselves;
+// they match the way routes do, ignoring case and a trailing slash. A
+// maxBytes of zero or less disables the limit.
+func BodyLimit(maxBytes int, exemptPaths ...string) fiber.Handler {
+	exempt := make(map[string]bool, len(exemptPaths))
+	for _, p := range exemptPaths {
+		exempt[normalizePath(p)] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		if maxBytes <= 0 || exempt[normalizePath(c.Path())] {
+			return c.Next()
+		}
+
+		req := c.Request()
+		if req.Header.ContentLength() > maxBytes {
+			return bodyTooLarge(c)
+		}
+
+		if req.IsBodyStream() {
+			body, err := io.ReadAll(io.LimitReader(req.BodyStream(), int64(maxBytes)+1))
+			if err != nil {
+				return response.BadRequest(c, "Failed to read request body")
+			}
+			if len(body) > maxBytes {
+				return bodyTooLarge(c)
+			}
+			req.SetBody(body)
+		}
+
+		return c.Next()
+	}
+}
+
+func bodyTooLarge(c *fiber.Ctx) error {
+	// the rest of the body is never read, so the connection can't be reused
+	c.Set(fiber.HeaderConnection, "close")
+	return response.Error(c, fiber.StatusRequestEntityTooLarge, "Request body too large")
+}
\ No newline at end of file
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyLimit(t *testing.T) {
+	const limit = 64
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		chunked        bool
+		expectedStatus int
+	}{
+		{name: "small body", path: "/users", body: `{"name":"Jane"}`, expectedStatus: fiber.StatusOK},
+		{name: "oversized body", path: "/users", body: strings.Repeat("x", 1024), expectedStatus: fiber.StatusRequestEntityTooLarge},
+		{name: "small chunked body", path: "/users", body: `{"name":"Jane"}`, chunked: true, expectedStatus: fiber.StatusOK},
+		{name: "oversized chunked body", path: "/users", body: strings.Repeat("x", 1024), chunked: true, expectedStatus: fiber.StatusRequestEntityTooLarge},
+		{name: "exempt path", path: "/users/bulk", body: strings.Repeat("x", 1024), expectedStatus: fiber.StatusOK},
+		{name: "exempt path with trailing slash", path: "/users/bulk/", body: strings.Repeat("x", 1024), expectedStatus: fiber.StatusOK},
+		{name: "exempt path in another case", path: "/Users/BULK", body: strings.Repeat("x", 1024), expectedStatus: fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{StreamRequestBody: true, BodyLimit: limit})
+			app.Use(BodyLimit(limit, "/users/bulk"))
+			echo := func(c *fiber.Ctx) error {
+				return c.Send(c.Body())
+			}
+			app.Post("/users", echo)
+			app.Post("/users/bulk", echo)
+
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+				req.TransferEncoding = []string{"chunked"}
+			}
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			raw, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			if tt.expectedStatus == fiber.StatusOK {
+				assert.Equal(t, tt.body, string(raw), "the handler sees the whole body")
+				return
+			}
+
+			var body response.Response
+			require.NoError(t, json.Unmarshal(raw, &body))
+			assert.False(t, body.Success)
+			assert.Equal(t, "Request body too large", body.Error)
+		})
+	}
+}
\ No newline at end of file
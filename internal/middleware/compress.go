@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"bytes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/valyala/fasthttp"
+)
+
+// precompressedTypes are compressible by content type prefix but already
+// compressed, so compressing them again only costs CPU.
+var precompressedTypes = [][]byte{
+	[]byte("application/gzip"),
+	[]byte("application/x-gzip"),
+	[]byte("application/zip"),
+	[]byte("application/zstd"),
+	[]byte("application/x-bzip2"),
+	[]byte("application/x-xz"),
+	[]byte("application/x-7z-compressed"),
+	[]byte("application/x-rar-compressed"),
+	[]byte("application/pdf"),
+}
+
+// Compression compresses responses with brotli, gzip or deflate, whichever
+// the client's Accept-Encoding prefers, at level. Responses smaller than
+// minBytes, already-compressed content types and upgraded connections are
+// sent as they are. Streamed responses are always compressed since their size
+// isn't known up front.
+func Compression(level compress.Level, minBytes int) fiber.Handler {
+	var compressor fasthttp.RequestHandler
+	noop := func(*fasthttp.RequestCtx) {}
+	switch level {
+	case compress.LevelBestSpeed:
+		compressor = fasthttp.CompressHandlerBrotliLevel(noop, fasthttp.CompressBrotliBestSpeed, fasthttp.CompressBestSpeed)
+	case compress.LevelBestCompression:
+		compressor = fasthttp.CompressHandlerBrotliLevel(noop, fasthttp.CompressBrotliBestCompression, fasthttp.CompressBestCompression)
+	default:
+		compressor = fasthttp.CompressHandlerBrotliLevel(noop, fasthttp.CompressBrotliDefaultCompression, fasthttp.CompressDefaultCompression)
+	}
+
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		resp := c.Response()
+		if resp.StatusCode() == fiber.StatusSwitchingProtocols {
+			return nil
+		}
+		if !resp.IsBodyStream() && len(resp.Body()) < minBytes {
+			return nil
+		}
+		contentType := resp.Header.ContentType()
+		for _, t := range precompressedTypes {
+			if bytes.HasPrefix(contentType, t) {
+				return nil
+			}
+		}
+
+		compressor(c.Context())
+		return nil
+	}
+}
\ No newline at end of file
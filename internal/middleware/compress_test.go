@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompression(t *testing.T) {
+	large := `{"data":"` + strings.Repeat("x", 4096) + `"}`
+
+	app := fiber.New()
+	app.Use(Compression(compress.LevelDefault, 1024))
+	app.Get("/large", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return c.SendString(large)
+	})
+	app.Get("/small", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"ok": true})
+	})
+	app.Get("/archive", func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, "application/zip")
+		return c.SendString(large)
+	})
+
+	t.Run("large body is gzipped", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/large", nil)
+		req.Header.Set(fiber.HeaderAcceptEncoding, "gzip")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, "gzip", resp.Header.Get(fiber.HeaderContentEncoding))
+		zr, err := gzip.NewReader(resp.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.Equal(t, large, string(body))
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+	}{
+		{name: "client without Accept-Encoding", path: "/large"},
+		{name: "small body", path: "/small", acceptEncoding: "gzip"},
+		{name: "already compressed content type", path: "/archive", acceptEncoding: "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set(fiber.HeaderAcceptEncoding, tt.acceptEncoding)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			assert.Empty(t, resp.Header.Get(fiber.HeaderContentEncoding))
+		})
+	}
+}
\ No newline at end of file
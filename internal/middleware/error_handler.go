@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// ErrorHandler renders errors returned by handlers in the standard envelope:
+// *response.APIError with its status and code, *fiber.Error with its status,
+// and anything else as a 500. The full error is always logged, but outside
+// development a 5xx only tells the client "Internal server error" and the
+// request ID, so database errors and other internals don't leak. 4xx
+// messages are sent as they are.
+func ErrorHandler(env string) fiber.ErrorHandler {
+	detailed := env == "development"
+
+	return func(c *fiber.Ctx, err error) error {
+		var apiErr *response.APIError
+		if errors.As(err, &apiErr) {
+			return response.ErrorCode(c, apiErr.Status, apiErr.Code, apiErr.Message)
+		}
+
+		code := fiber.StatusInternalServerError
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			code = fiberErr.Code
+		}
+		if code == fiber.StatusMethodNotAllowed {
+			return MethodNotAllowed(c)
+		}
+
+		logger.WithContext(c.UserContext()).Error("Unhandled error",
+			zap.Error(err),
+			zap.String("path", c.Path()),
+			zap.String("method", c.Method()),
+		)
+
+		message := err.Error()
+		if code >= fiber.StatusInternalServerError && !detailed {
+			message = "Internal server error"
+		}
+		return response.Error(c, code, message)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorHandler(t *testing.T) {
+	dbErr := errors.New(`pq: relation "users" does not exist`)
+
+	tests := []struct {
+		name            string
+		env             string
+		err             error
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{name: "5xx is generic in production", env: "production", err: dbErr, expectedStatus: fiber.StatusInternalServerError, expectedMessage: "Internal server error"},
+		{name: "5xx is generic in staging", env: "staging", err: fiber.NewError(fiber.StatusBadGateway, "upstream 10.0.0.3 refused"), expectedStatus: fiber.StatusBadGateway, expectedMessage: "Internal server error"},
+		{name: "5xx is detailed in development", env: "development", err: dbErr, expectedStatus: fiber.StatusInternalServerError, expectedMessage: dbErr.Error()},
+		{name: "4xx is kept in production", env: "production", err: fiber.NewError(fiber.StatusBadRequest, "Invalid cursor"), expectedStatus: fiber.StatusBadRequest, expectedMessage: "Invalid cursor"},
+		{name: "APIError is kept in production", env: "production", err: &response.APIError{Status: fiber.StatusConflict, Code: "CONFLICT", Message: "Already exists"}, expectedStatus: fiber.StatusConflict, expectedMessage: "Already exists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler(tt.env)})
+			app.Use(requestid.New())
+			app.Get("/", func(c *fiber.Ctx) error { return tt.err })
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var body response.Response
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, tt.expectedMessage, body.Error)
+			assert.NotEmpty(t, body.RequestID)
+			assert.Equal(t, resp.Header.Get(fiber.HeaderXRequestID), body.RequestID)
+		})
+	}
+}
\ No newline at end of file
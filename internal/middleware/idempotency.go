@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyPruneThreshold = 1024
+
+	// idempotencyInFlightTTL bounds how long a key stays reserved if the
+	// instance handling it dies before storing the response.
+	idempotencyInFlightTTL = 5 * time.Minute
+)
+
+// IdempotencyRecord is a stored response, replayed for repeats of the request
+// that produced it. InFlight marks a key reserved by a request that is still
+// running; only BodyHash is set on such a record.
+type IdempotencyRecord struct {
+	BodyHash    string
+	InFlight    bool
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// IdempotencyStore keeps responses by idempotency key until they expire. Get
+// returns nil, nil for unknown or expired keys. Reserve stores record only if
+// key is unknown or expired, like SETNX, and reports whether it did; Delete
+// removes a key.
+type IdempotencyStore interface {
+	Get(ctx context.Context, key string) (*IdempotencyRecord, error)
+	Set(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error
+	Reserve(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type memoryIdempotencyEntry struct {
+	record    IdempotencyRecord
+	expiresAt time.Time
+}
+
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryIdempotencyEntry
+}
+
+// NewMemoryIdempotencyStore keeps records in process memory, so replays only
+// work while requests reach the same instance.
+func NewMemoryIdempotencyStore() IdempotencyStore {
+	return &memoryIdempotencyStore{entries: make(map[string]memoryIdempotencyEntry)}
+}
+
+func (s *memoryIdempotencyStore) Get(ctx context.Context, key string) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, nil
+	}
+	record := entry.record
+	return &record, nil
+}
+
+func (s *memoryIdempotencyStore) Set(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(key, record, ttl)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Reserve(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok && !time.Now().After(entry.expiresAt) {
+		return false, nil
+	}
+	s.set(key, record, ttl)
+	return true, nil
+}
+
+func (s *memoryIdempotencyStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// set stores record; s.mu must be held.
+func (s *memoryIdempotencyStore) set(key string, record IdempotencyRecord, ttl time.Duration) {
+	now := time.Now()
+	if len(s.entries) >= idempotencyPruneThreshold {
+		for k, entry := range s.entries {
+			if now.After(entry.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+	}
+	s.entries[key] = memoryIdempotencyEntry{record: record, expiresAt: now.Add(ttl)}
+}
+
+// Idempotency replays the stored response when a request repeats an
+// Idempotency-Key already seen on the same route, instead of running the
+// handler again. Keys are scoped per route, query string and authenticated
+// user. Reusing
+// a key with a different body is rejected with 409. The key is reserved
+// before the handler runs, so a repeat arriving while the first request is
+// still running gets 409 too rather than running the handler a second time.
+// Server errors are not stored, so a request that failed with 5xx can be
+// retried with the same key. Requests without the header pass through.
+func Idempotency(store IdempotencyStore, ttl time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return response.BadRequest(c, "Idempotency-Key is too long")
+		}
+
+		ctx := c.UserContext()
+		storeKey := idempotencyScope(c) + "|" + key
+		sum := sha256.Sum256(c.Body())
+		bodyHash := hex.EncodeToString(sum[:])
+
+		record, err := store.Get(ctx, storeKey)
+		if err == nil && record == nil {
+			var reserved bool
+			reserved, err = store.Reserve(ctx, storeKey, IdempotencyRecord{BodyHash: bodyHash, InFlight: true}, idempotencyInFlightTTL)
+			if err == nil && !reserved {
+				// another request took the key since the Get
+				record, err = store.Get(ctx, storeKey)
+				if err == nil && record == nil {
+					record = &IdempotencyRecord{BodyHash: bodyHash, InFlight: true}
+				}
+			}
+		}
+		if err != nil {
+			logger.WithContext(ctx).Error("Failed to read idempotency record", zap.Error(err))
+			return response.InternalServerError(c, "Failed to process request")
+		}
+		if record != nil {
+			if record.BodyHash != bodyHash {
+				return response.Error(c, fiber.StatusConflict, "Idempotency-Key was already used with a different request body")
+			}
+			if record.InFlight {
+				return response.Error(c, fiber.StatusConflict, "A request with this Idempotency-Key is still being processed")
+			}
+			c.Set(IdempotentReplayedHeader, "true")
+			c.Set(fiber.HeaderContentType, record.ContentType)
+			return c.Status(record.Status).Send(record.Body)
+		}
+
+		if err := c.Next(); err != nil {
+			releaseIdempotencyKey(ctx, store, storeKey)
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			releaseIdempotencyKey(ctx, store, storeKey)
+			return nil
+		}
+		err = store.Set(ctx, storeKey, IdempotencyRecord{
+			BodyHash:    bodyHash,
+			Status:      status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}, ttl)
+		if err != nil {
+			// the request itself succeeded; only a retry would run it again
+			logger.WithContext(ctx).Error("Failed to store idempotency record", zap.Error(err))
+			releaseIdempotencyKey(ctx, store, storeKey)
+		}
+		return nil
+	}
+}
+
+// releaseIdempotencyKey drops the reservation of a request whose response
+// won't be stored, so the key can be retried.
+func releaseIdempotencyKey(ctx context.Context, store IdempotencyStore, key string) {
+	if err := store.Delete(ctx, key); err != nil {
+		// the key stays blocked until the reservation expires
+		logger.WithContext(ctx).Error("Failed to release idempotency key", zap.Error(err))
+	}
+}
+
+// idempotencyScope keys a request by method, route, query and user. The query
+// is part of it because it can change what the request does: a
+// ?validate_only=true dry run must not be replayed for the real request.
+func idempotencyScope(c *fiber.Ctx) string {
+	scope := c.Method() + " " + c.Route().Path
+	if query := c.Request().URI().QueryString(); len(query) > 0 {
+		scope += "?" + string(query)
+	}
+	if userID, ok := c.Locals("user_id").(string); ok {
+		scope += "|user:" + userID
+	}
+	return scope
+}
\ No newline at end of file
@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdempotencyApp(calls *int) *fiber.App {
+	app := fiber.New()
+	app.Post("/users", Idempotency(NewMemoryIdempotencyStore(), time.Hour), func(c *fiber.Ctx) error {
+		*calls++
+		return response.Created(c, fiber.Map{"call": *calls})
+	})
+	return app
+}
+
+func postWithKey(t *testing.T, app *fiber.App, key, body string) (int, string, string) {
+	t.Helper()
+
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(respBody), resp.Header.Get(IdempotentReplayedHeader)
+}
+
+func TestIdempotency_ReplaysResponse(t *testing.T) {
+	calls := 0
+	app := newIdempotencyApp(&calls)
+	body := `{"name":"Jane","email":"jane@example.com"}`
+
+	status, first, replayed := postWithKey(t, app, "key-1", body)
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.Empty(t, replayed)
+
+	status, second, replayed := postWithKey(t, app, "key-1", body)
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.Equal(t, first, second)
+	assert.Equal(t, "true", replayed)
+	assert.Equal(t, 1, calls, "the handler runs once per key")
+
+	postWithKey(t, app, "key-2", body)
+	postWithKey(t, app, "", body)
+	assert.Equal(t, 3, calls, "new keys and requests without a key run the handler")
+}
+
+func TestIdempotency_BodyMismatch(t *testing.T) {
+	calls := 0
+	app := newIdempotencyApp(&calls)
+
+	postWithKey(t, app, "key-1", `{"name":"Jane","email":"jane@example.com"}`)
+	status, body, _ := postWithKey(t, app, "key-1", `{"name":"John","email":"john@example.com"}`)
+
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Contains(t, body, "different request body")
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_ScopedByQuery(t *testing.T) {
+	calls := 0
+	app := fiber.New()
+	app.Post("/users", Idempotency(NewMemoryIdempotencyStore(), time.Hour), func(c *fiber.Ctx) error {
+		calls++
+		if c.QueryBool("validate_only") {
+			return response.Success(c, fiber.Map{"valid": true})
+		}
+		return response.Created(c, fiber.Map{"call": calls})
+	})
+	body := `{"name":"Jane","email":"jane@example.com"}`
+
+	req := httptest.NewRequest("POST", "/users?validate_only=true", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// the real request with the same key is not answered with the dry run
+	status, _, replayed := postWithKey(t, app, "key-1", body)
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.Empty(t, replayed)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_ServerErrorsAreNotStored(t *testing.T) {
+	calls := 0
+	app := fiber.New()
+	app.Post("/users", Idempotency(NewMemoryIdempotencyStore(), time.Hour), func(c *fiber.Ctx) error {
+		calls++
+		return response.InternalServerError(c, "Failed to create user")
+	})
+
+	postWithKey(t, app, "key-1", `{}`)
+	status, _, replayed := postWithKey(t, app, "key-1", `{}`)
+
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Empty(t, replayed)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_ConcurrentRepeat(t *testing.T) {
+	calls := 0
+	started, release := make(chan struct{}), make(chan struct{})
+	app := fiber.New()
+	app.Post("/users", Idempotency(NewMemoryIdempotencyStore(), time.Hour), func(c *fiber.Ctx) error {
+		calls++
+		close(started)
+		<-release
+		return response.Created(c, fiber.Map{"call": calls})
+	})
+	body := `{"name":"Jane","email":"jane@example.com"}`
+
+	done := make(chan int)
+	go func() {
+		status, _, _ := postWithKey(t, app, "key-1", body)
+		done <- status
+	}()
+	<-started
+
+	status, respBody, _ := postWithKey(t, app, "key-1", body)
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Contains(t, respBody, "still being processed")
+
+	close(release)
+	assert.Equal(t, fiber.StatusCreated, <-done)
+
+	status, _, replayed := postWithKey(t, app, "key-1", body)
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.Equal(t, "true", replayed)
+	assert.Equal(t, 1, calls)
+}
+
+func TestMemoryIdempotencyStore_Reserve(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	ctx := t.Context()
+
+	reserved, err := store.Reserve(ctx, "key", IdempotencyRecord{InFlight: true}, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+
+	reserved, err = store.Reserve(ctx, "key", IdempotencyRecord{InFlight: true}, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, reserved, "a reserved key can't be taken again")
+
+	require.NoError(t, store.Delete(ctx, "key"))
+	reserved, err = store.Reserve(ctx, "key", IdempotencyRecord{InFlight: true}, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, reserved, "a released key can be reserved")
+}
+
+func TestMemoryIdempotencyStore_Expires(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	ctx := t.Context()
+
+	require.NoError(t, store.Set(ctx, "key", IdempotencyRecord{Status: fiber.StatusCreated}, -time.Second))
+
+	record, err := store.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.Nil(t, record)
+}
\ No newline at end of file
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LimiterStore holds the counters of RateLimit when they are shared between
+// instances. Counts are kept per fixed window; RateLimit weighs the previous
+// window's count to approximate a sliding one.
+type LimiterStore interface {
+	// Hit counts a hit on key in window and returns the hits counted in it and
+	// in the window before it. Counters expire after ttl.
+	Hit(ctx context.Context, key string, window int64, ttl time.Duration) (current, previous int64, err error)
+}
+
+// LimiterStorage returns the counter store for the rate limiter called name.
+// With a Redis client the counts are shared by every instance, so the limit
+// holds across replicas; with nil, each instance counts in its own memory.
+func LimiterStorage(rdb *redis.Client, name string) LimiterStore {
+	if rdb == nil {
+		return nil
+	}
+	// limiters key by IP or user ID alike, so each needs its own namespace
+	return &redisLimiterStore{client: rdb, prefix: "ratelimit:" + name + ":"}
+}
+
+// redisLimiterStore counts with INCR, so concurrent hits from any number of
+// instances are all counted. It uses a shared client, which it does not own
+// and so never closes.
+type redisLimiterStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func (s *redisLimiterStore) Hit(ctx context.Context, key string, window int64, ttl time.Duration) (int64, int64, error) {
+	currentKey := s.prefix + key + ":" + strconv.FormatInt(window, 10)
+	previousKey := s.prefix + key + ":" + strconv.FormatInt(window-1, 10)
+
+	var incr *redis.IntCmd
+	var prev *redis.StringCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, currentKey)
+		pipe.Expire(ctx, currentKey, ttl)
+		prev = pipe.Get(ctx, previousKey)
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, 0, err
+	}
+
+	previous, err := prev.Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, 0, err
+	}
+	return incr.Val(), previous, nil
+}
\ No newline at end of file
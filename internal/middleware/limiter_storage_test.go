@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterStorage_SharedAcrossInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	// two replicas of the same service, each with its own limiter middleware
+	newInstance := func() *fiber.App {
+		app := fiber.New()
+		app.Get("/", RateLimit(3, time.Minute, UserOrIPKey, LimiterStorage(rdb, "global")), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+		return app
+	}
+	instances := []*fiber.App{newInstance(), newInstance()}
+
+	get := func(app *fiber.App) int {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, fiber.StatusOK, get(instances[i%2]))
+	}
+	assert.Equal(t, fiber.StatusTooManyRequests, get(instances[0]))
+	assert.Equal(t, fiber.StatusTooManyRequests, get(instances[1]))
+
+	assert.NotEmpty(t, server.Keys(), "counts live in Redis")
+	for _, key := range server.Keys() {
+		assert.Contains(t, key, "ratelimit:global:")
+	}
+}
+
+func TestLimiterStorage_ConcurrentHitsAreAllCounted(t *testing.T) {
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	apps := make([]*fiber.App, 2)
+	for i := range apps {
+		apps[i] = fiber.New()
+		apps[i].Get("/", RateLimit(5, time.Minute, UserOrIPKey, LimiterStorage(rdb, "global")), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+	}
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(app *fiber.App) {
+			defer wg.Done()
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			if err == nil && resp.StatusCode == fiber.StatusOK {
+				allowed.Add(1)
+			}
+		}(apps[i%2])
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(5), allowed.Load())
+}
+
+func TestLimiterStorage_FallsBackToMemoryWhenRedisFails(t *testing.T) {
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { rdb.Close() })
+
+	app := fiber.New()
+	app.Get("/", RateLimit(2, time.Minute, UserOrIPKey, LimiterStorage(rdb, "global")), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	get := func() int {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	server.Close()
+
+	assert.Equal(t, fiber.StatusOK, get(), "an outage doesn't reject requests")
+	assert.Equal(t, fiber.StatusOK, get())
+	assert.Equal(t, fiber.StatusTooManyRequests, get(), "the limit still holds in memory")
+}
+
+func TestLimiterStorage_NilClientUsesMemory(t *testing.T) {
+	assert.Nil(t, LimiterStorage(nil, "global"))
+}
\ No newline at end of file
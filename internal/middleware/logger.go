@@ -1,6 +1,9 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/ariam/my-api/pkg/logger"
@@ -8,13 +11,27 @@ import (
 	"go.uber.org/zap"
 )
 
+// RequestContext stores the request ID set by the requestid middleware in the
+// request's context.Context, so code below the handlers can log it with
+// logger.WithContext. It must be registered after requestid.New.
+func RequestContext() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if id := c.GetRespHeader(fiber.HeaderXRequestID); id != "" {
+			c.SetUserContext(logger.ContextWithRequestID(c.UserContext(), id))
+		}
+		return c.Next()
+	}
+}
+
+// RequestLogger logs every request once it has been handled. Requests that
+// passed the auth middleware further down the chain are logged with user_id.
 func RequestLogger() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
 
 		err := c.Next()
 
-		logger.Info("HTTP Request",
+		fields := []zap.Field{
 			zap.String("request_id", c.GetRespHeader("X-Request-ID")),
 			zap.String("method", c.Method()),
 			zap.String("path", c.Path()),
@@ -22,8 +39,101 @@ func RequestLogger() fiber.Handler {
 			zap.Duration("latency", time.Since(start)),
 			zap.String("ip", c.IP()),
 			zap.String("user_agent", c.Get("User-Agent")),
+		}
+		if userID, ok := c.Locals("user_id").(string); ok && userID != "" {
+			fields = append(fields, zap.String("user_id", userID))
+		}
+		logger.Info("HTTP Request", fields...)
+
+		return err
+	}
+}
+
+// redactedFields are masked wherever they appear in a logged JSON body.
+var redactedFields = map[string]bool{
+	"password": true,
+	"token":    true,
+	"secret":   true,
+}
+
+// BodyLogger logs request and response bodies at debug level, redacting
+// sensitive fields and keeping at most maxBytes of each body. The app
+// streams request bodies, so the request body is taken once the handler has
+// run: by then BodyLimit or the handler has read it into memory, within the
+// body limit. Bodies still streaming at that point, such as a bulk import the
+// handler consumed as a stream, are never buffered; only a placeholder is
+// logged for them.
+func BodyLogger(maxBytes int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !logger.Get().Core().Enabled(zap.DebugLevel) {
+			return c.Next()
+		}
+
+		err := c.Next()
+
+		reqBody := "[streamed]"
+		if !c.Request().IsBodyStream() {
+			reqBody = formatBody(c.Request().Body(), maxBytes)
+		}
+
+		respBody := "[streamed]"
+		if !c.Response().IsBodyStream() {
+			respBody = formatBody(c.Response().Body(), maxBytes)
+		}
+
+		logger.Debug("HTTP Body",
+			zap.String("request_id", c.GetRespHeader("X-Request-ID")),
+			zap.String("method", c.Method()),
+			zap.String("path", c.Path()),
+			zap.Int("status", c.Response().StatusCode()),
+			zap.String("request_body", reqBody),
+			zap.String("response_body", respBody),
 		)
 
 		return err
 	}
+}
+
+// formatBody redacts a JSON body and truncates the result to maxBytes.
+// Non-JSON bodies are not logged because they cannot be redacted reliably.
+func formatBody(body []byte, maxBytes int) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "[non-JSON body omitted]"
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(redact(data)); err != nil {
+		return "[unloggable body omitted]"
+	}
+
+	out := bytes.TrimRight(buf.Bytes(), "\n")
+	if maxBytes > 0 && len(out) > maxBytes {
+		return string(out[:maxBytes]) + "...[truncated]"
+	}
+	return string(out)
+}
+
+func redact(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, field := range val {
+			if redactedFields[strings.ToLower(k)] {
+				val[k] = "[REDACTED]"
+				continue
+			}
+			val[k] = redact(field)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = redact(item)
+		}
+	}
+	return v
 }
\ No newline at end of file
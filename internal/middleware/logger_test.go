@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ariam/my-api/pkg/jwt"
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFormatBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		maxBytes int
+		expected string
+	}{
+		{name: "empty body", body: "", maxBytes: 100, expected: ""},
+		{name: "sensitive fields are redacted", body: `{"email":"a@b.c","password":"hunter22","Token":"abc"}`, maxBytes: 100, expected: `{"Token":"[REDACTED]","email":"a@b.c","password":"[REDACTED]"}`},
+		{name: "nested fields are redacted", body: `{"data":[{"secret":"s"}]}`, maxBytes: 100, expected: `{"data":[{"secret":"[REDACTED]"}]}`},
+		{name: "long bodies are truncated", body: `{"name":"abcdefghij"}`, maxBytes: 10, expected: `{"name":"a...[truncated]`},
+		{name: "non-JSON bodies are omitted", body: "password=hunter22", maxBytes: 100, expected: "[non-JSON body omitted]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatBody([]byte(tt.body), tt.maxBytes))
+		})
+	}
+}
+
+func TestBodyLogger_PreservesStreamedResponse(t *testing.T) {
+	payload := strings.Repeat("x", 64*1024)
+
+	app := fiber.New()
+	app.Use(BodyLogger(16))
+	app.Get("/stream", func(c *fiber.Ctx) error {
+		return c.SendStream(bytes.NewReader([]byte(payload)))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/stream", nil))
+	assert.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, payload, string(body))
+}
+
+func TestBodyLogger_StreamedRequestBody(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	t.Cleanup(logger.Replace(zap.New(core)))
+
+	app := fiber.New(fiber.Config{StreamRequestBody: true})
+	app.Use(BodyLogger(1024))
+	app.Post("/users", BodyLimit(1024), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusCreated)
+	})
+	app.Post("/users/bulk", func(c *fiber.Ctx) error {
+		_, err := io.Copy(io.Discard, c.Context().RequestBodyStream())
+		if err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for _, path := range []string{"/users", "/users/bulk"} {
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"name":"Jane","password":"hunter22"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		_, err := app.Test(req)
+		require.NoError(t, err)
+	}
+
+	entries := logs.FilterMessage("HTTP Body").All()
+	require.Len(t, entries, 2)
+	assert.Equal(t, `{"name":"Jane","password":"[REDACTED]"}`, entries[0].ContextMap()["request_body"])
+	assert.Equal(t, "[streamed]", entries[1].ContextMap()["request_body"], "a body the handler streamed is not buffered")
+}
+
+func TestRequestContext_PropagatesRequestID(t *testing.T) {
+	app := fiber.New()
+	app.Use(requestid.New())
+	app.Use(RequestContext())
+
+	var seen string
+	app.Get("/ping", func(c *fiber.Ctx) error {
+		seen = logger.RequestIDFromContext(c.UserContext())
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest("GET", "/ping", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "req-abc")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "req-abc", resp.Header.Get(fiber.HeaderXRequestID))
+	assert.Equal(t, "req-abc", seen)
+}
+func TestRequestLogger_UserID(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	t.Cleanup(logger.Replace(zap.New(core)))
+
+	jwtManager := jwt.NewJWTManager("test-secret", 1)
+	app := fiber.New()
+	app.Use(RequestLogger())
+	app.Get("/public", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	app.Get("/private", Auth(jwtManager, nil), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	token, err := jwtManager.Generate("user-123", "john@example.com", "user")
+	require.NoError(t, err)
+	req := httptest.NewRequest("GET", "/private", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	_, err = app.Test(req)
+	require.NoError(t, err)
+
+	_, err = app.Test(httptest.NewRequest("GET", "/public", nil))
+	require.NoError(t, err)
+
+	entries := logs.FilterMessage("HTTP Request").All()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "user-123", entries[0].ContextMap()["user_id"])
+	assert.NotContains(t, entries[1].ContextMap(), "user_id", "anonymous requests omit user_id")
+}
\ No newline at end of file
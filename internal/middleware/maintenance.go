@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Maintenance modes: read-only rejects writes and still serves reads; full
+// rejects every request.
+const (
+	MaintenanceOff      = "off"
+	MaintenanceReadOnly = "read_only"
+	MaintenanceFull     = "full"
+)
+
+var ErrInvalidMaintenanceMode = errors.New("maintenance mode must be one of off, read_only, full")
+
+// Maintenance holds the current maintenance mode. It is safe for concurrent
+// use and is local to the process: with several instances, each is switched
+// separately.
+type Maintenance struct {
+	mode atomic.Value
+}
+
+// NewMaintenance starts in mode. It panics if mode is invalid; config.Load
+// rejects such values before they get here.
+func NewMaintenance(mode string) *Maintenance {
+	m := &Maintenance{}
+	if err := m.SetMode(mode); err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (m *Maintenance) Mode() string {
+	return m.mode.Load().(string)
+}
+
+func (m *Maintenance) SetMode(mode string) error {
+	switch mode {
+	case MaintenanceOff, MaintenanceReadOnly, MaintenanceFull:
+		m.mode.Store(mode)
+		return nil
+	}
+	return ErrInvalidMaintenanceMode
+}
+
+// MaintenanceMode rejects requests with 503 and Retry-After while m is in
+// maintenance: writes in read-only mode, everything in full mode. GET, HEAD
+// and OPTIONS count as reads. /health and exemptPaths, such as login and the
+// endpoint that switches the mode back off, are always let through. Paths are
+// matched as Fiber routes them by default: ignoring case and a trailing slash.
+func MaintenanceMode(m *Maintenance, retryAfter time.Duration, exemptPaths ...string) fiber.Handler {
+	exempt := make(map[string]bool, len(exemptPaths))
+	for _, p := range exemptPaths {
+		exempt[normalizePath(p)] = true
+	}
+	retryAfterSeconds := strconv.Itoa(int(retryAfter.Seconds()))
+
+	return func(c *fiber.Ctx) error {
+		mode := m.Mode()
+		if mode == MaintenanceOff || exempt[normalizePath(c.Path())] || isHealthPath(c.Path()) {
+			return c.Next()
+		}
+
+		message := "The API is down for maintenance"
+		if mode == MaintenanceReadOnly {
+			switch c.Method() {
+			case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+				return c.Next()
+			}
+			message = "The API is read-only during maintenance"
+		}
+
+		c.Set(fiber.HeaderRetryAfter, retryAfterSeconds)
+		return response.ErrorCode(c, fiber.StatusServiceUnavailable, response.CodeMaintenance, message)
+	}
+}
+
+func isHealthPath(path string) bool {
+	return path == "/health" || strings.HasPrefix(path, "/health/")
+}
+
+func normalizePath(path string) string {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return strings.ToLower(path)
+}
\ No newline at end of file
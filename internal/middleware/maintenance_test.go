@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{name: "off allows writes", mode: MaintenanceOff, method: "POST", path: "/users", expectedStatus: fiber.StatusOK},
+		{name: "read-only rejects POST", mode: MaintenanceReadOnly, method: "POST", path: "/users", expectedStatus: fiber.StatusServiceUnavailable},
+		{name: "read-only rejects DELETE", mode: MaintenanceReadOnly, method: "DELETE", path: "/users", expectedStatus: fiber.StatusServiceUnavailable},
+		{name: "read-only serves GET", mode: MaintenanceReadOnly, method: "GET", path: "/users", expectedStatus: fiber.StatusOK},
+		{name: "full rejects GET", mode: MaintenanceFull, method: "GET", path: "/users", expectedStatus: fiber.StatusServiceUnavailable},
+		{name: "full serves health", mode: MaintenanceFull, method: "GET", path: "/health/ready", expectedStatus: fiber.StatusOK},
+		{name: "full serves exempt path", mode: MaintenanceFull, method: "POST", path: "/maintenance", expectedStatus: fiber.StatusOK},
+		{name: "full serves exempt path with trailing slash", mode: MaintenanceFull, method: "PUT", path: "/maintenance/", expectedStatus: fiber.StatusOK},
+		{name: "read-only serves login", mode: MaintenanceReadOnly, method: "POST", path: "/auth/login", expectedStatus: fiber.StatusOK},
+		{name: "full serves login", mode: MaintenanceFull, method: "POST", path: "/auth/login", expectedStatus: fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(MaintenanceMode(NewMaintenance(tt.mode), 2*time.Minute, "/maintenance", "/auth/login"))
+			ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+			app.All("/users", ok)
+			app.All("/maintenance", ok)
+			app.Post("/auth/login", ok)
+			app.Get("/health/ready", ok)
+
+			resp, err := app.Test(httptest.NewRequest(tt.method, tt.path, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus == fiber.StatusOK {
+				return
+			}
+
+			assert.Equal(t, "120", resp.Header.Get("Retry-After"))
+			var body response.Response
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, response.CodeMaintenance, body.Code)
+		})
+	}
+}
+
+func TestMaintenance_SetMode(t *testing.T) {
+	m := NewMaintenance(MaintenanceOff)
+
+	require.NoError(t, m.SetMode(MaintenanceReadOnly))
+	assert.Equal(t, MaintenanceReadOnly, m.Mode())
+
+	assert.ErrorIs(t, m.SetMode("sideways"), ErrInvalidMaintenanceMode)
+	assert.Equal(t, MaintenanceReadOnly, m.Mode(), "an invalid mode leaves the current one in place")
+}
\ No newline at end of file
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Recover turns a panic in a later handler into a 500 response and logs it at
+// error level with the stack trace and request ID, so crashes show up in the
+// structured logs rather than on stderr.
+func Recover() fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			logger.WithContext(c.UserContext()).Error("Panic recovered",
+				zap.String("panic", fmt.Sprint(r)),
+				zap.String("method", c.Method()),
+				zap.String("path", c.Path()),
+				zap.Stack("stack"),
+			)
+			err = response.InternalServerError(c, "Internal server error")
+		}()
+
+		return c.Next()
+	}
+}
\ No newline at end of file
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecover_LogsPanic(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	t.Cleanup(logger.Replace(zap.New(core)))
+
+	app := fiber.New()
+	app.Use(requestid.New())
+	app.Use(RequestContext())
+	app.Use(Recover())
+	app.Get("/boom", func(c *fiber.Ctx) error {
+		panic("nil map write")
+	})
+
+	req := httptest.NewRequest("GET", "/boom", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "req-panic")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	entries := logs.FilterMessage("Panic recovered").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zapcore.ErrorLevel, entries[0].Level)
+
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "nil map write", fields["panic"])
+	assert.Equal(t, "req-panic", fields["request_id"])
+	assert.Equal(t, "/boom", fields["path"])
+	assert.Contains(t, fields["stack"], "TestRecover_LogsPanic")
+}
\ No newline at end of file
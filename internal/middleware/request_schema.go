@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/ariam/my-api/pkg/openapi"
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/ariam/my-api/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestSchema validates JSON request bodies against the body schema spec
+// documents for the matched route, rejecting mismatches the struct tags don't
+// catch, such as undeclared fields or values of the wrong type, with the
+// standard 422. Add it to the routes that should be checked; routes without a
+// documented body, and requests with an empty or non-JSON body, are left to
+// the handler, as are bodies that aren't valid JSON.
+func RequestSchema(spec *openapi.Spec) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		schema, ok := spec.RequestBody(c.Method(), c.Route().Path)
+		if !ok || len(c.Body()) == 0 || !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+			return c.Next()
+		}
+
+		errs, err := spec.Validate(schema, c.Body())
+		if err != nil || len(errs) == 0 {
+			return c.Next()
+		}
+		if strings.EqualFold(c.Get(response.ValidationFormatHeader), "map") {
+			return response.ValidationErrorMap(c, validator.GroupByField(errs))
+		}
+		return response.ValidationError(c, errs)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ariam/my-api/pkg/openapi"
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestSchema(t *testing.T) {
+	spec, err := openapi.Parse([]byte(`{
+		"basePath": "/api/v1",
+		"paths": {"/users": {"post": {"parameters": [{"in": "body", "schema": {"$ref": "#/definitions/CreateUserInput"}}]}}},
+		"definitions": {"CreateUserInput": {"type": "object", "properties": {"name": {"type": "string"}, "email": {"type": "string"}}}}
+	}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+		expectedField  string
+	}{
+		{name: "matches the spec", path: "/api/v1/users", body: `{"name":"Jane","email":"jane@example.com"}`, expectedStatus: fiber.StatusCreated},
+		{name: "unexpected field type", path: "/api/v1/users", body: `{"name":42,"email":"jane@example.com"}`, expectedStatus: fiber.StatusUnprocessableEntity, expectedField: "name"},
+		{name: "undeclared field", path: "/api/v1/users", body: `{"name":"Jane","role":"admin"}`, expectedStatus: fiber.StatusUnprocessableEntity, expectedField: "role"},
+		{name: "malformed JSON is left to the handler", path: "/api/v1/users", body: `{"name":`, expectedStatus: fiber.StatusCreated},
+		{name: "route without a documented body", path: "/api/v1/other", body: `{"name":42}`, expectedStatus: fiber.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			created := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) }
+			app.Post("/api/v1/users", RequestSchema(spec), created)
+			app.Post("/api/v1/other", RequestSchema(spec), created)
+
+			req := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedField == "" {
+				return
+			}
+
+			var body struct {
+				Code  string `json:"code"`
+				Error []struct {
+					Field string `json:"field"`
+				} `json:"error"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, response.CodeValidationFailed, body.Code)
+			require.Len(t, body.Error, 1)
+			assert.Equal(t, tt.expectedField, body.Error[0].Field)
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/ariam/my-api/pkg/cache"
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// cachedResponse is a response stored by CacheGET. Headers holds the
+// pagination headers the handler set.
+type cachedResponse struct {
+	ContentType string            `json:"content_type"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        []byte            `json:"body"`
+	StoredAt    time.Time         `json:"stored_at"`
+}
+
+// CacheGET serves repeated GET requests for the same URL from store for up to
+// ttl instead of running the handler again. Entries are scoped to the
+// authenticated user, so it must run after Auth. Only 200 responses are
+// stored, and a request with Cache-Control: no-cache skips the lookup but
+// refreshes the entry. Replays keep the pagination headers of the stored
+// response. Responses carry Cache-Control, and replays carry Age.
+// Cache failures are logged and the request is handled as a miss.
+func CacheGET(store cache.Cache, ttl time.Duration) fiber.Handler {
+	cacheControl := "private, max-age=" + strconv.Itoa(int(ttl.Seconds()))
+
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet {
+			return c.Next()
+		}
+
+		ctx := c.UserContext()
+		key := responseCacheKey(c)
+
+		if c.Get(fiber.HeaderCacheControl) != "no-cache" {
+			data, err := store.Get(ctx, key)
+			if err != nil {
+				logger.WithContext(ctx).Warn("Response cache read failed", zap.Error(err))
+			}
+			var cached cachedResponse
+			if data != nil && json.Unmarshal(data, &cached) == nil {
+				c.Set(fiber.HeaderCacheControl, cacheControl)
+				c.Set(fiber.HeaderAge, strconv.Itoa(int(time.Since(cached.StoredAt).Seconds())))
+				c.Set(fiber.HeaderContentType, cached.ContentType)
+				for name, value := range cached.Headers {
+					c.Set(name, value)
+				}
+				return c.Status(fiber.StatusOK).Send(cached.Body)
+			}
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		if c.Response().StatusCode() != fiber.StatusOK || c.Response().IsBodyStream() {
+			return nil
+		}
+		c.Set(fiber.HeaderCacheControl, cacheControl)
+
+		headers := make(map[string]string)
+		for _, name := range response.PaginationHeaders {
+			if value := c.Response().Header.Peek(name); len(value) > 0 {
+				headers[name] = string(value)
+			}
+		}
+		data, err := json.Marshal(cachedResponse{
+			ContentType: string(c.Response().Header.ContentType()),
+			Headers:     headers,
+			Body:        c.Response().Body(),
+			StoredAt:    time.Now(),
+		})
+		if err == nil {
+			err = store.Set(ctx, key, data, ttl)
+		}
+		if err != nil {
+			logger.WithContext(ctx).Warn("Response cache write failed", zap.Error(err))
+		}
+		return nil
+	}
+}
+
+// EvictCachedGET drops the caller's response cached by CacheGET for the GET
+// of resource(c) once the rest of the chain has run, so a client reading back
+// what it just wrote, such as a new version, isn't served the old copy.
+// Only the entry for the bare URL is dropped; other callers' entries expire
+// with their TTL. It must run after Auth.
+func EvictCachedGET(store cache.Cache, resource func(*fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		ctx := c.UserContext()
+		if delErr := store.Delete(ctx, "response:"+responseCacheScope(c)+":"+resource(c)); delErr != nil {
+			logger.WithContext(ctx).Warn("Response cache eviction failed", zap.Error(delErr))
+		}
+		return err
+	}
+}
+
+// responseCacheKey scopes the full URL to the caller, so one caller is never
+// served another's response.
+func responseCacheKey(c *fiber.Ctx) string {
+	return "response:" + responseCacheScope(c) + ":" + c.OriginalURL()
+}
+
+// responseCacheScope identifies the caller: the authenticated user when Auth
+// has run, otherwise a hash of any Authorization header.
+func responseCacheScope(c *fiber.Ctx) string {
+	if userID, ok := c.Locals("user_id").(string); ok && userID != "" {
+		return "user:" + userID
+	}
+	if auth := c.Get(fiber.HeaderAuthorization); auth != "" {
+		sum := sha256.Sum256([]byte(auth))
+		return "auth:" + hex.EncodeToString(sum[:])
+	}
+	return "anonymous"
+}
\ No newline at end of file
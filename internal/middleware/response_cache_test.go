@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/pkg/cache"
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheGET(t *testing.T) {
+	calls := 0
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		// stand-in for Auth
+		if userID := c.Get("X-Test-User"); userID != "" {
+			c.Locals("user_id", userID)
+		}
+		return c.Next()
+	})
+	app.Use(CacheGET(cache.NewLRU(100), time.Minute))
+	app.Get("/items", func(c *fiber.Ctx) error {
+		calls++
+		return c.JSON(fiber.Map{"call": calls, "user": c.Locals("user_id")})
+	})
+	app.Get("/missing", func(c *fiber.Ctx) error {
+		calls++
+		return c.SendStatus(fiber.StatusNotFound)
+	})
+
+	get := func(path, userID, cacheControl string) (*http.Response, string) {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("X-Test-User", userID)
+		if cacheControl != "" {
+			req.Header.Set(fiber.HeaderCacheControl, cacheControl)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	first, firstBody := get("/items?page=1", "alice", "")
+	assert.Equal(t, "private, max-age=60", first.Header.Get(fiber.HeaderCacheControl))
+	assert.Empty(t, first.Header.Get(fiber.HeaderAge))
+
+	second, secondBody := get("/items?page=1", "alice", "")
+	assert.Equal(t, 1, calls, "second request within the TTL skips the handler")
+	assert.Equal(t, firstBody, secondBody)
+	assert.Equal(t, "private, max-age=60", second.Header.Get(fiber.HeaderCacheControl))
+	age, err := strconv.Atoi(second.Header.Get(fiber.HeaderAge))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, age, 0)
+
+	_, otherBody := get("/items?page=1", "bob", "")
+	assert.Equal(t, 2, calls, "another user's request is not served alice's response")
+	assert.Contains(t, otherBody, `"user":"bob"`)
+
+	get("/items?page=2", "alice", "")
+	assert.Equal(t, 3, calls, "the query string is part of the key")
+
+	get("/items?page=1", "alice", "no-cache")
+	assert.Equal(t, 4, calls, "no-cache bypasses the stored response")
+
+	for i := 0; i < 2; i++ {
+		resp, _ := get("/missing", "alice", "")
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	}
+	assert.Equal(t, 6, calls, "non-200 responses are not cached")
+}
+
+func TestCacheGET_ReplaysPaginationHeaders(t *testing.T) {
+	calls := 0
+	app := fiber.New()
+	app.Use(CacheGET(cache.NewLRU(100), time.Minute))
+	app.Get("/items", func(c *fiber.Ctx) error {
+		calls++
+		return response.Paginated(c, []string{"a", "b"}, 42, 2, 2)
+	})
+
+	var headers []http.Header
+	for i := 0; i < 2; i++ {
+		resp, err := app.Test(httptest.NewRequest("GET", "/items?page=2&per_page=2", nil))
+		require.NoError(t, err)
+		headers = append(headers, resp.Header)
+	}
+
+	assert.Equal(t, 1, calls, "the second request is served from the cache")
+	assert.NotEmpty(t, headers[1].Get(fiber.HeaderAge))
+	for _, name := range response.PaginationHeaders {
+		assert.Equal(t, headers[0].Get(name), headers[1].Get(name), name)
+	}
+	assert.Equal(t, "42", headers[1].Get(response.HeaderTotalCount))
+	assert.Equal(t, "21", headers[1].Get(response.HeaderTotalPages))
+}
+
+func TestEvictCachedGET(t *testing.T) {
+	store := cache.NewLRU(100)
+	version := 1
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", "alice")
+		return c.Next()
+	})
+	app.Get("/users/:id", CacheGET(store, time.Minute), func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"version": version})
+	})
+	app.Put("/users/:id", EvictCachedGET(store, func(c *fiber.Ctx) string { return "/users/" + c.Params("id") }), func(c *fiber.Ctx) error {
+		version++
+		return c.JSON(fiber.Map{"version": version})
+	})
+
+	get := func() string {
+		resp, err := app.Test(httptest.NewRequest("GET", "/users/1", nil))
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	assert.Equal(t, `{"version":1}`, get())
+	_, err := app.Test(httptest.NewRequest("PUT", "/users/1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, `{"version":2}`, get(), "the write drops the cached copy")
+}
\ No newline at end of file
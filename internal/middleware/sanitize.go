@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RejectSuspiciousInput rejects requests whose query values or JSON string
+// fields contain null bytes or other control characters. Tabs and newlines are
+// allowed. It is deliberately narrow and meant as defense in depth on routes
+// that write user-supplied text. Streamed bodies are read in full, so don't
+// use it on routes that consume the body as a stream.
+func RejectSuspiciousInput() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		for _, v := range c.Queries() {
+			if hasControlChars(v) {
+				return response.BadRequest(c, "Request contains invalid characters")
+			}
+		}
+
+		// c.Body reads a streamed body into memory, where the handler finds
+		// it again; BodyLimit has already bounded it
+		if len(c.Body()) == 0 {
+			return c.Next()
+		}
+
+		var data interface{}
+		if err := json.Unmarshal(c.Body(), &data); err != nil {
+			// malformed bodies are left to the handler's own parsing
+			return c.Next()
+		}
+		if containsControlChars(data) {
+			return response.BadRequest(c, "Request contains invalid characters")
+		}
+
+		return c.Next()
+	}
+}
+
+func containsControlChars(v interface{}) bool {
+	switch val := v.(type) {
+	case string:
+		return hasControlChars(val)
+	case map[string]interface{}:
+		for k, field := range val {
+			if hasControlChars(k) || containsControlChars(field) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range val {
+			if containsControlChars(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasControlChars(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		return (r < 0x20 && r != '\t' && r != '\n' && r != '\r') || r == 0x7f
+	}) >= 0
+}
\ No newline at end of file
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRejectSuspiciousInput(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		query          string
+		expectedStatus int
+	}{
+		{name: "normal name passes", body: `{"name":"John O'Brien","email":"john@example.com"}`, expectedStatus: fiber.StatusOK},
+		{name: "unicode and newlines pass", body: `{"name":"Zoë\nSmith"}`, expectedStatus: fiber.StatusOK},
+		{name: "null byte in name is rejected", body: `{"name":"John\u0000Doe"}`, expectedStatus: fiber.StatusBadRequest},
+		{name: "control character in nested field is rejected", body: `{"users":[{"name":"a\u0007b"}]}`, expectedStatus: fiber.StatusBadRequest},
+		{name: "null byte in query is rejected", query: "?q=a%00b", expectedStatus: fiber.StatusBadRequest},
+		{name: "malformed body is left to the handler", body: `{"name":`, expectedStatus: fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/users", RejectSuspiciousInput(), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest("POST", "/users"+tt.query, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestRejectSuspiciousInput_StreamedBody(t *testing.T) {
+	app := fiber.New(fiber.Config{StreamRequestBody: true})
+	app.Post("/users", RejectSuspiciousInput(), func(c *fiber.Ctx) error {
+		// the handler still sees the body the guard read
+		return c.Send(c.Body())
+	})
+
+	req := httptest.NewRequest("POST", "/users", bytes.NewReader([]byte(`{"name":"John\u0000Doe"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	body := `{"name":"John Doe"}`
+	req = httptest.NewRequest("POST", "/users", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	got, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(got))
+}
\ No newline at end of file
@@ -1,45 +1,278 @@
 package middleware
 
 import (
+	"encoding/json"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/ariam/my-api/internal/config"
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/ariam/my-api/pkg/response"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/helmet"
 	"github.com/gofiber/fiber/v2/middleware/limiter"
-	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"go.uber.org/zap"
 )
 
-func SetupSecurity(app *fiber.App, env string) {
-	app.Use(recover.New(recover.Config{
-		EnableStackTrace: env == "development",
-	}))
-
+// SetupSecurity registers the middleware every request passes through. The
+// global rate limiter counts in limiterStore; nil keeps counts in memory.
+func SetupSecurity(app *fiber.App, cfg *config.Config, limiterStore LimiterStore) {
+	if cfg.Proxy.Header != "" {
+		app.Use(ProxyClientIP(cfg.Proxy))
+	}
+	// the request ID is set up first so a recovered panic is logged with it
 	app.Use(requestid.New())
+	app.Use(RequestContext())
+	app.Use(Recover())
+
+	if len(cfg.App.AllowedMethods) > 0 {
+		app.Use(MethodFilter(cfg.App.AllowedMethods...))
+	}
 
 	app.Use(helmet.New())
 
-	app.Use(cors.New(cors.Config{
-		AllowOrigins:     "*",
-		AllowMethods:     "GET,POST,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-Request-ID",
-		AllowCredentials: false,
-		MaxAge:           300,
-	}))
-
-	app.Use(limiter.New(limiter.Config{
-		Max:               100,
-		Expiration:        1 * time.Minute,
+	app.Use(CORS(cfg.CORS))
+
+	app.Use(RateLimit(cfg.RateLimit.GlobalMax, time.Duration(cfg.RateLimit.GlobalWindow)*time.Second, func(c *fiber.Ctx) string {
+		return c.IP()
+	}, limiterStore))
+}
+
+// ProxyClientIP narrows the proxy header, on requests from a trusted proxy,
+// to the rightmost address that isn't itself a trusted proxy, so c.IP() in
+// later handlers returns it. Each proxy appends the address it received the
+// request from, so that is the client the outermost trusted proxy saw;
+// anything further left was sent by the client and can be forged. When the
+// header holds no usable address it is removed and c.IP() falls back to the
+// peer. The config is expected to have passed ProxyConfig.Validate.
+func ProxyClientIP(cfg config.ProxyConfig) fiber.Handler {
+	trusted := make([]*net.IPNet, 0, len(cfg.TrustedProxies))
+	for _, proxy := range cfg.TrustedProxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		if _, ipNet, err := net.ParseCIDR(proxy); err == nil {
+			trusted = append(trusted, ipNet)
+		}
+	}
+	isTrusted := func(ip net.IP) bool {
+		for _, ipNet := range trusted {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(c *fiber.Ctx) error {
+		value := c.Get(cfg.Header)
+		if value == "" || !c.IsProxyTrusted() {
+			return c.Next()
+		}
+
+		if ip := rightmostUntrusted(value, isTrusted); ip != "" {
+			c.Request().Header.Set(cfg.Header, ip)
+		} else {
+			c.Request().Header.Del(cfg.Header)
+		}
+		return c.Next()
+	}
+}
+
+// rightmostUntrusted walks a comma-separated address list from the right,
+// skipping trusted proxies. An unparsable entry stops the walk, since nothing
+// left of it can be attributed to a trusted hop. If every entry is trusted,
+// the leftmost is returned.
+func rightmostUntrusted(list string, isTrusted func(net.IP) bool) string {
+	entries := strings.Split(list, ",")
+	var leftmost string
+	for i := len(entries) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(entries[i]))
+		if ip == nil {
+			return ""
+		}
+		if !isTrusted(ip) {
+			return ip.String()
+		}
+		leftmost = ip.String()
+	}
+	return leftmost
+}
+
+func rateLimitReached(c *fiber.Ctx) error {
+	return response.Error(c, fiber.StatusTooManyRequests, "Too many requests, please try again later")
+}
+
+// MethodFilter rejects requests whose method is not in allowed with 405.
+// OPTIONS is always let through so CORS preflights keep working, and HEAD
+// whenever GET is, as Fiber answers HEAD on every GET route.
+func MethodFilter(allowed ...string) fiber.Handler {
+	permitted := map[string]bool{fiber.MethodOptions: true}
+	for _, m := range allowed {
+		permitted[strings.ToUpper(m)] = true
+	}
+	if permitted[fiber.MethodGet] {
+		permitted[fiber.MethodHead] = true
+	}
+
+	allowHeader := make([]string, 0, len(permitted))
+	for m := range permitted {
+		allowHeader = append(allowHeader, m)
+	}
+	sort.Strings(allowHeader)
+
+	return func(c *fiber.Ctx) error {
+		if permitted[c.Method()] {
+			return c.Next()
+		}
+		c.Set(fiber.HeaderAllow, strings.Join(allowHeader, ", "))
+		return response.Error(c, fiber.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// MethodNotAllowed responds 405 with an Allow header listing the methods
+// routed for the request path. Fiber returns fiber.ErrMethodNotAllowed when a
+// path is routed only for other methods; the app's error handler should
+// answer it with this.
+func MethodNotAllowed(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderAllow, strings.Join(allowedMethods(c), ", "))
+	return response.Error(c, fiber.StatusMethodNotAllowed, "Method not allowed")
+}
+
+// NotFound answers requests no route matched with the standard error
+// envelope. Register it with app.Use after every route. Paths routed for
+// other methods still get 405 from MethodNotAllowed.
+func NotFound() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if len(allowedMethods(c)) > 0 {
+			return MethodNotAllowed(c)
+		}
+		return response.NotFound(c, "Route not found")
+	}
+}
+
+// allowedMethods lists, sorted, the methods with a route matching the request
+// path.
+func allowedMethods(c *fiber.Ctx) []string {
+	cfg := c.App().Config()
+	seen := make(map[string]bool)
+	var allowed []string
+	for _, route := range c.App().GetRoutes(true) {
+		if seen[route.Method] || !fiber.RoutePatternMatch(c.Path(), route.Path, cfg) {
+			continue
+		}
+		seen[route.Method] = true
+		allowed = append(allowed, route.Method)
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+// CORS builds the CORS middleware from config. The config is expected to have
+// passed CORSConfig.Validate; with no origins listed, cross-origin requests get
+// no Access-Control-Allow-Origin header. The pagination headers are exposed to
+// browser clients.
+func CORS(cfg config.CORSConfig) fiber.Handler {
+	c := cors.Config{
+		AllowOrigins:     strings.Join(cfg.AllowedOrigins, ","),
+		AllowMethods:     strings.Join(cfg.AllowedMethods, ","),
+		AllowHeaders:     strings.Join(cfg.AllowedHeaders, ","),
+		AllowCredentials: cfg.AllowCredentials,
+		ExposeHeaders:    strings.Join(response.PaginationHeaders, ","),
+		MaxAge:           cfg.MaxAge,
+	}
+	if len(cfg.AllowedOrigins) == 0 {
+		c.AllowOriginsFunc = func(string) bool { return false }
+	}
+	return cors.New(c)
+}
+
+// the headers limiter.New sets, so responses look the same whichever store
+// counted them
+const (
+	xRateLimitLimit     = "X-RateLimit-Limit"
+	xRateLimitRemaining = "X-RateLimit-Remaining"
+	xRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// RateLimit applies its own limit to a route or group, on top of the global
+// limiter. keyFn selects the bucket a request counts against; nil uses
+// UserOrIPKey. store holds the counts; nil keeps them in memory. While store
+// fails, requests are counted in memory instead, so an outage of a shared
+// store neither rejects every request nor lifts the limit.
+func RateLimit(max int, window time.Duration, keyFn func(*fiber.Ctx) string, store LimiterStore) fiber.Handler {
+	if keyFn == nil {
+		keyFn = UserOrIPKey
+	}
+	memory := limiter.New(limiter.Config{
+		Max:               max,
+		Expiration:        window,
 		LimiterMiddleware: limiter.SlidingWindow{},
-		KeyGenerator: func(c *fiber.Ctx) string {
-			return c.IP()
-		},
-		LimitReached: func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"success": false,
-				"error":   "Too many requests, please try again later",
-			})
-		},
-	}))
+		KeyGenerator:      keyFn,
+		LimitReached:      rateLimitReached,
+	})
+	if store == nil {
+		return memory
+	}
+
+	var degraded atomic.Bool
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		now := time.Now()
+		current, previous, err := store.Hit(ctx, keyFn(c), now.UnixNano()/int64(window), 2*window)
+		if err != nil {
+			if !degraded.Swap(true) {
+				logger.WithContext(ctx).Warn("Rate limit store failed, counting in memory", zap.Error(err))
+			}
+			return memory(c)
+		}
+		if degraded.Swap(false) {
+			logger.WithContext(ctx).Info("Rate limit store recovered")
+		}
+
+		// weigh the previous window by how much of it still overlaps the
+		// sliding one, as limiter.SlidingWindow does
+		elapsed := time.Duration(now.UnixNano() % int64(window))
+		rate := int(float64(previous)*float64(window-elapsed)/float64(window)) + int(current)
+		resetIn := strconv.Itoa(int((window - elapsed).Seconds()))
+
+		c.Set(xRateLimitLimit, strconv.Itoa(max))
+		c.Set(xRateLimitReset, resetIn)
+		if rate > max {
+			c.Set(xRateLimitRemaining, "0")
+			c.Set(fiber.HeaderRetryAfter, resetIn)
+			return rateLimitReached(c)
+		}
+		c.Set(xRateLimitRemaining, strconv.Itoa(max-rate))
+		return c.Next()
+	}
+}
+
+// UserOrIPKey keys authenticated callers by user ID so the limit follows the
+// account rather than the client address.
+func UserOrIPKey(c *fiber.Ctx) string {
+	if userID, ok := c.Locals("user_id").(string); ok {
+		return "user:" + userID
+	}
+	return "ip:" + c.IP()
+}
+
+// LoginKey keys login attempts by submitted email and client IP, so guessing
+// one account's password is throttled without locking out everyone behind a NAT.
+func LoginKey(c *fiber.Ctx) string {
+	var body struct {
+		Email string `json:"email"`
+	}
+	_ = json.Unmarshal(c.Body(), &body)
+	return strings.ToLower(strings.TrimSpace(body.Email)) + "|" + c.IP()
 }
\ No newline at end of file
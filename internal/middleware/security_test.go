@@ -0,0 +1,252 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/internal/config"
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name           string
+		origins        []string
+		origin         string
+		expectedHeader string
+	}{
+		{name: "allowed origin is echoed", origins: []string{"https://app.example.com"}, origin: "https://app.example.com", expectedHeader: "https://app.example.com"},
+		{name: "disallowed origin gets no header", origins: []string{"https://app.example.com"}, origin: "https://evil.example.com", expectedHeader: ""},
+		{name: "no configured origins allows none", origins: nil, origin: "https://app.example.com", expectedHeader: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(CORS(config.CORSConfig{
+				AllowedOrigins: tt.origins,
+				AllowedMethods: []string{"GET"},
+			}))
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Origin", tt.origin)
+
+			resp, err := app.Test(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedHeader, resp.Header.Get("Access-Control-Allow-Origin"))
+		})
+	}
+}
+
+func TestRateLimit_LoginKey(t *testing.T) {
+	app := fiber.New()
+	app.Post("/auth/login", RateLimit(3, time.Minute, LoginKey, nil), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	login := func(email string) int {
+		req := httptest.NewRequest("POST", "/auth/login", bytes.NewReader([]byte(`{"email":"`+email+`","password":"wrong"}`)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, fiber.StatusOK, login("victim@example.com"))
+	}
+	assert.Equal(t, fiber.StatusTooManyRequests, login("victim@example.com"))
+	assert.Equal(t, fiber.StatusTooManyRequests, login("VICTIM@example.com"))
+
+	// other accounts from the same client have their own bucket
+	assert.Equal(t, fiber.StatusOK, login("someone@example.com"))
+}
+
+func TestMethodFilter(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		expectedStatus int
+	}{
+		{name: "allowed method passes", method: "GET", expectedStatus: fiber.StatusOK},
+		{name: "disallowed method is rejected", method: "PATCH", expectedStatus: fiber.StatusMethodNotAllowed},
+		{name: "trace is rejected", method: "TRACE", expectedStatus: fiber.StatusMethodNotAllowed},
+		{name: "options always passes", method: "OPTIONS", expectedStatus: fiber.StatusOK},
+		{name: "head passes with get", method: "HEAD", expectedStatus: fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(MethodFilter("get", "POST"))
+			app.All("/", func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(tt.method, "/", nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus == fiber.StatusMethodNotAllowed {
+				assert.Equal(t, "GET, HEAD, OPTIONS, POST", resp.Header.Get("Allow"))
+				assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestMethodFilter_HeadRequiresGet(t *testing.T) {
+	app := fiber.New()
+	app.Use(MethodFilter("POST"))
+	app.All("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("HEAD", "/", nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusMethodNotAllowed, resp.StatusCode)
+	assert.Equal(t, "OPTIONS, POST", resp.Header.Get("Allow"))
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			var e *fiber.Error
+			if errors.As(err, &e) && e.Code == fiber.StatusMethodNotAllowed {
+				return MethodNotAllowed(c)
+			}
+			return response.Error(c, fiber.StatusInternalServerError, err.Error())
+		},
+	})
+	ok := func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+	app.Get("/users/:id", ok)
+	app.Put("/users/:id", ok)
+	app.Delete("/users/:id", ok)
+	app.Post("/users", ok)
+
+	resp, err := app.Test(httptest.NewRequest("PATCH", "/users/123", nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, fiber.StatusMethodNotAllowed, resp.StatusCode)
+	assert.Equal(t, "DELETE, GET, HEAD, PUT", resp.Header.Get(fiber.HeaderAllow))
+
+	var body response.Response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.False(t, body.Success)
+	assert.Equal(t, "Method not allowed", body.Error)
+	assert.Equal(t, "METHOD_NOT_ALLOWED", body.Code)
+}
+func TestNotFound(t *testing.T) {
+	app := fiber.New()
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/broken", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusTeapot, "handled by the error handler")
+	})
+	app.Use(NotFound())
+
+	t.Run("unknown path", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/nope", nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+		var body response.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.False(t, body.Success)
+		assert.Equal(t, "Route not found", body.Error)
+		assert.Equal(t, "NOT_FOUND", body.Code)
+	})
+
+	t.Run("real routes are not shadowed", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/users/123", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		resp, err = app.Test(httptest.NewRequest("GET", "/broken", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusTeapot, resp.StatusCode)
+	})
+
+	t.Run("wrong method is still 405", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest("DELETE", "/users/123", nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusMethodNotAllowed, resp.StatusCode)
+		assert.Equal(t, "GET, HEAD", resp.Header.Get(fiber.HeaderAllow))
+	})
+}
+
+func TestProxyClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		proxy      config.ProxyConfig
+		forwarded  string
+		expectedIP string
+	}{
+		// app.Test requests come from 0.0.0.0
+		{
+			name:       "address appended by the trusted proxy",
+			proxy:      config.ProxyConfig{TrustedProxies: []string{"0.0.0.0"}, Header: "X-Forwarded-For"},
+			forwarded:  "203.0.113.7",
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:       "client-supplied entry before the proxy's",
+			proxy:      config.ProxyConfig{TrustedProxies: []string{"0.0.0.0"}, Header: "X-Forwarded-For"},
+			forwarded:  "198.51.100.1, 203.0.113.7",
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:       "trusted hops are skipped",
+			proxy:      config.ProxyConfig{TrustedProxies: []string{"0.0.0.0", "10.0.0.0/8"}, Header: "X-Forwarded-For"},
+			forwarded:  "198.51.100.1, 203.0.113.7, 10.0.0.2",
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:       "garbage appended after the client falls back to the peer",
+			proxy:      config.ProxyConfig{TrustedProxies: []string{"0.0.0.0"}, Header: "X-Forwarded-For"},
+			forwarded:  "203.0.113.7, not-an-ip",
+			expectedIP: "0.0.0.0",
+		},
+		{
+			name:       "header from untrusted peer is ignored",
+			proxy:      config.ProxyConfig{TrustedProxies: []string{"10.0.0.0/8"}, Header: "X-Forwarded-For"},
+			forwarded:  "203.0.113.7",
+			expectedIP: "0.0.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fc fiber.Config
+			tt.proxy.Apply(&fc)
+			app := fiber.New(fc)
+			app.Use(ProxyClientIP(tt.proxy))
+			app.Get("/", func(c *fiber.Ctx) error { return c.SendString(c.IP()) })
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("X-Forwarded-For", tt.forwarded)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedIP, string(body))
+		})
+	}
+}
\ No newline at end of file
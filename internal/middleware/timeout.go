@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Timeout bounds how long the rest of the chain may run. The deadline is set
+// on the request's context.Context, so database calls made with it are
+// cancelled once it passes, and the client gets 503 instead of whatever the
+// handler produced. exemptPaths are routes that run as long as their client
+// keeps streaming, such as bulk imports, and bound the work themselves; they
+// match the way routes do, ignoring case and a trailing slash. A d of zero or
+// less disables the timeout.
+func Timeout(d time.Duration, exemptPaths ...string) fiber.Handler {
+	exempt := make(map[string]bool, len(exemptPaths))
+	for _, p := range exemptPaths {
+		exempt[normalizePath(p)] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		if d <= 0 || exempt[normalizePath(c.Path())] {
+			return c.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), d)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return response.Error(c, fiber.StatusServiceUnavailable, "Request timeout")
+		}
+		return err
+	}
+}
\ No newline at end of file
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeout(t *testing.T) {
+	var downstreamErr error
+
+	app := fiber.New()
+	app.Use(Timeout(20 * time.Millisecond))
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		select {
+		case <-c.UserContext().Done():
+			downstreamErr = c.UserContext().Err()
+			return response.InternalServerError(c, "Failed to fetch users")
+		case <-time.After(time.Second):
+			return response.Success(c, nil)
+		}
+	})
+	app.Get("/fast", func(c *fiber.Ctx) error {
+		return response.Success(c, nil)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/slow", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+	assert.ErrorIs(t, downstreamErr, context.DeadlineExceeded, "the handler's context is cancelled")
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "Request timeout", body["error"])
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/fast", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestTimeout_ExemptPath(t *testing.T) {
+	app := fiber.New()
+	app.Use(Timeout(20*time.Millisecond, "/bulk"))
+	app.Post("/bulk", func(c *fiber.Ctx) error {
+		select {
+		case <-c.UserContext().Done():
+			return response.InternalServerError(c, "Import cut off")
+		case <-time.After(50 * time.Millisecond):
+			return response.Success(c, nil)
+		}
+	})
+
+	for _, path := range []string{"/bulk", "/bulk/", "/BULK"} {
+		resp, err := app.Test(httptest.NewRequest("POST", path, nil), -1)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode, path)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/ariam/my-api"
+
+// Tracing starts a server span per request, continuing the trace from an
+// incoming traceparent header. The span is named by method and route, e.g.
+// "GET /api/v1/users/:id", and is stored in the request's context.Context so
+// database queries made with it become child spans.
+func Tracing() fiber.Handler {
+	tracer := otel.Tracer(tracerName)
+
+	return func(c *fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), propagation.HeaderCarrier(c.GetReqHeaders()))
+		ctx, span := tracer.Start(ctx, c.Method()+" "+c.Path(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+			),
+		)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+		c.Locals(response.TraceIDLocal, span.SpanContext().TraceID().String())
+
+		err := c.Next()
+
+		// the route is only known once the router has matched the request
+		route := c.Route().Path
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route))
+
+		// errors are turned into responses by the app's error handler, which
+		// runs after this middleware returns
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+			span.RecordError(err)
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+
+		return err
+	}
+}
\ No newline at end of file
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ariam/my-api/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func setupTracing(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return exporter
+}
+
+func TestTracing_OneSpanPerRequest(t *testing.T) {
+	exporter := setupTracing(t)
+
+	app := fiber.New()
+	app.Use(Tracing())
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		return response.Success(c, nil)
+	})
+	app.Get("/boom", func(c *fiber.Ctx) error {
+		return response.InternalServerError(c, "Failed")
+	})
+
+	for _, path := range []string{"/users/1", "/users/2", "/boom"} {
+		_, err := app.Test(httptest.NewRequest("GET", path, nil))
+		require.NoError(t, err)
+	}
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, "GET /users/:id", spans[0].Name)
+	assert.Contains(t, spans[0].Attributes, semconv.HTTPResponseStatusCode(fiber.StatusOK))
+	assert.Contains(t, spans[0].Attributes, semconv.HTTPRoute("/users/:id"))
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+
+	assert.Equal(t, "GET /boom", spans[2].Name)
+	assert.Contains(t, spans[2].Attributes, semconv.HTTPResponseStatusCode(fiber.StatusInternalServerError))
+	assert.Equal(t, codes.Error, spans[2].Status.Code)
+}
+
+func TestTracing_ContinuesIncomingTrace(t *testing.T) {
+	exporter := setupTracing(t)
+
+	app := fiber.New()
+	app.Use(Tracing())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return response.NotFound(c, "User not found")
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", body["trace_id"], "error responses carry the trace ID")
+}
\ No newline at end of file
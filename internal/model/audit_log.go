@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	AuditActionUserCreate  = "user.create"
+	AuditActionUserUpdate  = "user.update"
+	AuditActionUserDelete  = "user.delete"
+	AuditActionUserSetRole = "user.set_role"
+
+	AuditActionUserResetPassword = "user.reset_password"
+	AuditActionUserChangeEmail   = "user.change_email"
+
+	AuditTargetUser = "user"
+)
+
+// AuditLog records who changed what. Entries are append-only, so unlike Base
+// there is no UpdatedAt or soft delete. ActorID is nil for anonymous actions
+// such as self-registration. TenantID is the tenant the action was taken in.
+type AuditLog struct {
+	ID         uuid.UUID              `json:"id" gorm:"type:uuid;primaryKey"`
+	TenantID   string                 `json:"tenant_id,omitempty" gorm:"size:64;not null;default:'';index"`
+	ActorID    *uuid.UUID             `json:"actor_id" gorm:"type:uuid;index"`
+	Action     string                 `json:"action" gorm:"size:50;index;not null"`
+	TargetType string                 `json:"target_type" gorm:"size:50;not null"`
+	TargetID   string                 `json:"target_id" gorm:"size:64;index;not null"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty" gorm:"serializer:json;type:text"`
+	CreatedAt  time.Time              `json:"created_at" gorm:"index"`
+}
+
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = NewID()
+	}
+	return nil
+}
\ No newline at end of file
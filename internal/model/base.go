@@ -14,9 +14,15 @@ type Base struct {
 	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
 }
 
+// CursorKey returns the (created_at, id) pair used for keyset pagination.
+func (b *Base) CursorKey() (time.Time, uuid.UUID) {
+	return b.CreatedAt, b.ID
+}
+
+// BeforeCreate assigns a new id unless the caller set one.
 func (b *Base) BeforeCreate(tx *gorm.DB) error {
 	if b.ID == uuid.Nil {
-		b.ID = uuid.New()
+		b.ID = NewID()
 	}
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"encoding/binary"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// IDGenerator produces the primary keys of new records.
+type IDGenerator interface {
+	NewID() uuid.UUID
+}
+
+// RandomIDs generates random (version 4) UUIDs. It is the default.
+type RandomIDs struct{}
+
+func (RandomIDs) NewID() uuid.UUID {
+	return uuid.New()
+}
+
+// SequenceIDs generates version 4 UUIDs from a counter, so a test that
+// creates the same records in the same order gets the same ids on every run:
+// 00000000-0000-4000-8000-000000000001, then ...0002 and so on. It is safe
+// for concurrent use.
+type SequenceIDs struct {
+	n atomic.Uint64
+}
+
+func (s *SequenceIDs) NewID() uuid.UUID {
+	var id uuid.UUID
+	binary.BigEndian.PutUint64(id[8:], s.n.Add(1))
+	id[6] = 0x40  // version 4
+	id[8] |= 0x80 // RFC 4122 variant
+	return id
+}
+
+type idGeneratorHolder struct{ IDGenerator }
+
+var idGenerator atomic.Value
+
+func init() {
+	idGenerator.Store(idGeneratorHolder{RandomIDs{}})
+}
+
+// NewID returns a new primary key from the current IDGenerator.
+func NewID() uuid.UUID {
+	return idGenerator.Load().(idGeneratorHolder).NewID()
+}
+
+// SetIDGenerator makes g the source of new primary keys and returns a
+// function that restores the previous one, for use with t.Cleanup.
+func SetIDGenerator(g IDGenerator) (restore func()) {
+	previous := idGenerator.Swap(idGeneratorHolder{g})
+	return func() { idGenerator.Store(previous) }
+}
\ No newline at end of file
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Session struct {
+	Base
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"index;not null"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	IP        string     `json:"ip" gorm:"size:64"`
+	UserAgent string     `json:"user_agent" gorm:"size:255"`
+}
\ No newline at end of file
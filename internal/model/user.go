@@ -1,14 +1,26 @@
 package model
 
+import "time"
+
+const (
+	ApprovalApproved = "approved"
+	ApprovalPending  = "pending_approval"
+	ApprovalRejected = "rejected"
+)
+
 type User struct {
 	Base
+	TenantID string `json:"tenant_id,omitempty" gorm:"size:64;not null;default:'';index"`
 	Name     string `json:"name" gorm:"size:100;not null"`
 	Email    string `json:"email" gorm:"size:100;uniqueIndex;not null"`
 	Password string `json:"-" gorm:"size:255;not null"`
 	Role     string `json:"role" gorm:"size:20;default:user"`
 	IsActive bool   `json:"is_active" gorm:"default:true"`
-}
 
-func (User) TableName() string {
-	return "users"
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	ApprovalStatus  string     `json:"approval_status" gorm:"size:20;index;default:approved"`
+
+	// Version is incremented by every update, which only applies if the row
+	// is still at the version the caller read.
+	Version uint `json:"version" gorm:"not null;default:1"`
 }
\ No newline at end of file
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+)
+
+// UserToken is a single-use, expiring token sent to a user out of band. Only
+// the SHA-256 hash of the token is stored.
+type UserToken struct {
+	Base
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;index;not null"`
+	Purpose   string     `json:"purpose" gorm:"size:32;not null"`
+	TokenHash string     `json:"-" gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
\ No newline at end of file
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/internal/tenant"
+	"gorm.io/gorm"
+)
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry *model.AuditLog) error
+	FindAll(ctx context.Context, page, perPage int) ([]model.AuditLog, int64, error)
+}
+
+type auditRepository struct {
+	*BaseRepository[model.AuditLog]
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{
+		BaseRepository: NewBaseRepository[model.AuditLog](db),
+	}
+}
+
+// Create records entry in the tenant bound to ctx, if any.
+func (r *auditRepository) Create(ctx context.Context, entry *model.AuditLog) error {
+	if id, ok := tenant.FromContext(ctx); ok {
+		entry.TenantID = id
+	}
+	return r.BaseRepository.Create(ctx, entry)
+}
+
+// FindAll pages through the entries of the tenant bound to ctx, if any.
+func (r *auditRepository) FindAll(ctx context.Context, page, perPage int) ([]model.AuditLog, int64, error) {
+	return r.BaseRepository.FindAll(ctx, page, perPage, DefaultSort, tenantScope(ctx))
+}
\ No newline at end of file
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/internal/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepository_TenantScope(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&model.AuditLog{}))
+	repo := NewAuditRepository(db)
+
+	tenantA := tenant.WithID(context.Background(), "tenant-a")
+	tenantB := tenant.WithID(context.Background(), "tenant-b")
+
+	entry := &model.AuditLog{Action: model.AuditActionUserCreate, TargetType: model.AuditTargetUser, TargetID: "user-a"}
+	require.NoError(t, repo.Create(tenantA, entry))
+	assert.Equal(t, "tenant-a", entry.TenantID)
+	require.NoError(t, repo.Create(tenantB, &model.AuditLog{Action: model.AuditActionUserCreate, TargetType: model.AuditTargetUser, TargetID: "user-b"}))
+
+	entries, total, err := repo.FindAll(tenantA, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "user-a", entries[0].TargetID)
+
+	// unscoped callers such as the CLI see every tenant
+	_, total, err = repo.FindAll(context.Background(), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+}
\ No newline at end of file
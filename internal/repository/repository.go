@@ -2,10 +2,66 @@ package repository
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type cursorKeyer interface {
+	CursorKey() (time.Time, uuid.UUID)
+}
+
+type cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"i"`
+}
+
+func encodeCursor(createdAt time.Time, id uuid.UUID) string {
+	raw, _ := json.Marshal(cursor{CreatedAt: createdAt, ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (*cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// DefaultSort orders newest records first.
+var DefaultSort = Sort{Column: "created_at", Desc: true}
+
+func (s Sort) Scope(db *gorm.DB) *gorm.DB {
+	if s.Column == "" {
+		s = DefaultSort
+	}
+	db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: s.Column}, Desc: s.Desc})
+	if s.Column != "id" {
+		// tie-breaker so pages stay stable when the sort column has duplicates
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: s.Desc})
+	}
+	return db
+}
+
 type BaseRepository[T any] struct {
 	DB *gorm.DB
 }
@@ -15,35 +71,80 @@ func NewBaseRepository[T any](db *gorm.DB) *BaseRepository[T] {
 }
 
 func (r *BaseRepository[T]) Create(ctx context.Context, entity *T) error {
-	return r.DB.WithContext(ctx).Create(entity).Error
+	return Conn(ctx, r.DB).Create(entity).Error
+}
+
+func (r *BaseRepository[T]) CreateBatch(ctx context.Context, entities []T) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	return Conn(ctx, r.DB).Create(&entities).Error
 }
 
 func (r *BaseRepository[T]) FindByID(ctx context.Context, id string) (*T, error) {
 	var entity T
-	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&entity).Error
+	err := Conn(ctx, r.DB).Where("id = ?", id).First(&entity).Error
 	if err != nil {
 		return nil, err
 	}
 	return &entity, nil
 }
 
-func (r *BaseRepository[T]) FindAll(ctx context.Context, page, perPage int) ([]T, int64, error) {
+func (r *BaseRepository[T]) FindAll(ctx context.Context, page, perPage int, sort Sort, scopes ...func(*gorm.DB) *gorm.DB) ([]T, int64, error) {
 	var entities []T
 	var total int64
 
-	r.DB.WithContext(ctx).Model(new(T)).Count(&total)
+	if err := Conn(ctx, r.DB).Model(new(T)).Scopes(scopes...).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
 
 	offset := (page - 1) * perPage
-	err := r.DB.WithContext(ctx).Offset(offset).Limit(perPage).Find(&entities).Error
+	err := Conn(ctx, r.DB).Scopes(scopes...).Scopes(sort.Scope).Offset(offset).Limit(perPage).Find(&entities).Error
 
 	return entities, total, err
 }
 
+// FindAfter returns up to limit entities ordered newest first, starting after
+// the opaque cursor. An empty cursor starts from the beginning; the returned
+// next cursor is empty once the end of the list is reached.
+func (r *BaseRepository[T]) FindAfter(ctx context.Context, cursor string, limit int, scopes ...func(*gorm.DB) *gorm.DB) ([]T, string, error) {
+	query := Conn(ctx, r.DB).Scopes(scopes...)
+
+	if cursor != "" {
+		c, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query = query.Where(
+			r.DB.Where("created_at < ?", c.CreatedAt).
+				Or("created_at = ? AND id < ?", c.CreatedAt, c.ID),
+		)
+	}
+
+	var entities []T
+	err := query.Scopes(DefaultSort.Scope).Limit(limit + 1).Find(&entities).Error
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(entities) <= limit {
+		return entities, "", nil
+	}
+
+	entities = entities[:limit]
+	last, ok := any(&entities[limit-1]).(cursorKeyer)
+	if !ok {
+		return entities, "", nil
+	}
+	createdAt, id := last.CursorKey()
+	return entities, encodeCursor(createdAt, id), nil
+}
+
 func (r *BaseRepository[T]) Update(ctx context.Context, entity *T) error {
-	return r.DB.WithContext(ctx).Save(entity).Error
+	return Conn(ctx, r.DB).Save(entity).Error
 }
 
 func (r *BaseRepository[T]) Delete(ctx context.Context, id string) error {
 	var entity T
-	return r.DB.WithContext(ctx).Where("id = ?", id).Delete(&entity).Error
+	return Conn(ctx, r.DB).Where("id = ?", id).Delete(&entity).Error
 }
\ No newline at end of file
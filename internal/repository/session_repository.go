@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ariam/my-api/internal/model"
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *model.Session) error
+	FindByID(ctx context.Context, id string) (*model.Session, error)
+	FindActiveByUser(ctx context.Context, userID string) ([]model.Session, error)
+	FindByUser(ctx context.Context, userID string) ([]model.Session, error)
+	Revoke(ctx context.Context, ids ...string) error
+}
+
+type sessionRepository struct {
+	*BaseRepository[model.Session]
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{
+		BaseRepository: NewBaseRepository[model.Session](db),
+	}
+}
+
+// FindActiveByUser returns the user's unrevoked, unexpired sessions, oldest first.
+func (r *sessionRepository) FindActiveByUser(ctx context.Context, userID string) ([]model.Session, error) {
+	var sessions []model.Session
+	err := Conn(ctx, r.DB).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at ASC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// FindByUser returns every session recorded for the user, newest first.
+func (r *sessionRepository) FindByUser(ctx context.Context, userID string) ([]model.Session, error) {
+	var sessions []model.Session
+	err := Conn(ctx, r.DB).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return Conn(ctx, r.DB).
+		Model(&model.Session{}).
+		Where("id IN ? AND revoked_at IS NULL", ids).
+		Update("revoked_at", time.Now()).Error
+}
\ No newline at end of file
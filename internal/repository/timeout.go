@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type queryTimeoutKey struct{}
+
+// queryDeadline is what a statement's context was before QueryTimeout
+// bounded it, and how to release the bound.
+type queryDeadline struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// QueryTimeout is a GORM plugin that bounds every create, query, update,
+// delete and raw statement by its duration, on top of any deadline the
+// caller's context already has, so a runaway query is cancelled whatever the
+// driver. Row and Rows results are read after the statement returns, so they
+// are left unbounded.
+type QueryTimeout time.Duration
+
+func (QueryTimeout) Name() string {
+	return "repository:query_timeout"
+}
+
+func (t QueryTimeout) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("repository:query_timeout_start", t.start),
+		cb.Create().After("*").Register("repository:query_timeout_stop", t.stop),
+		cb.Query().Before("*").Register("repository:query_timeout_start", t.start),
+		cb.Query().After("*").Register("repository:query_timeout_stop", t.stop),
+		cb.Update().Before("*").Register("repository:query_timeout_start", t.start),
+		cb.Update().After("*").Register("repository:query_timeout_stop", t.stop),
+		cb.Delete().Before("*").Register("repository:query_timeout_start", t.start),
+		cb.Delete().After("*").Register("repository:query_timeout_stop", t.stop),
+		cb.Raw().Before("*").Register("repository:query_timeout_start", t.start),
+		cb.Raw().After("*").Register("repository:query_timeout_stop", t.stop),
+	)
+}
+
+func (t QueryTimeout) start(db *gorm.DB) {
+	parent := db.Statement.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, time.Duration(t))
+	db.Statement.Context = ctx
+	db.Statement.Settings.Store(queryTimeoutKey{}, queryDeadline{parent: parent, cancel: cancel})
+}
+
+// stop releases the deadline and restores the caller's context, as a query
+// builder may be reused for another statement.
+func (QueryTimeout) stop(db *gorm.DB) {
+	v, ok := db.Statement.Settings.LoadAndDelete(queryTimeoutKey{})
+	if !ok {
+		return
+	}
+	deadline := v.(queryDeadline)
+	deadline.cancel()
+	db.Statement.Context = deadline.parent
+}
\ No newline at end of file
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTimeout(t *testing.T) {
+	t.Run("cancelled context fails promptly", func(t *testing.T) {
+		db := setupTestDB(t)
+		require.NoError(t, db.Use(QueryTimeout(time.Second)))
+		repo := NewUserRepository(db)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		start := time.Now()
+		_, _, err := repo.FindAll(ctx, UserFilter{}, Sort{}, 1, 10)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("statement exceeding the timeout is cancelled", func(t *testing.T) {
+		db := setupTestDB(t)
+		require.NoError(t, db.Use(QueryTimeout(time.Nanosecond)))
+
+		_, err := NewUserRepository(db).FindByEmail(context.Background(), "john@example.com")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("statements within the timeout succeed", func(t *testing.T) {
+		db := setupTestDB(t)
+		require.NoError(t, db.Use(QueryTimeout(time.Minute)))
+		repo := NewUserRepository(db)
+		ctx := context.Background()
+		seedUsers(t, db, model.User{Name: "John", Email: "john@example.com", Password: "x", IsActive: true})
+
+		// the count and the page run on the same query builder
+		users, total, err := repo.FindAll(ctx, UserFilter{}, Sort{}, 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Len(t, users, 1)
+
+		users[0].Name = "Johnny"
+		assert.NoError(t, repo.Update(ctx, &users[0]))
+	})
+}
\ No newline at end of file
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// WithTx binds tx to ctx. Repositories called with the returned context run
+// their queries inside tx instead of on their own connection.
+func WithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// Conn returns the transaction bound to ctx by WithTx, or db when there is
+// none, scoped to ctx.
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
\ No newline at end of file
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestWithTx(t *testing.T) {
+	db := setupTestDB(t)
+	users := NewUserRepository(db)
+	tokens := NewUserTokenRepository(db)
+
+	tx := db.Begin()
+	require.NoError(t, tx.Error)
+	ctx := WithTx(context.Background(), tx)
+
+	user := &model.User{Name: "John", Email: "john@example.com", Password: "x", Role: "user"}
+	require.NoError(t, users.Create(ctx, user))
+	require.NoError(t, tokens.Create(ctx, &model.UserToken{UserID: user.ID, Purpose: model.TokenPurposeEmailVerification, TokenHash: "hash"}))
+
+	// reads through the same context see the uncommitted rows
+	_, err := users.FindByID(ctx, user.ID.String())
+	require.NoError(t, err)
+	_, err = tokens.FindByHash(ctx, model.TokenPurposeEmailVerification, "hash")
+	require.NoError(t, err)
+
+	require.NoError(t, tx.Rollback().Error)
+
+	ctx = context.Background()
+	_, err = users.FindByID(ctx, user.ID.String())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "user should be rolled back")
+	_, err = tokens.FindByHash(ctx, model.TokenPurposeEmailVerification, "hash")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "token should be rolled back")
+}
\ No newline at end of file
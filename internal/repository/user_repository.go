@@ -2,20 +2,80 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/internal/tenant"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrStaleVersion is returned by Update when the user was changed since it
+// was read.
+var ErrStaleVersion = errors.New("user was modified concurrently")
+
 type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
+	CreateBatch(ctx context.Context, users []model.User) error
 	FindByID(ctx context.Context, id string) (*model.User, error)
 	FindByEmail(ctx context.Context, email string) (*model.User, error)
-	FindAll(ctx context.Context, page, perPage int) ([]model.User, int64, error)
+	FindDeletedByEmail(ctx context.Context, email string) (*model.User, error)
+	FindAll(ctx context.Context, filter UserFilter, sort Sort, page, perPage int) ([]model.User, int64, error)
+	FindAfter(ctx context.Context, filter UserFilter, cursor string, limit int) ([]model.User, string, error)
+	Search(ctx context.Context, query string, page, perPage int) ([]model.User, int64, error)
+	FindInBatches(ctx context.Context, filter UserFilter, batchSize int, fn func([]model.User) error) error
+	CountByRole(ctx context.Context, role string) (int64, error)
+	LockActiveAdmins(ctx context.Context) ([]string, error)
 	Update(ctx context.Context, user *model.User) error
+	Restore(ctx context.Context, user *model.User) error
 	Delete(ctx context.Context, id string) error
 }
 
+type UserFilter struct {
+	Role           string
+	IsActive       *bool
+	ApprovalStatus string
+	Search         string
+}
+
+// Scope applies the filter as equality conditions on role/is_active/approval_status and a
+// case-insensitive substring match of Search against name and email.
+func (f UserFilter) Scope(db *gorm.DB) *gorm.DB {
+	if f.Role != "" {
+		db = db.Where("role = ?", f.Role)
+	}
+	if f.IsActive != nil {
+		db = db.Where("is_active = ?", *f.IsActive)
+	}
+	if f.ApprovalStatus != "" {
+		db = db.Where("approval_status = ?", f.ApprovalStatus)
+	}
+	if f.Search != "" {
+		pattern := "%" + escapeLike(f.Search) + "%"
+		op := likeOperator(db)
+		db = db.Where(
+			db.Session(&gorm.Session{NewDB: true}).
+				Where("name "+op+" ? ESCAPE '!'", pattern).
+				Or("email "+op+" ? ESCAPE '!'", pattern),
+		)
+	}
+	return db
+}
+
+// tenantScope limits a query to the tenant bound to ctx, if any, so users of
+// one tenant are invisible to requests made within another.
+func tenantScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if id, ok := tenant.FromContext(ctx); ok {
+			return db.Where("tenant_id = ?", id)
+		}
+		return db
+	}
+}
+
+// userRepository scopes every query to the tenant in the context; see
+// tenantScope.
 type userRepository struct {
 	*BaseRepository[model.User]
 }
@@ -26,11 +86,197 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	}
 }
 
+// Create inserts the user into the tenant bound to ctx, if any. GORM
+// substitutes the is_active column default for a false value, so inactive
+// users are cleared in the same transaction.
+func (r *userRepository) Create(ctx context.Context, user *model.User) error {
+	if id, ok := tenant.FromContext(ctx); ok {
+		user.TenantID = id
+	}
+	if user.Version == 0 {
+		user.Version = 1
+	}
+	if user.IsActive {
+		return r.BaseRepository.Create(ctx, user)
+	}
+
+	return Conn(ctx, r.DB).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		user.IsActive = false
+		return tx.Model(user).Update("is_active", false).Error
+	})
+}
+
+func (r *userRepository) CreateBatch(ctx context.Context, users []model.User) error {
+	id, scoped := tenant.FromContext(ctx)
+	for i := range users {
+		if scoped {
+			users[i].TenantID = id
+		}
+		if users[i].Version == 0 {
+			users[i].Version = 1
+		}
+	}
+	return r.BaseRepository.CreateBatch(ctx, users)
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id string) (*model.User, error) {
+	var user model.User
+	err := Conn(ctx, r.DB).Scopes(tenantScope(ctx)).Where("id = ?", id).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	err := r.DB.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	err := Conn(ctx, r.DB).Scopes(tenantScope(ctx)).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
+}
+
+// FindDeletedByEmail finds a soft-deleted user, which FindByEmail doesn't
+// see but which still holds the email's unique index entry.
+func (r *userRepository) FindDeletedByEmail(ctx context.Context, email string) (*model.User, error) {
+	var user model.User
+	err := Conn(ctx, r.DB).Unscoped().Scopes(tenantScope(ctx)).
+		Where("email = ? AND deleted_at IS NOT NULL", email).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindAll(ctx context.Context, filter UserFilter, sort Sort, page, perPage int) ([]model.User, int64, error) {
+	return r.BaseRepository.FindAll(ctx, page, perPage, sort, tenantScope(ctx), filter.Scope)
+}
+
+func (r *userRepository) FindAfter(ctx context.Context, filter UserFilter, cursor string, limit int) ([]model.User, string, error) {
+	return r.BaseRepository.FindAfter(ctx, cursor, limit, tenantScope(ctx), filter.Scope)
+}
+
+// Search pages through users whose name or email contains query, ignoring
+// case. Users whose email equals query rank first; the rest are ordered by
+// name.
+func (r *userRepository) Search(ctx context.Context, query string, page, perPage int) ([]model.User, int64, error) {
+	filter := UserFilter{Search: query}
+
+	var total int64
+	if err := Conn(ctx, r.DB).Model(&model.User{}).Scopes(tenantScope(ctx), filter.Scope).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []model.User
+	err := Conn(ctx, r.DB).Scopes(tenantScope(ctx), filter.Scope).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "CASE WHEN LOWER(email) = LOWER(?) THEN 0 ELSE 1 END, name, id",
+			Vars: []interface{}{query},
+		}}).
+		Offset((page - 1) * perPage).Limit(perPage).Find(&users).Error
+
+	return users, total, err
+}
+
+// FindInBatches calls fn with successive batches of the users matching filter,
+// ordered by primary key, so the whole table can be walked without loading it
+// at once. An error from fn stops the walk and is returned.
+func (r *userRepository) FindInBatches(ctx context.Context, filter UserFilter, batchSize int, fn func([]model.User) error) error {
+	var batch []model.User
+	return Conn(ctx, r.DB).Scopes(tenantScope(ctx), filter.Scope).FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+func (r *userRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	var count int64
+	err := Conn(ctx, r.DB).Model(&model.User{}).Scopes(tenantScope(ctx)).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
+// LockActiveAdmins returns the IDs of the admins who can sign in: active and
+// approved. Their rows stay locked until the transaction in ctx ends, so a
+// concurrent caller waits and then sees the admins as they were committed.
+// SQLite has no row locks, but serialises write transactions anyway.
+func (r *userRepository) LockActiveAdmins(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := Conn(ctx, r.DB).Model(&model.User{}).Scopes(tenantScope(ctx)).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("role = ? AND is_active = ? AND approval_status = ?", "admin", true, model.ApprovalApproved).
+		Order("id").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Update saves every field of user except its tenant and bumps its Version.
+// The row must still be at user.Version: if it was changed since it was read,
+// ErrStaleVersion is returned and nothing is written. A user that is missing,
+// or belongs to another tenant, is reported as gorm.ErrRecordNotFound.
+func (r *userRepository) Update(ctx context.Context, user *model.User) error {
+	expected := user.Version
+	user.Version++
+
+	result := Conn(ctx, r.DB).Model(user).Scopes(tenantScope(ctx)).
+		Where("version = ?", expected).
+		Select("*").Omit("tenant_id").Updates(user)
+	if result.Error != nil {
+		user.Version = expected
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	user.Version = expected
+	var count int64
+	if err := Conn(ctx, r.DB).Model(&model.User{}).Scopes(tenantScope(ctx)).Where("id = ?", user.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return ErrStaleVersion
+}
+
+// Restore undeletes a soft-deleted user, saving every field of user except
+// its tenant and creation time, and bumps its Version. A user that isn't
+// deleted, or belongs to another tenant, is reported as gorm.ErrRecordNotFound.
+func (r *userRepository) Restore(ctx context.Context, user *model.User) error {
+	user.DeletedAt = gorm.DeletedAt{}
+	user.Version++
+
+	result := Conn(ctx, r.DB).Unscoped().Model(user).Scopes(tenantScope(ctx)).
+		Where("deleted_at IS NOT NULL").
+		Select("*").Omit("tenant_id", "created_at").Updates(user)
+	if result.Error != nil {
+		user.Version--
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		user.Version--
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) Delete(ctx context.Context, id string) error {
+	return Conn(ctx, r.DB).Scopes(tenantScope(ctx)).Where("id = ?", id).Delete(&model.User{}).Error
+}
+
+// likeOperator returns ILIKE on Postgres; other drivers' LIKE is already
+// case-insensitive for ASCII.
+func likeOperator(db *gorm.DB) string {
+	if db.Dialector.Name() == "postgres" {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
+
+// escapeLike escapes LIKE wildcards using '!' so the pattern is portable
+// across drivers that treat backslashes differently in string literals.
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
 }
\ No newline at end of file
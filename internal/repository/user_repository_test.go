@@ -0,0 +1,474 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/internal/tenant"
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// every connection to :memory: is a separate database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	require.NoError(t, db.AutoMigrate(&model.User{}, &model.UserToken{}))
+	return db
+}
+
+func seedUsers(t *testing.T, db *gorm.DB, users ...model.User) {
+	t.Helper()
+	for i := range users {
+		// is_active has a DB default of true, so false must be written explicitly
+		isActive := users[i].IsActive
+		require.NoError(t, db.Create(&users[i]).Error)
+		if !isActive {
+			require.NoError(t, db.Model(&users[i]).Update("is_active", false).Error)
+		}
+	}
+}
+
+func TestUserRepository_FindAll_Filters(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	seedUsers(t, db,
+		model.User{Name: "Alice Admin", Email: "alice@example.com", Password: "x", Role: "admin", IsActive: true},
+		model.User{Name: "Bob Admin", Email: "bob@corp.io", Password: "x", Role: "admin", IsActive: false},
+		model.User{Name: "Carol", Email: "carol@example.com", Password: "x", Role: "user", IsActive: true, ApprovalStatus: model.ApprovalPending},
+		model.User{Name: "Dave 100%", Email: "dave@corp.io", Password: "x", Role: "user", IsActive: true},
+	)
+
+	active := true
+	tests := []struct {
+		name     string
+		filter   UserFilter
+		expected []string
+	}{
+		{name: "no filter returns all", filter: UserFilter{}, expected: []string{"alice@example.com", "bob@corp.io", "carol@example.com", "dave@corp.io"}},
+		{name: "role filter", filter: UserFilter{Role: "admin"}, expected: []string{"alice@example.com", "bob@corp.io"}},
+		{name: "active admins", filter: UserFilter{Role: "admin", IsActive: &active}, expected: []string{"alice@example.com"}},
+		{name: "search matches name case-insensitively", filter: UserFilter{Search: "ADMIN"}, expected: []string{"alice@example.com", "bob@corp.io"}},
+		{name: "search matches email", filter: UserFilter{Search: "corp.io"}, expected: []string{"bob@corp.io", "dave@corp.io"}},
+		{name: "approval status filter", filter: UserFilter{ApprovalStatus: model.ApprovalPending}, expected: []string{"carol@example.com"}},
+		{name: "search combined with role", filter: UserFilter{Role: "user", Search: "corp"}, expected: []string{"dave@corp.io"}},
+		{name: "wildcards in search are literal", filter: UserFilter{Search: "100%"}, expected: []string{"dave@corp.io"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, total, err := repo.FindAll(ctx, tt.filter, DefaultSort, 1, 10)
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.expected)), total)
+
+			emails := make([]string, len(users))
+			for i, u := range users {
+				emails[i] = u.Email
+			}
+			assert.ElementsMatch(t, tt.expected, emails)
+		})
+	}
+}
+
+func TestUserRepository_FindAll_PaginatesFilteredResults(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	seedUsers(t, db,
+		model.User{Name: "User One", Email: "one@example.com", Password: "x", Role: "user", IsActive: true},
+		model.User{Name: "User Two", Email: "two@example.com", Password: "x", Role: "user", IsActive: true},
+		model.User{Name: "User Three", Email: "three@example.com", Password: "x", Role: "user", IsActive: true},
+		model.User{Name: "Admin", Email: "admin@example.com", Password: "x", Role: "admin", IsActive: true},
+	)
+
+	users, total, err := repo.FindAll(ctx, UserFilter{Role: "user"}, DefaultSort, 2, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, users, 1)
+}
+
+func TestUserRepository_FindAll_Sort(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	seedUsers(t, db,
+		model.User{Base: model.Base{CreatedAt: base.Add(2 * time.Hour)}, Name: "Bravo", Email: "c@example.com", Password: "x", IsActive: true},
+		model.User{Base: model.Base{CreatedAt: base}, Name: "Charlie", Email: "a@example.com", Password: "x", IsActive: true},
+		model.User{Base: model.Base{CreatedAt: base.Add(time.Hour)}, Name: "Alpha", Email: "b@example.com", Password: "x", IsActive: true},
+	)
+
+	tests := []struct {
+		name     string
+		sort     Sort
+		expected []string
+	}{
+		{name: "default is newest first", sort: Sort{}, expected: []string{"Bravo", "Alpha", "Charlie"}},
+		{name: "name ascending", sort: Sort{Column: "name"}, expected: []string{"Alpha", "Bravo", "Charlie"}},
+		{name: "email descending", sort: Sort{Column: "email", Desc: true}, expected: []string{"Bravo", "Alpha", "Charlie"}},
+		{name: "created_at ascending", sort: Sort{Column: "created_at"}, expected: []string{"Charlie", "Alpha", "Bravo"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, _, err := repo.FindAll(ctx, UserFilter{}, tt.sort, 1, 10)
+
+			require.NoError(t, err)
+			names := make([]string, len(users))
+			for i, u := range users {
+				names[i] = u.Name
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestUserRepository_FindAfter(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var users []model.User
+	for i := 0; i < 5; i++ {
+		users = append(users, model.User{
+			Base:     model.Base{CreatedAt: base.Add(time.Duration(i) * time.Minute)},
+			Name:     fmt.Sprintf("User %d", i),
+			Email:    fmt.Sprintf("user%d@example.com", i),
+			Password: "x",
+			IsActive: true,
+		})
+	}
+	// two rows sharing a timestamp exercise the id tie-breaker
+	users = append(users, model.User{
+		Base:     model.Base{CreatedAt: base.Add(2 * time.Minute)},
+		Name:     "User 2b",
+		Email:    "user2b@example.com",
+		Password: "x",
+		IsActive: true,
+	})
+	seedUsers(t, db, users...)
+
+	var seen []string
+	cursor := ""
+	pages := 0
+	for {
+		page, next, err := repo.FindAfter(ctx, UserFilter{}, cursor, 4)
+		require.NoError(t, err)
+		pages++
+		for _, u := range page {
+			seen = append(seen, u.Email)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	assert.Equal(t, 2, pages)
+	assert.Len(t, seen, 6)
+	assert.ElementsMatch(t, []string{
+		"user0@example.com", "user1@example.com", "user2@example.com",
+		"user2b@example.com", "user3@example.com", "user4@example.com",
+	}, seen)
+	assert.Equal(t, "user4@example.com", seen[0])
+	assert.Equal(t, "user0@example.com", seen[5])
+}
+
+func TestUserRepository_FindAfter_EndOfList(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	seedUsers(t, db,
+		model.User{Name: "Only", Email: "only@example.com", Password: "x", IsActive: true},
+	)
+
+	users, next, err := repo.FindAfter(ctx, UserFilter{}, "", 10)
+
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Empty(t, next)
+}
+
+func TestUserRepository_FindAfter_InvalidCursor(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	_, _, err := repo.FindAfter(context.Background(), UserFilter{}, "not-a-cursor", 10)
+
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestUserRepository_Create_PersistsInactiveUser(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	user := &model.User{Name: "Pending", Email: "pending@example.com", Password: "x", Role: "user", IsActive: false}
+	require.NoError(t, repo.Create(ctx, user))
+
+	found, err := repo.FindByID(ctx, user.ID.String())
+	require.NoError(t, err)
+	assert.False(t, found.IsActive)
+}
+
+func TestUserRepository_Create_AssignsID(t *testing.T) {
+	repo := NewUserRepository(setupTestDB(t))
+	ctx := context.Background()
+
+	user := &model.User{Name: "Alice", Email: "alice@example.com", Password: "x", Role: "user"}
+	require.NoError(t, repo.Create(ctx, user))
+
+	assert.NotEqual(t, uuid.Nil, user.ID)
+	assert.Equal(t, uuid.Version(4), user.ID.Version())
+	_, err := repo.FindByID(ctx, user.ID.String())
+	assert.NoError(t, err)
+
+	explicit := uuid.New()
+	user = &model.User{Base: model.Base{ID: explicit}, Name: "Bob", Email: "bob@example.com", Password: "x", Role: "user"}
+	require.NoError(t, repo.Create(ctx, user))
+	assert.Equal(t, explicit, user.ID, "an explicit id must be kept")
+}
+
+func TestUserRepository_Create_SequenceIDs(t *testing.T) {
+	createUsers := func() []string {
+		restore := model.SetIDGenerator(&model.SequenceIDs{})
+		defer restore()
+
+		repo := NewUserRepository(setupTestDB(t))
+		var ids []string
+		for _, name := range []string{"alice", "bob"} {
+			user := &model.User{Name: name, Email: name + "@example.com", Password: "x", Role: "user"}
+			require.NoError(t, repo.Create(context.Background(), user))
+			ids = append(ids, user.ID.String())
+		}
+		return ids
+	}
+
+	first := createUsers()
+	assert.Equal(t, []string{
+		"00000000-0000-4000-8000-000000000001",
+		"00000000-0000-4000-8000-000000000002",
+	}, first)
+	assert.Equal(t, first, createUsers(), "ids should be the same on every run")
+}
+
+func TestUserRepository_LockActiveAdmins(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+
+	seedUsers(t, db,
+		model.User{Name: "Alice", Email: "alice@example.com", Password: "x", Role: "admin", IsActive: true},
+		model.User{Name: "Bob", Email: "bob@example.com", Password: "x", Role: "admin", IsActive: false},
+		model.User{Name: "Carol", Email: "carol@example.com", Password: "x", Role: "admin", IsActive: true, ApprovalStatus: model.ApprovalPending},
+		model.User{Name: "Dave", Email: "dave@example.com", Password: "x", Role: "user", IsActive: true},
+		model.User{Name: "Erin", Email: "erin@example.com", Password: "x", Role: "admin", IsActive: true, TenantID: "tenant-b"},
+	)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		admins, err := repo.LockActiveAdmins(tenant.WithID(WithTx(context.Background(), tx), ""))
+		require.NoError(t, err)
+		require.Len(t, admins, 1)
+		var alice model.User
+		require.NoError(t, tx.Where("email = ?", "alice@example.com").First(&alice).Error)
+		assert.Equal(t, alice.ID.String(), admins[0])
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestUserRepository_CountByRole(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	seedUsers(t, db,
+		model.User{Name: "Admin One", Email: "a1@example.com", Password: "x", Role: "admin", IsActive: true},
+		model.User{Name: "Admin Two", Email: "a2@example.com", Password: "x", Role: "admin", IsActive: false},
+		model.User{Name: "User", Email: "u@example.com", Password: "x", Role: "user", IsActive: true},
+	)
+
+	admins, err := repo.CountByRole(ctx, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), admins)
+
+	users, err := repo.CountByRole(ctx, "user")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), users)
+}
+
+func TestUserRepository_FindInBatches(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		seedUsers(t, db, model.User{Name: fmt.Sprintf("User %d", i), Email: fmt.Sprintf("user%d@example.com", i), Password: "x", Role: "user", IsActive: true})
+	}
+	seedUsers(t, db, model.User{Name: "Admin", Email: "admin@example.com", Password: "x", Role: "admin", IsActive: true})
+
+	var sizes []int
+	seen := map[string]bool{}
+	err := repo.FindInBatches(ctx, UserFilter{Role: "user"}, 2, func(users []model.User) error {
+		sizes = append(sizes, len(users))
+		for _, u := range users {
+			seen[u.Email] = true
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 2, 1}, sizes)
+	assert.Len(t, seen, 5)
+	assert.False(t, seen["admin@example.com"])
+}
+
+func TestUserRepository_Search_RanksExactEmailFirst(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	seedUsers(t, db,
+		model.User{Name: "Alice (ann@example.com)", Email: "alice@example.com", Password: "x", IsActive: true},
+		model.User{Name: "Joann", Email: "joann@example.com", Password: "x", IsActive: true},
+		model.User{Name: "Zara", Email: "ann@example.com", Password: "x", IsActive: true},
+		model.User{Name: "Bob", Email: "bob@example.com", Password: "x", IsActive: true},
+	)
+
+	users, total, err := repo.Search(ctx, "ANN@example.com", 1, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	names := make([]string, len(users))
+	for i, u := range users {
+		names[i] = u.Name
+	}
+	assert.Equal(t, []string{"Zara", "Alice (ann@example.com)", "Joann"}, names)
+
+	users, total, err = repo.Search(ctx, "ann@example.com", 2, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, users, 1)
+	assert.Equal(t, "Joann", users[0].Name)
+}
+func TestUserRepository_TenantIsolation(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+	tenantA := tenant.WithID(context.Background(), "tenant-a")
+	tenantB := tenant.WithID(context.Background(), "tenant-b")
+
+	alice := &model.User{Name: "Alice", Email: "alice@example.com", Password: "x", Role: "admin", IsActive: true}
+	require.NoError(t, repo.Create(tenantA, alice))
+	assert.Equal(t, "tenant-a", alice.TenantID)
+	bob := &model.User{Name: "Bob", Email: "bob@example.com", Password: "x", Role: "admin", IsActive: true, TenantID: "tenant-a"}
+	require.NoError(t, repo.Create(tenantB, bob))
+	assert.Equal(t, "tenant-b", bob.TenantID, "the context decides the tenant, not the caller")
+	seedUsers(t, db, model.User{Name: "Nobody", Email: "nobody@example.com", Password: "x", IsActive: true})
+
+	_, err := repo.FindByID(tenantB, alice.ID.String())
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = repo.FindByEmail(tenantB, alice.Email)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	found, err := repo.FindByID(tenantA, alice.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, alice.Email, found.Email)
+
+	users, total, err := repo.FindAll(tenantB, UserFilter{}, DefaultSort, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, users, 1)
+	assert.Equal(t, "bob@example.com", users[0].Email)
+
+	users, _, err = repo.FindAfter(tenantB, UserFilter{}, "", 10)
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+
+	_, total, err = repo.Search(tenantB, "example.com", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	admins, err := repo.CountByRole(tenantB, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), admins)
+
+	// a request bound to no tenant sees only users without one
+	_, total, err = repo.FindAll(tenant.WithID(context.Background(), ""), UserFilter{}, DefaultSort, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	// unscoped callers such as the CLI see everyone
+	_, total, err = repo.FindAll(context.Background(), UserFilter{}, DefaultSort, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+
+	alice.Name = "Mallory"
+	assert.ErrorIs(t, repo.Update(tenantB, alice), gorm.ErrRecordNotFound)
+	require.NoError(t, repo.Delete(tenantB, alice.ID.String()))
+
+	found, err = repo.FindByID(tenantA, alice.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", found.Name)
+
+	found.Name = "Alice Smith"
+	require.NoError(t, repo.Update(tenantA, found))
+	found, err = repo.FindByID(tenantA, alice.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "Alice Smith", found.Name)
+	assert.Equal(t, "tenant-a", found.TenantID)
+}
+func TestUserRepository_Update_RejectsStaleVersion(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	user := &model.User{Name: "Alice", Email: "alice@example.com", Password: "x", IsActive: true}
+	require.NoError(t, repo.Create(ctx, user))
+	assert.Equal(t, uint(1), user.Version)
+
+	// two requests read the same version
+	first, err := repo.FindByID(ctx, user.ID.String())
+	require.NoError(t, err)
+	second, err := repo.FindByID(ctx, user.ID.String())
+	require.NoError(t, err)
+
+	first.Name = "Alice First"
+	require.NoError(t, repo.Update(ctx, first))
+	assert.Equal(t, uint(2), first.Version)
+
+	second.Name = "Alice Second"
+	assert.ErrorIs(t, repo.Update(ctx, second), ErrStaleVersion)
+	assert.Equal(t, uint(1), second.Version, "a failed update leaves the version as read")
+
+	stored, err := repo.FindByID(ctx, user.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "Alice First", stored.Name)
+	assert.Equal(t, uint(2), stored.Version)
+
+	missing := &model.User{Base: model.Base{ID: uuid.New()}, Name: "Ghost", Email: "ghost@example.com", Version: 1}
+	assert.ErrorIs(t, repo.Update(ctx, missing), gorm.ErrRecordNotFound)
+}
\ No newline at end of file
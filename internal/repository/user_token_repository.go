@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ariam/my-api/internal/model"
+	"gorm.io/gorm"
+)
+
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *model.UserToken) error
+	FindByHash(ctx context.Context, purpose, hash string) (*model.UserToken, error)
+	MarkUsed(ctx context.Context, id string) (bool, error)
+	MarkAllUsed(ctx context.Context, userID string) error
+}
+
+type userTokenRepository struct {
+	*BaseRepository[model.UserToken]
+}
+
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{
+		BaseRepository: NewBaseRepository[model.UserToken](db),
+	}
+}
+
+func (r *userTokenRepository) FindByHash(ctx context.Context, purpose, hash string) (*model.UserToken, error) {
+	var token model.UserToken
+	err := Conn(ctx, r.DB).Where("purpose = ? AND token_hash = ?", purpose, hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed consumes the token and reports whether this call was the one that
+// did, so concurrent redemptions of the same token cannot both succeed.
+func (r *userTokenRepository) MarkUsed(ctx context.Context, id string) (bool, error) {
+	result := Conn(ctx, r.DB).
+		Model(&model.UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// MarkAllUsed consumes every outstanding token of the user, so links already
+// emailed to them stop working.
+func (r *userTokenRepository) MarkAllUsed(ctx context.Context, userID string) error {
+	return Conn(ctx, r.DB).
+		Model(&model.UserToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
\ No newline at end of file
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserTokenRepository_MarkUsedOnlyOnce(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewUserTokenRepository(db)
+	ctx := context.Background()
+
+	token := &model.UserToken{
+		UserID:    uuid.New(),
+		Purpose:   model.TokenPurposeEmailVerification,
+		TokenHash: "hash",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	require.NoError(t, repo.Create(ctx, token))
+
+	found, err := repo.FindByHash(ctx, model.TokenPurposeEmailVerification, "hash")
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, found.ID)
+
+	_, err = repo.FindByHash(ctx, "password_reset", "hash")
+	assert.Error(t, err)
+
+	consumed, err := repo.MarkUsed(ctx, token.ID.String())
+	require.NoError(t, err)
+	assert.True(t, consumed)
+
+	consumed, err = repo.MarkUsed(ctx, token.ID.String())
+	require.NoError(t, err)
+	assert.False(t, consumed)
+}
\ No newline at end of file
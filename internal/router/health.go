@@ -0,0 +1,36 @@
+package router
+
+import (
+	"context"
+	"time"
+
+	"github.com/ariam/my-api/internal/handler"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// readinessTimeout bounds each dependency check of a readiness probe.
+const readinessTimeout = 2 * time.Second
+
+// SetupHealth serves the readiness probe at /health/ready, checking the
+// database and, when configured, Redis. rdb is nil when Redis is not
+// configured.
+func SetupHealth(app *fiber.App, db *gorm.DB, rdb *redis.Client) {
+	checkers := []handler.HealthChecker{
+		handler.HealthCheck("database", func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		}),
+	}
+	if rdb != nil {
+		checkers = append(checkers, handler.HealthCheck("redis", func(ctx context.Context) error {
+			return rdb.Ping(ctx).Err()
+		}))
+	}
+
+	app.Get("/health/ready", handler.NewHealthHandler(readinessTimeout, checkers...).Ready)
+}
\ No newline at end of file
@@ -1,35 +1,149 @@
 package router
 
 import (
+	"time"
+
+	"github.com/ariam/my-api/internal/config"
+	"github.com/ariam/my-api/internal/event"
 	"github.com/ariam/my-api/internal/handler"
 	"github.com/ariam/my-api/internal/middleware"
 	"github.com/ariam/my-api/internal/repository"
 	"github.com/ariam/my-api/internal/service"
+	"github.com/ariam/my-api/pkg/cache"
 	"github.com/ariam/my-api/pkg/jwt"
 	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
-func Setup(app *fiber.App, db *gorm.DB, jwtManager *jwt.JWTManager) {
+// maintenanceExemptPaths are served even in full maintenance: the endpoint
+// that switches it, and login, so reads keep working once access tokens
+// expire and an admin can always sign in to switch maintenance back off.
+var maintenanceExemptPaths = []string{"/api/v1/admin/maintenance", "/api/v1/auth/login"}
+
+// idempotencyTTL is how long a response is replayed for a repeated
+// Idempotency-Key.
+const idempotencyTTL = 24 * time.Hour
+
+// Setup registers the API routes. rdb is nil when Redis is not configured.
+func Setup(app *fiber.App, cfg *config.Config, db *gorm.DB, rdb *redis.Client, jwtManager *jwt.JWTManager) {
 	userRepo := repository.NewUserRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	tokenRepo := repository.NewUserTokenRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+
+	events := event.NewBus()
+	pageLimits := service.PageLimits{DefaultPerPage: cfg.Pagination.DefaultPerPage, MaxPerPage: cfg.Pagination.MaxPerPage}
 
-	userService := service.NewUserService(userRepo)
-	authService := service.NewAuthService(userRepo, jwtManager)
+	userOpts := []service.UserServiceOption{
+		service.WithBulkImport(cfg.Bulk.BatchSize, cfg.Bulk.MaxItems),
+		service.WithSessionRepository(sessionRepo),
+		service.WithTransactor(service.NewTransactor(db)),
+		service.WithAuditLog(auditRepo),
+		service.WithEvents(events),
+		service.WithUserTokens(tokenRepo, cfg.Mail.Mailer()),
+		service.WithPasswordReset(cfg.PasswordReset.URL, time.Duration(cfg.PasswordReset.TTLMinutes)*time.Minute),
+		service.WithDeletedEmailPolicy(cfg.Signup.DeletedEmailPolicy),
+		service.WithPageLimits(pageLimits),
+	}
+	if cfg.Signup.RequireVerification {
+		userOpts = append(userOpts, service.WithEmailVerification(cfg.App.BaseURL,
+			time.Duration(cfg.Signup.VerificationTTLHours)*time.Hour))
+	}
+	if cfg.Signup.RequireApproval {
+		userOpts = append(userOpts, service.WithApproval())
+	}
+	if cfg.Cache.TTLSeconds > 0 {
+		userOpts = append(userOpts, service.WithCache(newCache(cfg, rdb), time.Duration(cfg.Cache.TTLSeconds)*time.Second))
+	}
+	userService := service.NewUserService(userRepo, userOpts...)
+	authService := service.NewAuthService(userRepo, jwtManager,
+		service.WithSessions(sessionRepo, service.SessionPolicy{
+			MaxActive:     cfg.Session.MaxActive,
+			RoleLimits:    cfg.Session.RoleLimits,
+			RejectOnLimit: cfg.Session.LimitPolicy == "reject",
+		}),
+		service.WithRememberMe(time.Duration(cfg.JWT.RememberMeExpireHours)*time.Hour),
+	)
 
-	userHandler := handler.NewUserHandler(userService)
-	authHandler := handler.NewAuthHandler(authService)
+	userHandler := handler.NewUserHandler(userService, handler.WithPageLimits(pageLimits))
+	authHandler := handler.NewAuthHandler(authService, userService,
+		handler.WithPublicUserFields(cfg.App.PublicUserFields...),
+	)
+	auditHandler := handler.NewAuditHandler(service.NewAuditService(auditRepo, service.WithAuditPageLimits(pageLimits)),
+		handler.WithAuditPageLimits(pageLimits),
+	)
+	eventHandler := handler.NewEventHandler(events)
+	maintenance := middleware.NewMaintenance(cfg.Maintenance.Mode)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenance)
+
+	authRequired := middleware.Auth(jwtManager, authService)
+	selfOrAdmin := middleware.SelfOrRole("id", "admin")
+	idempotent := middleware.Idempotency(middleware.NewMemoryIdempotencyStore(), idempotencyTTL)
+	cached := func(c *fiber.Ctx) error { return c.Next() }
+	// writes to a user drop the caller's cached copy of it, so the version a
+	// client reads back is current
+	evictCachedUser := cached
+	if cfg.Cache.ResponseTTLSeconds > 0 {
+		responses := newCache(cfg, rdb)
+		cached = middleware.CacheGET(responses, time.Duration(cfg.Cache.ResponseTTLSeconds)*time.Second)
+		evictCachedUser = middleware.EvictCachedGET(responses, func(c *fiber.Ctx) string {
+			return "/api/v1/users/" + c.Params("id")
+		})
+	}
+	matchesSpec := middleware.RequestSchema(requestSpec())
+	loginLimit := middleware.RateLimit(cfg.RateLimit.LoginMax, time.Duration(cfg.RateLimit.LoginWindow)*time.Second, middleware.LoginKey, middleware.LimiterStorage(rdb, "login"))
+
+	app.Use(middleware.MaintenanceMode(maintenance, time.Duration(cfg.Maintenance.RetryAfter)*time.Second, maintenanceExemptPaths...))
+	// bulk import streams its body and bounds it by item count instead
+	app.Use(middleware.BodyLimit(cfg.App.MaxBodyBytes, "/api/v1/users/bulk"))
 
 	api := app.Group("/api")
 	v1 := api.Group("/v1")
 
 	auth := v1.Group("/auth")
-	auth.Post("/login", authHandler.Login)
-	auth.Get("/me", middleware.Auth(jwtManager), authHandler.Me)
+	auth.Post("/register", loginLimit, matchesSpec, middleware.RejectSuspiciousInput(), authHandler.Register)
+	auth.Post("/login", loginLimit, matchesSpec, authHandler.Login)
+	auth.Get("/me", authRequired, authHandler.Me)
+	auth.Get("/permissions", authRequired, authHandler.Permissions)
+	auth.Get("/sessions", authRequired, authHandler.Sessions)
+	auth.Delete("/sessions/:id", authRequired, authHandler.RevokeSession)
+	auth.Get("/verify", userHandler.VerifyEmail)
+	auth.Post("/forgot-password", loginLimit, userHandler.ForgotPassword)
+	auth.Post("/reset-password", userHandler.ResetPassword)
 
 	users := v1.Group("/users")
-	users.Post("/", userHandler.Create)
-	users.Get("/", middleware.Auth(jwtManager), userHandler.FindAll)
-	users.Get("/:id", middleware.Auth(jwtManager), userHandler.FindByID)
-	users.Put("/:id", middleware.Auth(jwtManager), userHandler.Update)
-	users.Delete("/:id", middleware.Auth(jwtManager), middleware.RoleRequired("admin"), userHandler.Delete)
+	users.Post("/", authRequired, middleware.RoleRequired("admin"), matchesSpec, middleware.RejectSuspiciousInput(), idempotent, userHandler.Create)
+	users.Post("/bulk", authRequired, middleware.RoleRequired("admin"), userHandler.BulkCreate)
+	users.Get("/", authRequired, cached, userHandler.FindAll)
+	users.Get("/search", authRequired, cached, userHandler.Search)
+	users.Get("/by-email", authRequired, middleware.RoleRequired("admin"), userHandler.FindByEmail)
+	users.Get("/export", authRequired, middleware.RoleRequired("admin"), userHandler.Export)
+	users.Get("/pending", authRequired, middleware.RoleRequired("admin"), userHandler.FindPending)
+	users.Get("/me", authRequired, userHandler.Me)
+	users.Get("/me/export", authRequired, middleware.RateLimit(3, time.Hour, nil, middleware.LimiterStorage(rdb, "personal-export")), userHandler.ExportPersonalData)
+	// ahead of the HEAD route Get adds, so existence checks skip serializing the user
+	users.Head("/:id", authRequired, selfOrAdmin, userHandler.Exists)
+	users.Get("/:id", authRequired, selfOrAdmin, cached, userHandler.FindByID)
+	users.Put("/:id", authRequired, selfOrAdmin, evictCachedUser, matchesSpec, middleware.RejectSuspiciousInput(), userHandler.Update)
+	users.Patch("/:id", authRequired, selfOrAdmin, evictCachedUser, matchesSpec, middleware.RejectSuspiciousInput(), userHandler.Patch)
+	users.Patch("/:id/role", authRequired, middleware.RoleRequired("admin"), evictCachedUser, matchesSpec, userHandler.SetRole)
+	users.Delete("/:id", authRequired, middleware.RoleRequired("admin"), evictCachedUser, userHandler.Delete)
+	users.Post("/:id/approve", authRequired, middleware.RoleRequired("admin"), evictCachedUser, userHandler.Approve)
+	users.Post("/:id/reject", authRequired, middleware.RoleRequired("admin"), evictCachedUser, userHandler.Reject)
+	users.Post("/:id/reset-password", authRequired, middleware.RoleRequired("admin"), evictCachedUser, matchesSpec, userHandler.AdminResetPassword)
+
+	v1.Get("/audit", authRequired, middleware.RoleRequired("admin"), auditHandler.FindAll)
+	v1.Get("/admin/maintenance", authRequired, middleware.RoleRequired("admin"), maintenanceHandler.Get)
+	v1.Put("/admin/maintenance", authRequired, middleware.RoleRequired("admin"), maintenanceHandler.Set)
+	v1.Get("/ws/users", middleware.WebSocketAuth(jwtManager, authService), middleware.RoleRequired("admin"), eventHandler.UserEvents)
+}
+
+// newCache returns a cache shared through Redis when it is configured, and an
+// in-memory one otherwise. Callers keep their keys apart by prefix.
+func newCache(cfg *config.Config, rdb *redis.Client) cache.Cache {
+	if rdb != nil {
+		return cache.NewRedis(rdb, cfg.App.Name+":")
+	}
+	return cache.NewLRU(cfg.Cache.Size)
 }
\ No newline at end of file
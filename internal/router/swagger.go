@@ -0,0 +1,29 @@
+package router
+
+import (
+	"github.com/ariam/my-api/docs"
+	"github.com/ariam/my-api/internal/config"
+	"github.com/ariam/my-api/pkg/openapi"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/swagger"
+)
+
+// SetupSwagger serves the Swagger UI and spec under /swagger when the config
+// enables it; otherwise the path is left unrouted and returns 404.
+func SetupSwagger(app *fiber.App, cfg *config.Config) {
+	if !cfg.App.SwaggerEnabled() {
+		return
+	}
+	app.Get("/swagger/*", swagger.HandlerDefault)
+}
+
+// requestSpec parses the generated spec for middleware.RequestSchema. Only a
+// broken swag run can make it invalid, so it panics rather than serve
+// unchecked routes.
+func requestSpec() *openapi.Spec {
+	spec, err := openapi.Parse([]byte(docs.SwaggerInfo.ReadDoc()))
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
\ No newline at end of file
@@ -0,0 +1,49 @@
+package router
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ariam/my-api/internal/config"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupSwagger(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name           string
+		app            config.AppConfig
+		expectedStatus int
+	}{
+		{name: "on by default in development", app: config.AppConfig{Env: "development"}, expectedStatus: fiber.StatusOK},
+		{name: "off by default in production", app: config.AppConfig{Env: "production"}, expectedStatus: fiber.StatusNotFound},
+		{name: "enabled in production", app: config.AppConfig{Env: "production", Swagger: &enabled}, expectedStatus: fiber.StatusOK},
+		{name: "disabled in development", app: config.AppConfig{Env: "development", Swagger: &disabled}, expectedStatus: fiber.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			SetupSwagger(app, &config.Config{App: tt.app})
+
+			for _, path := range []string{"/swagger/index.html", "/swagger/doc.json"} {
+				resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, resp.StatusCode, path)
+			}
+		})
+	}
+}
+func TestRequestSpec(t *testing.T) {
+	spec := requestSpec()
+
+	for _, route := range []string{"/api/v1/users/", "/api/v1/auth/login"} {
+		_, ok := spec.RequestBody("POST", route)
+		assert.True(t, ok, route)
+	}
+	_, ok := spec.RequestBody("PATCH", "/api/v1/users/:id/role")
+	assert.True(t, ok)
+}
\ No newline at end of file
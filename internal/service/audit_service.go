@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/internal/repository"
+	"github.com/google/uuid"
+)
+
+type actorKey struct{}
+
+// ContextWithActor records the authenticated user performing the request so
+// audit entries written further down the call chain can attribute it.
+func ContextWithActor(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorID)
+}
+
+func actorFromContext(ctx context.Context) *uuid.UUID {
+	raw, _ := ctx.Value(actorKey{}).(string)
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+type AuditService interface {
+	FindAll(ctx context.Context, page, perPage int) ([]model.AuditLog, int64, error)
+}
+
+type auditService struct {
+	auditRepo  repository.AuditRepository
+	pageLimits PageLimits
+}
+
+type AuditServiceOption func(*auditService)
+
+// WithAuditPageLimits bounds the page size of FindAll.
+func WithAuditPageLimits(limits PageLimits) AuditServiceOption {
+	return func(s *auditService) {
+		s.pageLimits = limits
+	}
+}
+
+func NewAuditService(auditRepo repository.AuditRepository, opts ...AuditServiceOption) AuditService {
+	s := &auditService{auditRepo: auditRepo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *auditService) FindAll(ctx context.Context, page, perPage int) ([]model.AuditLog, int64, error) {
+	page, perPage = s.pageLimits.Normalize(page, perPage)
+	return s.auditRepo.FindAll(ctx, page, perPage)
+}
+
+// WithAuditLog records user mutations in the audit log, in the same
+// transaction as the change when a Transactor is configured.
+func WithAuditLog(auditRepo repository.AuditRepository) UserServiceOption {
+	return func(s *userService) {
+		s.auditRepo = auditRepo
+	}
+}
+
+func (s *userService) audit(ctx context.Context, action string, user *model.User, metadata map[string]interface{}) error {
+	if s.auditRepo == nil {
+		return nil
+	}
+	return s.auditRepo.Create(ctx, &model.AuditLog{
+		ActorID:    actorFromContext(ctx),
+		Action:     action,
+		TargetType: model.AuditTargetUser,
+		TargetID:   user.ID.String(),
+		Metadata:   metadata,
+	})
+}
\ No newline at end of file
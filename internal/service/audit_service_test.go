@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestUserService_WritesAuditLog(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&model.AuditLog{}))
+
+	users := repository.NewUserRepository(db)
+	audits := repository.NewAuditRepository(db)
+	svc := NewUserService(users,
+		WithPasswordCost(bcrypt.MinCost),
+		WithTransactor(NewTransactor(db)),
+		WithAuditLog(audits),
+	)
+
+	adminID := uuid.New()
+	ctx := ContextWithActor(context.Background(), adminID.String())
+
+	created, err := svc.Create(context.Background(), &CreateUserInput{Name: "John Doe", Email: "john@example.com", Password: "Password123!"})
+	require.NoError(t, err)
+	_, err = svc.Update(ctx, created.ID, &UpdateUserInput{Name: "John Smith", Email: "john@example.com"})
+	require.NoError(t, err)
+	name := "Johnny"
+	_, err = svc.Patch(ctx, created.ID, &PatchUserInput{Name: &name})
+	require.NoError(t, err)
+	_, err = svc.SetRole(ctx, created.ID, "admin")
+	require.NoError(t, err)
+	require.NoError(t, svc.Delete(ctx, created.ID))
+
+	var entries []model.AuditLog
+	require.NoError(t, db.Order("created_at").Find(&entries).Error)
+	require.Len(t, entries, 5)
+
+	expected := []string{
+		model.AuditActionUserCreate,
+		model.AuditActionUserUpdate,
+		model.AuditActionUserUpdate,
+		model.AuditActionUserSetRole,
+		model.AuditActionUserDelete,
+	}
+	for i, entry := range entries {
+		assert.Equal(t, expected[i], entry.Action)
+		assert.Equal(t, model.AuditTargetUser, entry.TargetType)
+		assert.Equal(t, created.ID, entry.TargetID)
+	}
+
+	assert.Nil(t, entries[0].ActorID, "self-registration has no actor")
+	for _, entry := range entries[1:] {
+		require.NotNil(t, entry.ActorID)
+		assert.Equal(t, adminID, *entry.ActorID)
+	}
+	assert.Equal(t, map[string]interface{}{
+		"name": map[string]interface{}{"old": "John Doe", "new": "John Smith"},
+	}, entries[1].Metadata["changes"])
+	assert.Equal(t, map[string]interface{}{"from": "user", "to": "admin"}, entries[3].Metadata)
+}
+
+func TestUserService_AuditFailureRollsBackMutation(t *testing.T) {
+	db := setupTestDB(t)
+	// no audit_logs table, so writing the entry fails
+
+	users := repository.NewUserRepository(db)
+	svc := NewUserService(users,
+		WithPasswordCost(bcrypt.MinCost),
+		WithTransactor(NewTransactor(db)),
+		WithAuditLog(repository.NewAuditRepository(db)),
+	)
+
+	_, err := svc.Create(context.Background(), &CreateUserInput{Name: "John Doe", Email: "john@example.com", Password: "Password123!"})
+	assert.Error(t, err)
+
+	var count int64
+	require.NoError(t, db.Model(&model.User{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestUserService_EmailChange_NotifiesOldAddressAndAudits(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&model.AuditLog{}))
+
+	mockMailer := new(MockMailer)
+	svc := NewUserService(repository.NewUserRepository(db),
+		WithPasswordCost(bcrypt.MinCost),
+		WithTransactor(NewTransactor(db)),
+		WithAuditLog(repository.NewAuditRepository(db)),
+		WithUserTokens(nil, mockMailer),
+	)
+	created, err := svc.Create(context.Background(), &CreateUserInput{Name: "John Doe", Email: "john@example.com", Password: "Password123!"})
+	require.NoError(t, err)
+
+	ctx := ContextWithActor(context.Background(), uuid.New().String())
+	mockMailer.On("Send", ctx, "john@example.com", "Your email address was changed", mock.MatchedBy(func(body string) bool {
+		return strings.Contains(body, "john@example.com") && strings.Contains(body, "johnny@example.com")
+	})).Return(nil).Once()
+
+	_, err = svc.Update(ctx, created.ID, &UpdateUserInput{Name: "John Doe", Email: "Johnny@example.com"})
+	require.NoError(t, err)
+
+	var entries []model.AuditLog
+	require.NoError(t, db.Where("action = ?", model.AuditActionUserChangeEmail).Find(&entries).Error)
+	require.Len(t, entries, 1)
+	assert.Equal(t, created.ID, entries[0].TargetID)
+	assert.Equal(t, map[string]interface{}{
+		"old_email": "john@example.com",
+		"new_email": "johnny@example.com",
+	}, entries[0].Metadata)
+
+	// saving the same email again is not a change
+	name := "Johnny"
+	_, err = svc.Patch(ctx, created.ID, &PatchUserInput{Name: &name})
+	require.NoError(t, err)
+	mockMailer.AssertExpectations(t)
+}
\ No newline at end of file
@@ -2,40 +2,101 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/ariam/my-api/internal/model"
 	"github.com/ariam/my-api/internal/repository"
 	"github.com/ariam/my-api/pkg/jwt"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSessionLimitReached = errors.New("maximum active sessions reached")
+	ErrEmailNotVerified    = errors.New("email address has not been verified")
+	ErrPendingApproval     = errors.New("account is pending approval")
+	ErrAccountRejected     = errors.New("account registration was rejected")
 )
 
 type LoginInput struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Email     string `json:"email" validate:"required,email"`
+	Password  string `json:"password" validate:"required"`
+	Remember  bool   `json:"remember"`
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
 type AuthResponse struct {
-	Token string        `json:"token"`
-	User  *UserResponse `json:"user"`
+	Token           string        `json:"token,omitempty"`
+	User            *UserResponse `json:"user"`
+	SessionID       string        `json:"session_id,omitempty"`
+	RevokedSessions []string      `json:"revoked_sessions,omitempty"`
+}
+
+// SessionPolicy caps concurrent sessions per user. A limit of zero means
+// unlimited; RoleLimits overrides MaxActive for specific roles.
+type SessionPolicy struct {
+	MaxActive     int
+	RoleLimits    map[string]int
+	RejectOnLimit bool
+}
+
+func (p SessionPolicy) LimitFor(role string) int {
+	if limit, ok := p.RoleLimits[role]; ok {
+		return limit
+	}
+	return p.MaxActive
 }
 
 type AuthService interface {
 	Login(ctx context.Context, input *LoginInput) (*AuthResponse, error)
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+	StartSession(ctx context.Context, userID, ip, userAgent string) (*AuthResponse, error)
+	ListSessions(ctx context.Context, userID string) ([]SessionResponse, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
 }
 
 type authService struct {
-	userRepo   repository.UserRepository
-	jwtManager *jwt.JWTManager
+	userRepo      repository.UserRepository
+	sessionRepo   repository.SessionRepository
+	sessionPolicy SessionPolicy
+	rememberTTL   time.Duration
+	jwtManager    *jwt.JWTManager
+}
+
+type AuthServiceOption func(*authService)
+
+// WithSessions enables server-side session tracking; tokens are then tied to
+// a stored session that can be revoked.
+func WithSessions(sessionRepo repository.SessionRepository, policy SessionPolicy) AuthServiceOption {
+	return func(s *authService) {
+		s.sessionRepo = sessionRepo
+		s.sessionPolicy = policy
+	}
+}
+
+// WithRememberMe sets the token lifetime for logins with Remember set. Without
+// it, or with a ttl of zero, they get the default lifetime.
+func WithRememberMe(ttl time.Duration) AuthServiceOption {
+	return func(s *authService) {
+		s.rememberTTL = ttl
+	}
 }
 
-func NewAuthService(userRepo repository.UserRepository, jwtManager *jwt.JWTManager) AuthService {
-	return &authService{
+func NewAuthService(userRepo repository.UserRepository, jwtManager *jwt.JWTManager, opts ...AuthServiceOption) AuthService {
+	s := &authService{
 		userRepo:   userRepo,
 		jwtManager: jwtManager,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *authService) Login(ctx context.Context, input *LoginInput) (*AuthResponse, error) {
-	user, err := s.userRepo.FindByEmail(ctx, input.Email)
+	user, err := s.userRepo.FindByEmail(ctx, normalizeEmail(input.Email))
 	if err != nil {
 		return nil, ErrInvalidCredentials
 	}
@@ -45,16 +106,135 @@ func (s *authService) Login(ctx context.Context, input *LoginInput) (*AuthRespon
 	}
 
 	if !user.IsActive {
+		// only reveal the verification state once the password has been checked
+		if user.EmailVerifiedAt == nil {
+			return nil, ErrEmailNotVerified
+		}
 		return nil, ErrInvalidCredentials
 	}
 
-	token, err := s.jwtManager.Generate(user.ID.String(), user.Email, user.Role)
+	switch user.ApprovalStatus {
+	case model.ApprovalPending:
+		return nil, ErrPendingApproval
+	case model.ApprovalRejected:
+		return nil, ErrAccountRejected
+	}
+
+	ttl := s.jwtManager.TTL()
+	if input.Remember && s.rememberTTL > 0 {
+		ttl = s.rememberTTL
+	}
+
+	return s.issueToken(ctx, user, ttl, input.IP, input.UserAgent)
+}
+
+func (s *authService) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	if s.sessionRepo == nil {
+		return true, nil
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return session.RevokedAt == nil && session.ExpiresAt.After(time.Now()), nil
+}
+
+// StartSession signs a token for a user who has already been authenticated by
+// other means, such as having just registered.
+func (s *authService) StartSession(ctx context.Context, userID, ip, userAgent string) (*AuthResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return s.issueToken(ctx, user, s.jwtManager.TTL(), ip, userAgent)
+}
+
+// issueToken enforces the session cap, records a new session and signs a
+// token bound to it, both expiring after ttl.
+func (s *authService) issueToken(ctx context.Context, user *model.User, ttl time.Duration, ip, userAgent string) (*AuthResponse, error) {
+	if s.sessionRepo == nil {
+		token, err := s.jwtManager.GenerateWithTTL(user.ID.String(), user.Email, user.Role, ttl, tokenClaims(user))
+		if err != nil {
+			return nil, err
+		}
+		return &AuthResponse{Token: token, User: toUserResponse(user)}, nil
+	}
+
+	revoked, err := s.enforceSessionLimit(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &model.Session{
+		Base:      model.Base{ID: model.NewID()},
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(ttl),
+		IP:        ip,
+		UserAgent: userAgent,
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	token, err := s.jwtManager.GenerateWithIDAndTTL(session.ID.String(), user.ID.String(), user.Email, user.Role, ttl, tokenClaims(user))
 	if err != nil {
 		return nil, err
 	}
 
 	return &AuthResponse{
-		Token: token,
-		User:  toUserResponse(user),
+		Token:           token,
+		User:            toUserResponse(user),
+		SessionID:       session.ID.String(),
+		RevokedSessions: revoked,
 	}, nil
+}
+
+// tokenClaims are the extra claims signed into user's tokens: the tenant the
+// Auth middleware scopes their requests to.
+func tokenClaims(user *model.User) map[string]interface{} {
+	if user.TenantID == "" {
+		return nil
+	}
+	return map[string]interface{}{"tenant_id": user.TenantID}
+}
+
+// enforceSessionLimit makes room for one more session, either by revoking the
+// oldest ones or by rejecting the login, and returns the revoked session IDs.
+func (s *authService) enforceSessionLimit(ctx context.Context, user *model.User) ([]string, error) {
+	limit := s.sessionPolicy.LimitFor(user.Role)
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	active, err := s.sessionRepo.FindActiveByUser(ctx, user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(active) < limit {
+		return nil, nil
+	}
+
+	if s.sessionPolicy.RejectOnLimit {
+		return nil, ErrSessionLimitReached
+	}
+
+	excess := active[:len(active)-limit+1]
+	ids := make([]string, len(excess))
+	for i, session := range excess {
+		ids[i] = session.ID.String()
+	}
+	if err := s.sessionRepo.Revoke(ctx, ids...); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
 }
\ No newline at end of file
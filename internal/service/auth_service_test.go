@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/pkg/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Create(ctx context.Context, session *model.Session) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) FindByID(ctx context.Context, id string) (*model.Session, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) FindActiveByUser(ctx context.Context, userID string) ([]model.Session, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]model.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) FindByUser(ctx context.Context, userID string) ([]model.Session, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]model.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) Revoke(ctx context.Context, ids ...string) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+func newLoginUser(t *testing.T, role string) *model.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	return &model.User{
+		Base:     model.Base{ID: uuid.New()},
+		Email:    "john@example.com",
+		Password: string(hash),
+		Role:     role,
+		IsActive: true,
+	}
+}
+
+func activeSessions(userID uuid.UUID, n int) []model.Session {
+	sessions := make([]model.Session, n)
+	for i := range sessions {
+		sessions[i] = model.Session{
+			Base:      model.Base{ID: uuid.New(), CreatedAt: time.Now().Add(time.Duration(i-n) * time.Hour)},
+			UserID:    userID,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+	return sessions
+}
+
+func TestAuthService_Login_EvictsOldestSessionAtLimit(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessions := new(MockSessionRepository)
+	jwtManager := jwt.NewJWTManager("test-secret-key-min-32-characters", 24)
+	service := NewAuthService(mockRepo, jwtManager, WithSessions(mockSessions, SessionPolicy{MaxActive: 3}))
+	ctx := context.Background()
+
+	user := newLoginUser(t, "user")
+	existing := activeSessions(user.ID, 3)
+
+	mockRepo.On("FindByEmail", ctx, user.Email).Return(user, nil)
+	mockSessions.On("FindActiveByUser", ctx, user.ID.String()).Return(existing, nil)
+	mockSessions.On("Revoke", ctx, []string{existing[0].ID.String()}).Return(nil)
+	mockSessions.On("Create", ctx, mock.AnythingOfType("*model.Session")).Return(nil)
+
+	result, err := service.Login(ctx, &LoginInput{Email: user.Email, Password: "password123"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{existing[0].ID.String()}, result.RevokedSessions)
+	assert.NotEmpty(t, result.SessionID)
+
+	claims, err := jwtManager.Validate(result.Token)
+	require.NoError(t, err)
+	assert.Equal(t, result.SessionID, claims.ID)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestAuthService_Login_RejectsAtLimit(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessions := new(MockSessionRepository)
+	jwtManager := jwt.NewJWTManager("test-secret-key-min-32-characters", 24)
+	service := NewAuthService(mockRepo, jwtManager, WithSessions(mockSessions, SessionPolicy{MaxActive: 3, RejectOnLimit: true}))
+	ctx := context.Background()
+
+	user := newLoginUser(t, "user")
+
+	mockRepo.On("FindByEmail", ctx, user.Email).Return(user, nil)
+	mockSessions.On("FindActiveByUser", ctx, user.ID.String()).Return(activeSessions(user.ID, 3), nil)
+
+	result, err := service.Login(ctx, &LoginInput{Email: user.Email, Password: "password123"})
+
+	assert.ErrorIs(t, err, ErrSessionLimitReached)
+	assert.Nil(t, result)
+	mockSessions.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAuthService_Login_RoleSpecificLimit(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessions := new(MockSessionRepository)
+	jwtManager := jwt.NewJWTManager("test-secret-key-min-32-characters", 24)
+	policy := SessionPolicy{MaxActive: 3, RoleLimits: map[string]int{"admin": 1}}
+	service := NewAuthService(mockRepo, jwtManager, WithSessions(mockSessions, policy))
+	ctx := context.Background()
+
+	user := newLoginUser(t, "admin")
+	existing := activeSessions(user.ID, 1)
+
+	mockRepo.On("FindByEmail", ctx, user.Email).Return(user, nil)
+	mockSessions.On("FindActiveByUser", ctx, user.ID.String()).Return(existing, nil)
+	mockSessions.On("Revoke", ctx, []string{existing[0].ID.String()}).Return(nil)
+	mockSessions.On("Create", ctx, mock.AnythingOfType("*model.Session")).Return(nil)
+
+	result, err := service.Login(ctx, &LoginInput{Email: user.Email, Password: "password123"})
+
+	require.NoError(t, err)
+	assert.Len(t, result.RevokedSessions, 1)
+}
+
+func TestAuthService_IsSessionActive(t *testing.T) {
+	mockSessions := new(MockSessionRepository)
+	service := NewAuthService(new(MockUserRepository), nil, WithSessions(mockSessions, SessionPolicy{}))
+	ctx := context.Background()
+
+	revokedAt := time.Now()
+	mockSessions.On("FindByID", ctx, "active").Return(&model.Session{ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	mockSessions.On("FindByID", ctx, "revoked").Return(&model.Session{ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}, nil)
+
+	active, err := service.IsSessionActive(ctx, "active")
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	active, err = service.IsSessionActive(ctx, "revoked")
+	assert.NoError(t, err)
+	assert.False(t, active)
+}
+
+func TestAuthService_Login_UnverifiedEmail(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewAuthService(mockRepo, jwt.NewJWTManager("test-secret-key-min-32-characters", 24))
+	ctx := context.Background()
+
+	user := newLoginUser(t, "user")
+	user.IsActive = false
+	mockRepo.On("FindByEmail", ctx, user.Email).Return(user, nil)
+
+	_, err := service.Login(ctx, &LoginInput{Email: user.Email, Password: "password123"})
+	assert.ErrorIs(t, err, ErrEmailNotVerified)
+
+	_, err = service.Login(ctx, &LoginInput{Email: user.Email, Password: "wrong-password"})
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestAuthService_Login_ApprovalState(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		expectedErr error
+	}{
+		{name: "pending approval", status: model.ApprovalPending, expectedErr: ErrPendingApproval},
+		{name: "rejected", status: model.ApprovalRejected, expectedErr: ErrAccountRejected},
+		{name: "approved", status: model.ApprovalApproved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			service := NewAuthService(mockRepo, jwt.NewJWTManager("test-secret-key-min-32-characters", 24))
+			ctx := context.Background()
+
+			user := newLoginUser(t, "user")
+			user.ApprovalStatus = tt.status
+			mockRepo.On("FindByEmail", ctx, user.Email).Return(user, nil)
+
+			result, err := service.Login(ctx, &LoginInput{Email: user.Email, Password: "password123"})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, result.Token)
+			}
+		})
+	}
+}
+
+func TestAuthService_StartSession(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessions := new(MockSessionRepository)
+	jwtManager := jwt.NewJWTManager("test-secret-key-min-32-characters", 24)
+	service := NewAuthService(mockRepo, jwtManager, WithSessions(mockSessions, SessionPolicy{}))
+	ctx := context.Background()
+
+	user := newLoginUser(t, "user")
+
+	mockRepo.On("FindByID", ctx, user.ID.String()).Return(user, nil)
+	mockSessions.On("FindActiveByUser", ctx, user.ID.String()).Return([]model.Session{}, nil).Maybe()
+	mockSessions.On("Create", ctx, mock.AnythingOfType("*model.Session")).Return(nil)
+
+	result, err := service.StartSession(ctx, user.ID.String(), "127.0.0.1", "test-agent")
+
+	require.NoError(t, err)
+	claims, err := jwtManager.Validate(result.Token)
+	require.NoError(t, err)
+	assert.Equal(t, result.SessionID, claims.ID)
+	assert.Equal(t, user.ID.String(), result.User.ID)
+
+	mockRepo.On("FindByID", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+	_, err = service.StartSession(ctx, "missing", "", "")
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestAuthService_Login_Remember(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessions := new(MockSessionRepository)
+	jwtManager := jwt.NewJWTManager("test-secret-key-min-32-characters", 24)
+	service := NewAuthService(mockRepo, jwtManager,
+		WithSessions(mockSessions, SessionPolicy{}),
+		WithRememberMe(30*24*time.Hour),
+	)
+	ctx := context.Background()
+
+	user := newLoginUser(t, "user")
+	var sessions []*model.Session
+
+	mockRepo.On("FindByEmail", ctx, user.Email).Return(user, nil)
+	mockSessions.On("Create", ctx, mock.AnythingOfType("*model.Session")).
+		Run(func(args mock.Arguments) { sessions = append(sessions, args.Get(1).(*model.Session)) }).
+		Return(nil)
+
+	normal, err := service.Login(ctx, &LoginInput{Email: user.Email, Password: "password123"})
+	require.NoError(t, err)
+	remembered, err := service.Login(ctx, &LoginInput{Email: user.Email, Password: "password123", Remember: true})
+	require.NoError(t, err)
+
+	normalClaims, err := jwtManager.Validate(normal.Token)
+	require.NoError(t, err)
+	rememberedClaims, err := jwtManager.Validate(remembered.Token)
+	require.NoError(t, err)
+
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), normalClaims.ExpiresAt.Time, time.Minute)
+	assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), rememberedClaims.ExpiresAt.Time, time.Minute)
+	assert.True(t, rememberedClaims.ExpiresAt.After(normalClaims.ExpiresAt.Time))
+
+	// each session lasts exactly as long as its token
+	require.Len(t, sessions, 2)
+	assert.WithinDuration(t, normalClaims.ExpiresAt.Time, sessions[0].ExpiresAt, time.Second)
+	assert.WithinDuration(t, rememberedClaims.ExpiresAt.Time, sessions[1].ExpiresAt, time.Second)
+}
\ No newline at end of file
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSessionNotFound  = errors.New("session not found")
+	ErrSessionsDisabled = errors.New("session tracking is not enabled")
+)
+
+// SessionResponse describes one of the caller's active sessions. Current is
+// set by the handler for the session the request was made with.
+type SessionResponse struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	Current   bool      `json:"current"`
+}
+
+// ListSessions returns the user's unrevoked, unexpired sessions, oldest first.
+func (s *authService) ListSessions(ctx context.Context, userID string) ([]SessionResponse, error) {
+	if s.sessionRepo == nil {
+		return nil, ErrSessionsDisabled
+	}
+
+	sessions, err := s.sessionRepo.FindActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SessionResponse, len(sessions))
+	for i, session := range sessions {
+		result[i] = SessionResponse{
+			ID:        session.ID.String(),
+			CreatedAt: session.CreatedAt,
+			ExpiresAt: session.ExpiresAt,
+			IP:        session.IP,
+			UserAgent: session.UserAgent,
+		}
+	}
+	return result, nil
+}
+
+// RevokeSession ends one of the user's sessions. Sessions belonging to other
+// users are reported as not found so their IDs can't be probed.
+func (s *authService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	if s.sessionRepo == nil {
+		return ErrSessionsDisabled
+	}
+
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return ErrSessionNotFound
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	if session.UserID.String() != userID || session.RevokedAt != nil {
+		return ErrSessionNotFound
+	}
+
+	return s.sessionRepo.Revoke(ctx, sessionID)
+}
\ No newline at end of file
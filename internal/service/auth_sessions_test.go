@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/internal/repository"
+	"github.com/ariam/my-api/pkg/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthService_Sessions(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&model.Session{}))
+	ctx := context.Background()
+
+	userRepo := repository.NewUserRepository(db)
+	user := newLoginUser(t, "user")
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	service := NewAuthService(userRepo, jwt.NewJWTManager("test-secret-key-min-32-characters", 24),
+		WithSessions(repository.NewSessionRepository(db), SessionPolicy{MaxActive: 2}))
+	login := &LoginInput{Email: user.Email, Password: "password123"}
+
+	var sessionIDs []string
+	for i := 0; i < 3; i++ {
+		result, err := service.Login(ctx, login)
+		require.NoError(t, err)
+		sessionIDs = append(sessionIDs, result.SessionID)
+	}
+
+	// the third login evicted the first session
+	active, err := service.IsSessionActive(ctx, sessionIDs[0])
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	sessions, err := service.ListSessions(ctx, user.ID.String())
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, sessionIDs[1], sessions[0].ID)
+	assert.Equal(t, sessionIDs[2], sessions[1].ID)
+
+	assert.ErrorIs(t, service.RevokeSession(ctx, uuid.NewString(), sessionIDs[1]), ErrSessionNotFound)
+	assert.ErrorIs(t, service.RevokeSession(ctx, user.ID.String(), sessionIDs[0]), ErrSessionNotFound)
+	assert.ErrorIs(t, service.RevokeSession(ctx, user.ID.String(), "not-a-uuid"), ErrSessionNotFound)
+
+	require.NoError(t, service.RevokeSession(ctx, user.ID.String(), sessionIDs[1]))
+	active, err = service.IsSessionActive(ctx, sessionIDs[1])
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	sessions, err = service.ListSessions(ctx, user.ID.String())
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, sessionIDs[2], sessions[0].ID)
+}
+
+func TestAuthService_Sessions_Disabled(t *testing.T) {
+	service := NewAuthService(new(MockUserRepository), nil)
+	ctx := context.Background()
+
+	_, err := service.ListSessions(ctx, uuid.NewString())
+	assert.ErrorIs(t, err, ErrSessionsDisabled)
+	assert.ErrorIs(t, service.RevokeSession(ctx, uuid.NewString(), uuid.NewString()), ErrSessionsDisabled)
+}
\ No newline at end of file
@@ -0,0 +1,51 @@
+package service
+
+// Page-based listings accept a page number starting at 1 and a page size
+// between MinPerPage and the deployment's maximum. DefaultPerPage and
+// MaxPerPage are the bounds used when none are configured.
+const (
+	MinPerPage     = 1
+	DefaultPerPage = 10
+	MaxPerPage     = 100
+)
+
+// PageLimits are a deployment's page size bounds. The zero value uses
+// DefaultPerPage and MaxPerPage.
+type PageLimits struct {
+	DefaultPerPage int
+	MaxPerPage     int
+}
+
+// Normalize returns page and perPage made safe to pass to a repository: a
+// page below 1 becomes 1, a missing or invalid page size becomes the default
+// and one above the maximum is clamped to it.
+func (l PageLimits) Normalize(page, perPage int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	return page, l.NormalizePerPage(perPage)
+}
+
+// NormalizePerPage is the page size half of Normalize.
+func (l PageLimits) NormalizePerPage(perPage int) int {
+	l = l.WithDefaults()
+	switch {
+	case perPage < MinPerPage:
+		return l.DefaultPerPage
+	case perPage > l.MaxPerPage:
+		return l.MaxPerPage
+	}
+	return perPage
+}
+
+// WithDefaults returns l with unset bounds replaced by DefaultPerPage and
+// MaxPerPage.
+func (l PageLimits) WithDefaults() PageLimits {
+	if l.DefaultPerPage < MinPerPage {
+		l.DefaultPerPage = DefaultPerPage
+	}
+	if l.MaxPerPage < MinPerPage {
+		l.MaxPerPage = MaxPerPage
+	}
+	return l
+}
\ No newline at end of file
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ariam/my-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Transactor runs fn inside a database transaction. Repository calls made
+// with the ctx passed to fn join the transaction, which is committed when fn
+// returns nil and rolled back otherwise. Nested calls use savepoints.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type gormTransactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &gormTransactor{db: db}
+}
+
+func (t *gormTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return repository.Conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fn(repository.WithTx(ctx, tx))
+	})
+}
+
+// noopTransactor runs fn directly; it is the default for services built
+// without a database, such as in unit tests with mocked repositories.
+type noopTransactor struct{}
+
+func (noopTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
\ No newline at end of file
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/internal/repository"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	require.NoError(t, db.AutoMigrate(&model.User{}, &model.UserToken{}))
+	return db
+}
+
+func TestTransactor_RollsBackOnError(t *testing.T) {
+	db := setupTestDB(t)
+	users := repository.NewUserRepository(db)
+	tokens := repository.NewUserTokenRepository(db)
+	tx := NewTransactor(db)
+	ctx := context.Background()
+
+	failure := errors.New("audit log unavailable")
+	user := &model.User{Name: "John", Email: "john@example.com", Password: "x", IsActive: true}
+
+	err := tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := users.Create(ctx, user); err != nil {
+			return err
+		}
+		if err := tokens.Create(ctx, &model.UserToken{UserID: user.ID, Purpose: model.TokenPurposeEmailVerification, TokenHash: "hash"}); err != nil {
+			return err
+		}
+		return failure
+	})
+
+	assert.ErrorIs(t, err, failure)
+	_, err = users.FindByEmail(ctx, "john@example.com")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "user insert should be rolled back")
+	_, err = tokens.FindByHash(ctx, model.TokenPurposeEmailVerification, "hash")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "token insert should be rolled back")
+}
+
+func TestTransactor_CommitsOnSuccess(t *testing.T) {
+	db := setupTestDB(t)
+	users := repository.NewUserRepository(db)
+	tx := NewTransactor(db)
+	ctx := context.Background()
+
+	err := tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		return users.Create(ctx, &model.User{Name: "John", Email: "john@example.com", Password: "x", IsActive: true})
+	})
+
+	assert.NoError(t, err)
+	_, err = users.FindByEmail(ctx, "john@example.com")
+	assert.NoError(t, err)
+}
\ No newline at end of file
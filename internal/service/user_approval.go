@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/internal/repository"
+	"github.com/ariam/my-api/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var ErrNotPendingApproval = errors.New("user is not pending approval")
+
+// WithApproval holds self-registered accounts in the pending_approval state
+// until an admin approves them.
+func WithApproval() UserServiceOption {
+	return func(s *userService) {
+		s.requireApproval = true
+	}
+}
+
+func (s *userService) ApproveUser(ctx context.Context, id string) (*UserResponse, error) {
+	return s.decideApproval(ctx, id, model.ApprovalApproved)
+}
+
+func (s *userService) RejectUser(ctx context.Context, id string) (*UserResponse, error) {
+	return s.decideApproval(ctx, id, model.ApprovalRejected)
+}
+
+func (s *userService) decideApproval(ctx context.Context, id, status string) (*UserResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if user.ApprovalStatus != model.ApprovalPending {
+		return nil, ErrNotPendingApproval
+	}
+
+	user.ApprovalStatus = status
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrStaleVersion) {
+			// another admin decided first
+			return nil, ErrVersionConflict
+		}
+		return nil, err
+	}
+	s.userUpdated(ctx, user)
+
+	subject, body := "Your account has been approved",
+		fmt.Sprintf("Hi %s,\n\nYour account has been approved. You can now sign in.\n", user.Name)
+	if status == model.ApprovalRejected {
+		subject, body = "Your account request was declined",
+			fmt.Sprintf("Hi %s,\n\nYour account request has been declined.\n", user.Name)
+	}
+	if err := s.mailer.Send(ctx, user.Email, subject, body); err != nil {
+		logger.WithContext(ctx).Error("Failed to send approval notification", zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
+
+	return toUserResponse(user), nil
+}
\ No newline at end of file
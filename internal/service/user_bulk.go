@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/ariam/my-api/pkg/validator"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultBulkBatchSize = 100
+	defaultBulkMaxItems  = 10000
+)
+
+var (
+	ErrInvalidBulkPayload = errors.New("invalid bulk import payload")
+	ErrBulkImportTooLarge = errors.New("bulk import exceeds maximum items")
+)
+
+type BulkCreateFailure struct {
+	Index int    `json:"index"`
+	Email string `json:"email,omitempty"`
+	Error string `json:"error"`
+}
+
+type BulkCreateResult struct {
+	Processed int                 `json:"processed"`
+	Created   int                 `json:"created"`
+	Failed    []BulkCreateFailure `json:"failed,omitempty"`
+}
+
+// BulkCreate decodes a JSON array of CreateUserInput one element at a time and
+// inserts valid users in batches, so memory stays bounded by the batch size.
+// Malformed or oversized streams stop the import; everything decoded before
+// that point is still flushed and reported in the returned result.
+func (s *userService) BulkCreate(ctx context.Context, r io.Reader) (*BulkCreateResult, error) {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil || tok != json.Delim('[') {
+		return nil, fmt.Errorf("%w: expected a JSON array", ErrInvalidBulkPayload)
+	}
+
+	result := &BulkCreateResult{}
+	batch := make([]model.User, 0, s.bulkBatchSize)
+	indexes := make([]int, 0, s.bulkBatchSize) // item index of each batch row
+	seen := make(map[string]struct{})
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		created := batch
+		err := s.userRepo.CreateBatch(ctx, batch)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			created, err = s.createOneByOne(ctx, batch, indexes, result)
+		}
+		for i := range created {
+			s.publish(UserCreated{User: toUserResponse(&created[i])})
+		}
+		result.Created += len(created)
+		if err != nil {
+			return err
+		}
+		batch = make([]model.User, 0, s.bulkBatchSize)
+		indexes = make([]int, 0, s.bulkBatchSize)
+
+		logger.WithContext(ctx).Info("Bulk import progress",
+			zap.Int("processed", result.Processed),
+			zap.Int("created", result.Created),
+			zap.Int("failed", len(result.Failed)),
+		)
+		return nil
+	}
+
+	for dec.More() {
+		if s.bulkMaxItems > 0 && result.Processed >= s.bulkMaxItems {
+			if err := flush(); err != nil {
+				return result, err
+			}
+			return result, fmt.Errorf("%w: limit is %d", ErrBulkImportTooLarge, s.bulkMaxItems)
+		}
+
+		index := result.Processed
+		var input CreateUserInput
+		if err := dec.Decode(&input); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				if flushErr := flush(); flushErr != nil {
+					return result, flushErr
+				}
+				return result, fmt.Errorf("%w: item %d: %v", ErrInvalidBulkPayload, index, err)
+			}
+			result.Processed++
+			result.Failed = append(result.Failed, BulkCreateFailure{Index: index, Error: "field " + typeErr.Field + " has the wrong type"})
+			continue
+		}
+		result.Processed++
+
+		if errs := validator.Validate(&input); len(errs) > 0 {
+			messages := make([]string, len(errs))
+			for i, e := range errs {
+				messages[i] = e.Message
+			}
+			result.Failed = append(result.Failed, BulkCreateFailure{Index: index, Email: input.Email, Error: strings.Join(messages, "; ")})
+			continue
+		}
+		input.Email = normalizeEmail(input.Email)
+
+		if _, dup := seen[input.Email]; dup {
+			result.Failed = append(result.Failed, BulkCreateFailure{Index: index, Email: input.Email, Error: ErrEmailAlreadyExists.Error()})
+			continue
+		}
+		deleted, err := s.availableEmail(ctx, input.Email)
+		if errors.Is(err, ErrEmailAlreadyExists) || errors.Is(err, ErrEmailDeletedAccount) {
+			result.Failed = append(result.Failed, BulkCreateFailure{Index: index, Email: input.Email, Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			if flushErr := flush(); flushErr != nil {
+				return result, flushErr
+			}
+			return result, err
+		}
+		seen[input.Email] = struct{}{}
+
+		if deleted != nil {
+			// restoring goes through Create, one account at a time
+			if _, err := s.create(ctx, &input, false); err != nil {
+				if !errors.Is(err, ErrEmailAlreadyExists) {
+					return result, err
+				}
+				result.Failed = append(result.Failed, BulkCreateFailure{Index: index, Email: input.Email, Error: err.Error()})
+				continue
+			}
+			result.Created++
+			continue
+		}
+
+		user, err := s.newUser(&input)
+		if err != nil {
+			return result, err
+		}
+		batch = append(batch, *user)
+		indexes = append(indexes, index)
+
+		if len(batch) >= s.bulkBatchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		if flushErr := flush(); flushErr != nil {
+			return result, flushErr
+		}
+		return result, fmt.Errorf("%w: %v", ErrInvalidBulkPayload, err)
+	}
+
+	if err := flush(); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// createOneByOne inserts users individually after their batch insert hit the
+// unique email index, reporting the rows that conflict as item failures. The
+// lookups before the batch only see the caller's tenant, but emails are unique
+// across all of them. It returns the users it created.
+func (s *userService) createOneByOne(ctx context.Context, users []model.User, indexes []int, result *BulkCreateResult) ([]model.User, error) {
+	created := make([]model.User, 0, len(users))
+	for i := range users {
+		err := s.userRepo.Create(ctx, &users[i])
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			result.Failed = append(result.Failed, BulkCreateFailure{Index: indexes[i], Email: users[i].Email, Error: ErrEmailAlreadyExists.Error()})
+			continue
+		}
+		if err != nil {
+			return created, err
+		}
+		created = append(created, users[i])
+	}
+	return created, nil
+}
\ No newline at end of file
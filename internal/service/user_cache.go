@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ariam/my-api/internal/tenant"
+	"github.com/ariam/my-api/pkg/cache"
+	"github.com/ariam/my-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// WithCache serves FindByID from c for up to ttl. Writes through the service
+// evict the user's entry; writes made elsewhere are visible once it expires.
+func WithCache(c cache.Cache, ttl time.Duration) UserServiceOption {
+	return func(s *userService) {
+		s.cache = c
+		s.cacheTTL = ttl
+	}
+}
+
+func userCacheKey(id string) string {
+	return "user:" + id
+}
+
+// cachedUser returns the cached response for id, or nil on a miss. Cache
+// failures are logged and treated as misses so they never fail a read.
+func (s *userService) cachedUser(ctx context.Context, id string) *UserResponse {
+	if s.cache == nil {
+		return nil
+	}
+
+	data, err := s.cache.Get(ctx, userCacheKey(id))
+	if err != nil {
+		logger.WithContext(ctx).Warn("User cache read failed", zap.String("user_id", id), zap.Error(err))
+		return nil
+	}
+	if data == nil {
+		return nil
+	}
+
+	var user UserResponse
+	if err := json.Unmarshal(data, &user); err != nil {
+		return nil
+	}
+	// entries are shared across tenants; the repository enforces the scope
+	// on a miss, so a user from another tenant must be one too
+	if tenantID, ok := tenant.FromContext(ctx); ok && user.TenantID != tenantID {
+		return nil
+	}
+	return &user
+}
+
+func (s *userService) cacheUser(ctx context.Context, user *UserResponse) {
+	if s.cache == nil {
+		return
+	}
+
+	data, err := json.Marshal(user)
+	if err != nil {
+		return
+	}
+	if err := s.cache.Set(ctx, userCacheKey(user.ID), data, s.cacheTTL); err != nil {
+		logger.WithContext(ctx).Warn("User cache write failed", zap.String("user_id", user.ID), zap.Error(err))
+	}
+}
+
+func (s *userService) evictUser(ctx context.Context, id string) {
+	if s.cache == nil {
+		return
+	}
+
+	if err := s.cache.Delete(ctx, userCacheKey(id)); err != nil {
+		logger.WithContext(ctx).Error("User cache eviction failed", zap.String("user_id", id), zap.Error(err))
+	}
+}
\ No newline at end of file
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/pkg/cache"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func TestUserService_FindByID_ServedFromCache(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, WithCache(cache.NewLRU(10), time.Minute))
+	ctx := context.Background()
+
+	user := &model.User{Base: model.Base{ID: uuid.New()}, Name: "John", Email: "john@example.com", Role: "user"}
+	mockRepo.On("FindByID", ctx, user.ID.String()).Return(user, nil).Once()
+
+	first, err := service.FindByID(ctx, user.ID.String())
+	require.NoError(t, err)
+	second, err := service.FindByID(ctx, user.ID.String())
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	mockRepo.AssertNumberOfCalls(t, "FindByID", 1)
+}
+
+func TestUserService_Update_EvictsCachedUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, WithCache(cache.NewLRU(10), time.Minute))
+	ctx := context.Background()
+
+	user := &model.User{Base: model.Base{ID: uuid.New()}, Name: "John", Email: "john@example.com", Role: "user"}
+	mockRepo.On("FindByID", ctx, user.ID.String()).Return(user, nil)
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*model.User")).Return(nil)
+
+	_, err := service.FindByID(ctx, user.ID.String())
+	require.NoError(t, err)
+
+	_, err = service.Update(ctx, user.ID.String(), &UpdateUserInput{Name: "Jane", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	result, err := service.FindByID(ctx, user.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "Jane", result.Name)
+	mockRepo.AssertNumberOfCalls(t, "FindByID", 3)
+}
+
+func TestUserService_Delete_EvictsCachedUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, WithCache(cache.NewLRU(10), time.Minute))
+	ctx := context.Background()
+
+	user := &model.User{Base: model.Base{ID: uuid.New()}, Name: "John", Email: "john@example.com", Role: "user"}
+	mockRepo.On("FindByID", ctx, user.ID.String()).Return(user, nil).Twice()
+	mockRepo.On("Delete", ctx, user.ID.String()).Return(nil)
+	mockRepo.On("FindByID", ctx, user.ID.String()).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.FindByID(ctx, user.ID.String())
+	require.NoError(t, err)
+	require.NoError(t, service.Delete(ctx, user.ID.String()))
+
+	_, err = service.FindByID(ctx, user.ID.String())
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestUserService_ResetPassword_EvictsCachedUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokens := new(MockUserTokenRepository)
+	service := NewUserService(mockRepo,
+		WithPasswordCost(bcrypt.MinCost),
+		WithUserTokens(mockTokens, new(MockMailer)),
+		WithCache(cache.NewLRU(10), time.Minute),
+	)
+	ctx := context.Background()
+
+	user := &model.User{Base: model.Base{ID: uuid.New()}, Name: "John", Email: "john@example.com", Role: "user", Version: 1}
+	tokenID := uuid.New()
+	mockTokens.On("FindByHash", ctx, model.TokenPurposePasswordReset, hashToken("secret")).
+		Return(&model.UserToken{Base: model.Base{ID: tokenID}, UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	mockTokens.On("MarkUsed", ctx, tokenID.String()).Return(true, nil)
+	mockRepo.On("FindByID", ctx, user.ID.String()).Return(user, nil)
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*model.User")).Run(func(args mock.Arguments) {
+		args.Get(1).(*model.User).Version++
+	}).Return(nil)
+
+	_, err := service.FindByID(ctx, user.ID.String())
+	require.NoError(t, err)
+
+	require.NoError(t, service.ResetPassword(ctx, "secret", "new-password"))
+
+	result, err := service.FindByID(ctx, user.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), result.Version, "the cached version is not served after the reset")
+	mockRepo.AssertNumberOfCalls(t, "FindByID", 3)
+}
\ No newline at end of file
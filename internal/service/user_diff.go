@@ -0,0 +1,24 @@
+package service
+
+import "github.com/ariam/my-api/internal/model"
+
+// FieldChange is the value of a user field before and after an update.
+type FieldChange struct {
+	Old string `json:"old" example:"John Doe"`
+	New string `json:"new" example:"John Smith"`
+}
+
+// userChanges returns the profile fields that differ between before and
+// after, keyed by their JSON name. Unchanged fields are left out.
+func userChanges(before, after *model.User) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+	diff := func(field, old, new string) {
+		if old != new {
+			changes[field] = FieldChange{Old: old, New: new}
+		}
+	}
+	diff("name", before.Name, after.Name)
+	diff("email", before.Email, after.Email)
+	diff("role", before.Role, after.Role)
+	return changes
+}
\ No newline at end of file
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ariam/my-api/internal/event"
+	"github.com/ariam/my-api/internal/model"
+)
+
+// User lifecycle event types.
+const (
+	EventUserCreated = "user.created"
+	EventUserUpdated = "user.updated"
+	EventUserDeleted = "user.deleted"
+)
+
+type UserCreated struct {
+	User *UserResponse `json:"user"`
+}
+
+func (UserCreated) Type() string { return EventUserCreated }
+
+type UserUpdated struct {
+	User *UserResponse `json:"user"`
+}
+
+func (UserUpdated) Type() string { return EventUserUpdated }
+
+type UserDeleted struct {
+	User *UserResponse `json:"user"`
+}
+
+func (UserDeleted) Type() string { return EventUserDeleted }
+
+// WithEvents publishes a user event on bus after each committed mutation.
+func WithEvents(bus *event.Bus) UserServiceOption {
+	return func(s *userService) {
+		s.events = bus
+	}
+}
+
+func (s *userService) publish(e event.Event) {
+	if s.events == nil {
+		return
+	}
+	s.events.Publish(e)
+}
+
+// userUpdated runs once a change to user has been committed. The cache is
+// evicted here rather than by a subscriber so the next read can't be stale.
+func (s *userService) userUpdated(ctx context.Context, user *model.User) {
+	s.evictUser(ctx, user.ID.String())
+	s.publish(UserUpdated{User: toUserResponse(user)})
+}
\ No newline at end of file
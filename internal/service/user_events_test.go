@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ariam/my-api/internal/event"
+	"github.com/ariam/my-api/internal/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestUserService_PublishesLifecycleEvents(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	bus := event.NewBus()
+	service := NewUserService(mockRepo, WithEvents(bus))
+	ctx := context.Background()
+
+	received := make(chan event.Event, 10)
+	bus.Subscribe(event.All, func(e event.Event) { received <- e })
+
+	mockRepo.On("FindByEmail", ctx, "john@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindDeletedByEmail", ctx, "john@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*model.User")).Return(nil)
+	created, err := service.Create(ctx, &CreateUserInput{Name: "John Doe", Email: "john@example.com", Password: "Password123!"})
+	require.NoError(t, err)
+
+	user := &model.User{Base: model.Base{ID: uuid.New()}, Name: "John Doe", Email: "john@example.com", Role: "user"}
+	mockRepo.On("FindByID", ctx, user.ID.String()).Return(user, nil)
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*model.User")).Return(nil)
+	mockRepo.On("Delete", ctx, user.ID.String()).Return(nil)
+	_, err = service.Update(ctx, user.ID.String(), &UpdateUserInput{Name: "Jane", Email: "john@example.com"})
+	require.NoError(t, err)
+	require.NoError(t, service.Delete(ctx, user.ID.String()))
+
+	next := func() event.Event {
+		select {
+		case e := <-received:
+			return e
+		case <-time.After(time.Second):
+			require.FailNow(t, "no event delivered")
+			return nil
+		}
+	}
+
+	assert.Equal(t, UserCreated{User: created}, next())
+	assert.Equal(t, "Jane", next().(UserUpdated).User.Name)
+	assert.Equal(t, user.ID.String(), next().(UserDeleted).User.ID)
+}
\ No newline at end of file
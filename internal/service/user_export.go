@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ariam/my-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// exportBatchSize is how many users ExportUsers loads per query.
+const exportBatchSize = 500
+
+// PersonalDataExport is the bundle returned for a data-subject access request.
+// It is assembled field by field so secrets such as the password hash can never
+// leak into it through a model change.
+type PersonalDataExport struct {
+	ExportedAt time.Time             `json:"exported_at"`
+	Profile    PersonalDataProfile   `json:"profile"`
+	Sessions   []PersonalDataSession `json:"sessions"`
+}
+
+type PersonalDataProfile struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PersonalDataSession struct {
+	ID        string     `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	IP        string     `json:"ip"`
+	UserAgent string     `json:"user_agent"`
+}
+
+func (s *userService) ExportPersonalData(ctx context.Context, id string) (*PersonalDataExport, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	export := &PersonalDataExport{
+		ExportedAt: time.Now().UTC(),
+		Profile:    toPersonalDataProfile(user),
+		Sessions:   []PersonalDataSession{},
+	}
+
+	if s.sessionRepo != nil {
+		sessions, err := s.sessionRepo.FindByUser(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, session := range sessions {
+			export.Sessions = append(export.Sessions, PersonalDataSession{
+				ID:        session.ID.String(),
+				CreatedAt: session.CreatedAt,
+				ExpiresAt: session.ExpiresAt,
+				RevokedAt: session.RevokedAt,
+				IP:        session.IP,
+				UserAgent: session.UserAgent,
+			})
+		}
+	}
+
+	return export, nil
+}
+
+// ExportUsers passes every user matching filter to fn, a batch at a time, so
+// an export can be streamed without holding the whole table in memory.
+func (s *userService) ExportUsers(ctx context.Context, filter UserFilter, fn func([]UserResponse) error) error {
+	return s.userRepo.FindInBatches(ctx, filter, exportBatchSize, func(users []model.User) error {
+		batch := make([]UserResponse, len(users))
+		for i := range users {
+			batch[i] = *toUserResponse(&users[i])
+		}
+		return fn(batch)
+	})
+}
+
+func toPersonalDataProfile(user *model.User) PersonalDataProfile {
+	return PersonalDataProfile{
+		ID:        user.ID.String(),
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      user.Role,
+		IsActive:  user.IsActive,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
\ No newline at end of file
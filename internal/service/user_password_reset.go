@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/internal/repository"
+	"github.com/ariam/my-api/pkg/logger"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const defaultResetTTL = time.Hour
+
+var ErrResetOwnPassword = errors.New("admins cannot reset their own password; use the password reset flow")
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,strongpassword"`
+}
+
+type AdminSetPasswordInput struct {
+	Password string `json:"password" validate:"required,strongpassword"`
+	// RevokeSessions signs the user out everywhere.
+	RevokeSessions bool `json:"revoke_sessions"`
+}
+
+// WithPasswordReset sets the page the reset link points at; the token is
+// appended as a query parameter. It requires WithUserTokens. A ttl of zero
+// uses the default of one hour.
+func WithPasswordReset(resetURL string, ttl time.Duration) UserServiceOption {
+	return func(s *userService) {
+		s.resetURL = resetURL
+		if ttl > 0 {
+			s.resetTTL = ttl
+		}
+	}
+}
+
+// RequestPasswordReset emails a reset link to the account with this email.
+// Unknown emails and delivery failures are not reported to the caller, so the
+// result cannot be used to discover which emails are registered.
+func (s *userService) RequestPasswordReset(ctx context.Context, email string) error {
+	if s.tokenRepo == nil {
+		return ErrTokensDisabled
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.issueToken(ctx, user.ID, model.TokenPurposePasswordReset, s.resetTTL)
+	if err != nil {
+		return err
+	}
+
+	link := s.resetURL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Hi %s,\n\nA password reset was requested for your account. Open this link within %d minutes to choose a new password:\n\n%s\n\nIf you did not request this, you can ignore this email.\n",
+		user.Name, int(s.resetTTL.Minutes()), link)
+	if err := s.mailer.Send(ctx, user.Email, "Reset your password", body); err != nil {
+		logger.WithContext(ctx).Error("Failed to send password reset email", zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
+	return nil
+}
+
+// ResetPassword redeems a reset token and replaces the user's password. All of
+// the user's sessions are revoked so a stolen session cannot outlive the reset.
+func (s *userService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	// redeeming the token, changing the password and revoking sessions succeed
+	// or fail together, so a failed reset leaves the token usable
+	var user *model.User
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		userToken, err := s.redeemToken(ctx, model.TokenPurposePasswordReset, token)
+		if err != nil {
+			return err
+		}
+
+		user, err = s.userRepo.FindByID(ctx, userToken.UserID.String())
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), s.passwordCost)
+		if err != nil {
+			return err
+		}
+		user.Password = string(hashedPassword)
+
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return s.revokeSessions(ctx, user.ID.String())
+	})
+	if err != nil {
+		return err
+	}
+
+	s.userUpdated(ctx, user)
+	return nil
+}
+
+// AdminSetPassword replaces a user's password on an admin's behalf, for
+// users locked out without access to their email. The acting admin, taken
+// from ctx, cannot use it on their own account. The change is audited without
+// the password.
+func (s *userService) AdminSetPassword(ctx context.Context, id string, input *AdminSetPasswordInput) error {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if actor := actorFromContext(ctx); actor != nil && *actor == user.ID {
+		return ErrResetOwnPassword
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), s.passwordCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashedPassword)
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		if input.RevokeSessions {
+			if err := s.revokeSessions(ctx, user.ID.String()); err != nil {
+				return err
+			}
+		}
+		return s.audit(ctx, model.AuditActionUserResetPassword, user, map[string]interface{}{"sessions_revoked": input.RevokeSessions})
+	})
+	if errors.Is(err, repository.ErrStaleVersion) {
+		return ErrVersionConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	s.evictUser(ctx, user.ID.String())
+	return nil
+}
+
+// revokeSessions ends all of the user's active sessions. It does nothing when
+// session tracking is not configured.
+func (s *userService) revokeSessions(ctx context.Context, userID string) error {
+	if s.sessionRepo == nil {
+		return nil
+	}
+	sessions, err := s.sessionRepo.FindActiveByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	ids := make([]string, len(sessions))
+	for i, session := range sessions {
+		ids[i] = session.ID.String()
+	}
+	return s.sessionRepo.Revoke(ctx, ids...)
+}
\ No newline at end of file
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ariam/my-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// Policies for creating a user with the email of a soft-deleted one. The
+// deleted row still holds the email's unique index entry, so one of them has
+// to be chosen.
+const (
+	// DeletedEmailReject fails the create with ErrEmailDeletedAccount.
+	DeletedEmailReject = "reject"
+	// DeletedEmailRestore brings the deleted account back under its old ID
+	// with the new name and password, as a regular user.
+	DeletedEmailRestore = "restore"
+)
+
+var ErrEmailDeletedAccount = errors.New("email belongs to a deleted account")
+
+// WithDeletedEmailPolicy sets what Create does with the email of a
+// soft-deleted user: DeletedEmailReject, the default, or DeletedEmailRestore.
+func WithDeletedEmailPolicy(policy string) UserServiceOption {
+	return func(s *userService) {
+		s.deletedEmailPolicy = policy
+	}
+}
+
+// deletedAccount returns the soft-deleted user holding email, or nil if
+// there is none. It returns ErrEmailDeletedAccount if there is one and the
+// policy is to reject it.
+func (s *userService) deletedAccount(ctx context.Context, email string) (*model.User, error) {
+	deleted, err := s.userRepo.FindDeletedByEmail(ctx, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.deletedEmailPolicy != DeletedEmailRestore {
+		return nil, ErrEmailDeletedAccount
+	}
+	return deleted, nil
+}
+
+// restoreAs turns user, built for a new account, into the restoration of
+// deleted: it keeps the deleted account's identity and replaces the rest.
+func restoreAs(user, deleted *model.User) {
+	user.ID = deleted.ID
+	user.CreatedAt = deleted.CreatedAt
+	user.TenantID = deleted.TenantID
+	user.Version = deleted.Version
+}
+
+// restoreAccount undeletes user, prepared with restoreAs, for its new owner.
+// The previous owner's sessions and emailed links are revoked with it, so
+// nothing issued to them still works on the account.
+func (s *userService) restoreAccount(ctx context.Context, user *model.User) error {
+	if err := s.userRepo.Restore(ctx, user); err != nil {
+		return err
+	}
+	if err := s.revokeSessions(ctx, user.ID.String()); err != nil {
+		return err
+	}
+	if s.tokenRepo != nil {
+		return s.tokenRepo.MarkAllUsed(ctx, user.ID.String())
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ariam/my-api/internal/model"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRole = errors.New("invalid role")
+	ErrLastAdmin   = errors.New("cannot demote the last admin")
+)
+
+// Roles lists the roles a user can be assigned. Input structs carrying a role
+// validate it with the matching oneof=user admin tag.
+var Roles = []string{"user", "admin"}
+
+type SetRoleInput struct {
+	Role string `json:"role" validate:"required,oneof=user admin"`
+}
+
+func (s *userService) SetRole(ctx context.Context, id, role string) (*UserResponse, error) {
+	if !validRole(role) {
+		return nil, ErrInvalidRole
+	}
+
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	previous := user.Role
+	user.Role = role
+
+	metadata := map[string]interface{}{"from": previous, "to": role}
+	if err := s.saveAudited(ctx, user, model.AuditActionUserSetRole, metadata, s.keepAnAdmin(previous, user)); err != nil {
+		return nil, err
+	}
+
+	return toUserResponse(user), nil
+}
+
+// keepAnAdmin returns a check for saveAudited that refuses to demote user, a
+// former previousRole, if no other admin who can sign in would remain, so
+// the system can't be locked out of admin endpoints. It runs in the saving
+// transaction and locks the admins it counts, so two concurrent demotions
+// can't each count the other as the admin left.
+func (s *userService) keepAnAdmin(previousRole string, user *model.User) func(context.Context) error {
+	return func(ctx context.Context) error {
+		if previousRole != "admin" || user.Role == "admin" {
+			return nil
+		}
+		admins, err := s.userRepo.LockActiveAdmins(ctx)
+		if err != nil {
+			return err
+		}
+		for _, id := range admins {
+			if id != user.ID.String() {
+				return nil
+			}
+		}
+		return ErrLastAdmin
+	}
+}
+
+func validRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
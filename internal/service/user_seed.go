@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ariam/my-api/internal/model"
+)
+
+// EnsureAdmin creates an active admin from input unless an admin already
+// exists, so it is safe to run on every start. It reports whether it created
+// one. An existing non-admin account with the same email is left alone and
+// reported as ErrEmailAlreadyExists.
+func (s *userService) EnsureAdmin(ctx context.Context, input *CreateUserInput) (bool, error) {
+	admins, err := s.userRepo.CountByRole(ctx, "admin")
+	if err != nil {
+		return false, err
+	}
+	if admins > 0 {
+		return false, nil
+	}
+
+	existing, _ := s.userRepo.FindByEmail(ctx, normalizeEmail(input.Email))
+	if existing != nil {
+		return false, ErrEmailAlreadyExists
+	}
+
+	user, err := s.newUser(input)
+	if err != nil {
+		return false, err
+	}
+	user.Role = "admin"
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		return s.audit(ctx, model.AuditActionUserCreate, user, nil)
+	})
+	if err != nil {
+		return false, err
+	}
+	s.publish(UserCreated{User: toUserResponse(user)})
+
+	return true, nil
+}
\ No newline at end of file
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestUserService_EnsureAdmin(t *testing.T) {
+	ctx := context.Background()
+	input := &CreateUserInput{Name: "Admin", Email: "admin@example.com", Password: "Password123!"}
+
+	t.Run("running twice creates one admin", func(t *testing.T) {
+		db := setupTestDB(t)
+		svc := NewUserService(repository.NewUserRepository(db), WithPasswordCost(bcrypt.MinCost))
+
+		created, err := svc.EnsureAdmin(ctx, input)
+		require.NoError(t, err)
+		assert.True(t, created)
+
+		created, err = svc.EnsureAdmin(ctx, input)
+		require.NoError(t, err)
+		assert.False(t, created)
+
+		var admins []model.User
+		require.NoError(t, db.Where("role = ?", "admin").Find(&admins).Error)
+		require.Len(t, admins, 1)
+		assert.Equal(t, "admin@example.com", admins[0].Email)
+		assert.True(t, admins[0].IsActive)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(admins[0].Password), []byte(input.Password)))
+	})
+
+	t.Run("skips when another admin exists", func(t *testing.T) {
+		db := setupTestDB(t)
+		require.NoError(t, db.Create(&model.User{Name: "Existing", Email: "root@example.com", Password: "x", Role: "admin"}).Error)
+		svc := NewUserService(repository.NewUserRepository(db), WithPasswordCost(bcrypt.MinCost))
+
+		created, err := svc.EnsureAdmin(ctx, input)
+		require.NoError(t, err)
+		assert.False(t, created)
+
+		var count int64
+		require.NoError(t, db.Model(&model.User{}).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("does not promote an existing user", func(t *testing.T) {
+		db := setupTestDB(t)
+		require.NoError(t, db.Create(&model.User{Name: "Admin", Email: "admin@example.com", Password: "x", Role: "user"}).Error)
+		svc := NewUserService(repository.NewUserRepository(db), WithPasswordCost(bcrypt.MinCost))
+
+		created, err := svc.EnsureAdmin(ctx, input)
+		assert.ErrorIs(t, err, ErrEmailAlreadyExists)
+		assert.False(t, created)
+	})
+}
\ No newline at end of file
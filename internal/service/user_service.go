@@ -3,9 +3,18 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
 
+	"github.com/ariam/my-api/internal/event"
 	"github.com/ariam/my-api/internal/model"
 	"github.com/ariam/my-api/internal/repository"
+	"github.com/ariam/my-api/pkg/cache"
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/ariam/my-api/pkg/mailer"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -14,69 +23,253 @@ var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrEmailAlreadyExists = errors.New("email already exists")
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrVersionConflict    = errors.New("user was modified by another request")
 )
 
 type CreateUserInput struct {
 	Name     string `json:"name" validate:"required,min=2,max=100"`
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=8"`
+	Password string `json:"password" validate:"required,strongpassword"`
 }
 
+// UpdateUserInput replaces a user's profile (PUT); every field except
+// Version is required. A Version that is no longer current fails the update
+// with ErrVersionConflict.
 type UpdateUserInput struct {
-	Name string `json:"name" validate:"omitempty,min=2,max=100"`
+	Name    string `json:"name" validate:"required,min=2,max=100"`
+	Email   string `json:"email" validate:"required,email"`
+	Version *uint  `json:"version,omitempty"`
 }
 
+// PatchUserInput partially updates a user (PATCH): nil fields are left
+// unchanged and provided ones are applied as given. Version is checked as for
+// UpdateUserInput.
+type PatchUserInput struct {
+	Name    *string `json:"name" validate:"omitnil,min=2,max=100"`
+	Email   *string `json:"email" validate:"omitnil,email"`
+	Role    *string `json:"role" validate:"omitnil,oneof=user admin"`
+	Version *uint   `json:"version,omitempty"`
+}
+
+type UserFilter = repository.UserFilter
+
+type Sort = repository.Sort
+
 type UserResponse struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Role     string `json:"role"`
-	IsActive bool   `json:"is_active"`
+	ID             string `json:"id"`
+	TenantID       string `json:"tenant_id,omitempty"`
+	Name           string `json:"name"`
+	Email          string `json:"email"`
+	Role           string `json:"role"`
+	IsActive       bool   `json:"is_active"`
+	ApprovalStatus string `json:"approval_status,omitempty"`
+	Version        uint   `json:"version"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+
+	// Changes is set on update responses to the fields the update changed.
+	Changes map[string]FieldChange `json:"changes,omitempty"`
 }
 
 type UserService interface {
 	Create(ctx context.Context, input *CreateUserInput) (*UserResponse, error)
+	Register(ctx context.Context, input *CreateUserInput) (*UserResponse, error)
+	CheckEmailAvailable(ctx context.Context, email string) error
+	EnsureAdmin(ctx context.Context, input *CreateUserInput) (bool, error)
+	BulkCreate(ctx context.Context, r io.Reader) (*BulkCreateResult, error)
 	FindByID(ctx context.Context, id string) (*UserResponse, error)
-	FindAll(ctx context.Context, page, perPage int) ([]UserResponse, int64, error)
+	FindByEmail(ctx context.Context, email string) (*UserResponse, error)
+	FindAll(ctx context.Context, filter UserFilter, sort Sort, page, perPage int) ([]UserResponse, int64, error)
+	FindAfter(ctx context.Context, filter UserFilter, cursor string, limit int) ([]UserResponse, string, error)
+	Search(ctx context.Context, query string, page, perPage int) ([]UserResponse, int64, error)
 	Update(ctx context.Context, id string, input *UpdateUserInput) (*UserResponse, error)
+	Patch(ctx context.Context, id string, input *PatchUserInput) (*UserResponse, error)
+	SetRole(ctx context.Context, id, role string) (*UserResponse, error)
 	Delete(ctx context.Context, id string) error
+	GenerateVerificationToken(ctx context.Context, userID string) (string, error)
+	VerifyEmail(ctx context.Context, token string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	AdminSetPassword(ctx context.Context, id string, input *AdminSetPasswordInput) error
+	ApproveUser(ctx context.Context, id string) (*UserResponse, error)
+	RejectUser(ctx context.Context, id string) (*UserResponse, error)
+	ExportPersonalData(ctx context.Context, id string) (*PersonalDataExport, error)
+	ExportUsers(ctx context.Context, filter UserFilter, fn func([]UserResponse) error) error
 }
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo            repository.UserRepository
+	sessionRepo         repository.SessionRepository
+	tokenRepo           repository.UserTokenRepository
+	mailer              mailer.Mailer
+	tx                  Transactor
+	auditRepo           repository.AuditRepository
+	requireVerification bool
+	requireApproval     bool
+	baseURL             string
+	verificationTTL     time.Duration
+	resetURL            string
+	resetTTL            time.Duration
+	passwordCost        int
+	bulkBatchSize       int
+	bulkMaxItems        int
+	cache               cache.Cache
+	cacheTTL            time.Duration
+	events              *event.Bus
+	deletedEmailPolicy  string
+	pageLimits          PageLimits
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
-	return &userService{userRepo: userRepo}
+type UserServiceOption func(*userService)
+
+func WithPasswordCost(cost int) UserServiceOption {
+	return func(s *userService) {
+		s.passwordCost = cost
+	}
 }
 
-func (s *userService) Create(ctx context.Context, input *CreateUserInput) (*UserResponse, error) {
-	existing, _ := s.userRepo.FindByEmail(ctx, input.Email)
-	if existing != nil {
-		return nil, ErrEmailAlreadyExists
+// WithPageLimits bounds the page size of FindAll and Search.
+func WithPageLimits(limits PageLimits) UserServiceOption {
+	return func(s *userService) {
+		s.pageLimits = limits
 	}
+}
+
+func WithBulkImport(batchSize, maxItems int) UserServiceOption {
+	return func(s *userService) {
+		if batchSize > 0 {
+			s.bulkBatchSize = batchSize
+		}
+		s.bulkMaxItems = maxItems
+	}
+}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+// WithSessionRepository lets the service include a user's sessions in their
+// personal data export.
+func WithSessionRepository(sessionRepo repository.SessionRepository) UserServiceOption {
+	return func(s *userService) {
+		s.sessionRepo = sessionRepo
+	}
+}
+
+// WithTransactor makes multi-step writes, such as a password reset, atomic.
+func WithTransactor(tx Transactor) UserServiceOption {
+	return func(s *userService) {
+		s.tx = tx
+	}
+}
+
+func NewUserService(userRepo repository.UserRepository, opts ...UserServiceOption) UserService {
+	s := &userService{
+		userRepo:        userRepo,
+		mailer:          mailer.NewNoop(),
+		tx:              noopTransactor{},
+		verificationTTL: defaultVerificationTTL,
+		resetTTL:        defaultResetTTL,
+		passwordCost:    bcrypt.DefaultCost,
+		bulkBatchSize:   defaultBulkBatchSize,
+		bulkMaxItems:    defaultBulkMaxItems,
+
+		deletedEmailPolicy: DeletedEmailReject,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Create adds an account on someone's behalf. It is active and approved
+// straight away; signup gating applies only to Register.
+func (s *userService) Create(ctx context.Context, input *CreateUserInput) (*UserResponse, error) {
+	return s.create(ctx, input, false)
+}
+
+// Register adds a self-registered account. With email verification enabled
+// it stays inactive until verified, and with approval required it waits for
+// an admin.
+func (s *userService) Register(ctx context.Context, input *CreateUserInput) (*UserResponse, error) {
+	return s.create(ctx, input, true)
+}
+
+func (s *userService) create(ctx context.Context, input *CreateUserInput, selfRegistered bool) (*UserResponse, error) {
+	deleted, err := s.availableEmail(ctx, normalizeEmail(input.Email))
 	if err != nil {
 		return nil, err
 	}
 
-	user := &model.User{
-		Name:     input.Name,
-		Email:    input.Email,
-		Password: string(hashedPassword),
-		Role:     "user",
-		IsActive: true,
+	user, err := s.newUser(input)
+	if err != nil {
+		return nil, err
+	}
+	verify := selfRegistered && s.verificationEnabled()
+	if verify {
+		user.IsActive = false
+	}
+	if selfRegistered && s.requireApproval {
+		user.ApprovalStatus = model.ApprovalPending
 	}
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if deleted != nil {
+			restoreAs(user, deleted)
+			if err := s.restoreAccount(ctx, user); err != nil {
+				return err
+			}
+			return s.audit(ctx, model.AuditActionUserCreate, user, map[string]interface{}{"restored": true})
+		}
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		return s.audit(ctx, model.AuditActionUserCreate, user, nil)
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// the lookup above only sees this tenant, but emails are unique
+		// across all of them
+		return nil, ErrEmailAlreadyExists
+	}
+	if err != nil {
 		return nil, err
 	}
+	resp := toUserResponse(user)
+	s.publish(UserCreated{User: resp})
 
-	return toUserResponse(user), nil
+	if verify {
+		if err := s.sendVerificationEmail(ctx, user); err != nil {
+			logger.WithContext(ctx).Error("Failed to send verification email", zap.String("user_id", user.ID.String()), zap.Error(err))
+			return resp, ErrVerificationEmailNotSent
+		}
+	}
+
+	return resp, nil
+}
+
+// CheckEmailAvailable returns the error Create would fail with because of
+// email, or nil if Create would accept it.
+func (s *userService) CheckEmailAvailable(ctx context.Context, email string) error {
+	_, err := s.availableEmail(ctx, normalizeEmail(email))
+	return err
+}
+
+// availableEmail checks that no account of the tenant holds email, and
+// returns the soft-deleted account to restore for it under the deleted email
+// policy, if any.
+func (s *userService) availableEmail(ctx context.Context, email string) (*model.User, error) {
+	existing, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrEmailAlreadyExists
+	}
+	return s.deletedAccount(ctx, email)
 }
 
 func (s *userService) FindByID(ctx context.Context, id string) (*UserResponse, error) {
+	if cached := s.cachedUser(ctx, id); cached != nil {
+		return cached, nil
+	}
+
 	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -85,11 +278,58 @@ func (s *userService) FindByID(ctx context.Context, id string) (*UserResponse, e
 		return nil, err
 	}
 
+	resp := toUserResponse(user)
+	s.cacheUser(ctx, resp)
+	return resp, nil
+}
+
+func (s *userService) FindByEmail(ctx context.Context, email string) (*UserResponse, error) {
+	user, err := s.userRepo.FindByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
 	return toUserResponse(user), nil
 }
 
-func (s *userService) FindAll(ctx context.Context, page, perPage int) ([]UserResponse, int64, error) {
-	users, total, err := s.userRepo.FindAll(ctx, page, perPage)
+func (s *userService) FindAll(ctx context.Context, filter UserFilter, sort Sort, page, perPage int) ([]UserResponse, int64, error) {
+	page, perPage = s.pageLimits.Normalize(page, perPage)
+	users, total, err := s.userRepo.FindAll(ctx, filter, sort, page, perPage)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = *toUserResponse(&user)
+	}
+
+	return responses, total, nil
+}
+
+func (s *userService) FindAfter(ctx context.Context, filter UserFilter, cursor string, limit int) ([]UserResponse, string, error) {
+	users, next, err := s.userRepo.FindAfter(ctx, filter, cursor, limit)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, "", ErrInvalidCursor
+		}
+		return nil, "", err
+	}
+
+	responses := make([]UserResponse, len(users))
+	for i, user := range users {
+		responses[i] = *toUserResponse(&user)
+	}
+
+	return responses, next, nil
+}
+
+func (s *userService) Search(ctx context.Context, query string, page, perPage int) ([]UserResponse, int64, error) {
+	page, perPage = s.pageLimits.Normalize(page, perPage)
+	users, total, err := s.userRepo.Search(ctx, query, page, perPage)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -111,19 +351,139 @@ func (s *userService) Update(ctx context.Context, id string, input *UpdateUserIn
 		return nil, err
 	}
 
-	if input.Name != "" {
-		user.Name = input.Name
+	if input.Version != nil && *input.Version != user.Version {
+		return nil, ErrVersionConflict
 	}
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	before := *user
+	if err := s.changeEmail(ctx, user, input.Email); err != nil {
 		return nil, err
 	}
+	user.Name = input.Name
 
-	return toUserResponse(user), nil
+	return s.saveChanges(ctx, &before, user)
+}
+
+func (s *userService) Patch(ctx context.Context, id string, input *PatchUserInput) (*UserResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if input.Version != nil && *input.Version != user.Version {
+		return nil, ErrVersionConflict
+	}
+
+	before := *user
+	if input.Email != nil {
+		if err := s.changeEmail(ctx, user, *input.Email); err != nil {
+			return nil, err
+		}
+	}
+	if input.Name != nil {
+		user.Name = *input.Name
+	}
+	if input.Role != nil {
+		user.Role = *input.Role
+	}
+
+	return s.saveChanges(ctx, &before, user)
+}
+
+// saveChanges saves an update to user and reports the fields that changed
+// since before, both in the audit entry and in the response.
+func (s *userService) saveChanges(ctx context.Context, before, user *model.User) (*UserResponse, error) {
+	changes := userChanges(before, user)
+	steps := []func(context.Context) error{s.keepAnAdmin(before.Role, user)}
+	if _, ok := changes["email"]; ok {
+		// recorded on its own so email changes can be found by action; it shares
+		// the update's transaction
+		steps = append(steps, func(ctx context.Context) error {
+			return s.audit(ctx, model.AuditActionUserChangeEmail, user, map[string]interface{}{
+				"old_email": before.Email,
+				"new_email": user.Email,
+			})
+		})
+	}
+	if err := s.saveAudited(ctx, user, model.AuditActionUserUpdate, map[string]interface{}{"changes": changes}, steps...); err != nil {
+		return nil, err
+	}
+	if _, ok := changes["email"]; ok {
+		s.sendEmailChangedNotice(ctx, before.Email, user)
+	}
+
+	resp := toUserResponse(user)
+	resp.Changes = changes
+	return resp, nil
+}
+
+// saveAudited updates user and records action in the same transaction. Each
+// step, such as a guard or an extra audit entry, runs first in that
+// transaction; an error from one aborts the update.
+func (s *userService) saveAudited(ctx context.Context, user *model.User, action string, metadata map[string]interface{}, steps ...func(context.Context) error) error {
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, step := range steps {
+			if err := step(ctx); err != nil {
+				return err
+			}
+		}
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return s.audit(ctx, action, user, metadata)
+	})
+	if errors.Is(err, repository.ErrStaleVersion) {
+		return ErrVersionConflict
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// email is the only unique column an update can change; see changeEmail
+		return ErrEmailAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+
+	s.userUpdated(ctx, user)
+	return nil
+}
+
+// changeEmail sets user's email, rejecting an address that belongs to
+// another account of the tenant. An address held in another tenant is only
+// caught by the unique index when the update is saved.
+func (s *userService) changeEmail(ctx context.Context, user *model.User, email string) error {
+	email = normalizeEmail(email)
+	if email == user.Email {
+		return nil
+	}
+
+	existing, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil {
+		return ErrEmailAlreadyExists
+	}
+
+	user.Email = email
+	return nil
+}
+
+// sendEmailChangedNotice tells the previous address that the account's email
+// was changed, so an owner whose account was taken over finds out. Delivery
+// failures are logged; the change itself has already been saved.
+func (s *userService) sendEmailChangedNotice(ctx context.Context, oldEmail string, user *model.User) {
+	body := fmt.Sprintf("Hi %s,\n\nThe email address of your account was changed from %s to %s.\n\nIf you did not make this change, contact support straight away.\n",
+		user.Name, oldEmail, user.Email)
+	if err := s.mailer.Send(ctx, oldEmail, "Your email address was changed", body); err != nil {
+		logger.WithContext(ctx).Error("Failed to send email change notice", zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
 }
 
 func (s *userService) Delete(ctx context.Context, id string) error {
-	_, err := s.userRepo.FindByID(ctx, id)
+	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrUserNotFound
@@ -131,15 +491,54 @@ func (s *userService) Delete(ctx context.Context, id string) error {
 		return err
 	}
 
-	return s.userRepo.Delete(ctx, id)
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.audit(ctx, model.AuditActionUserDelete, user, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.evictUser(ctx, id)
+	s.publish(UserDeleted{User: toUserResponse(user)})
+	return nil
+}
+
+func (s *userService) newUser(input *CreateUserInput) (*model.User, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), s.passwordCost)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.User{
+		Name:           input.Name,
+		Email:          normalizeEmail(input.Email),
+		Password:       string(hashedPassword),
+		Role:           "user",
+		IsActive:       true,
+		ApprovalStatus: model.ApprovalApproved,
+	}, nil
+}
+
+// normalizeEmail is the form emails are stored and looked up in, so addresses
+// differing only in case or surrounding space belong to the same account.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
 func toUserResponse(user *model.User) *UserResponse {
 	return &UserResponse{
-		ID:       user.ID.String(),
-		Name:     user.Name,
-		Email:    user.Email,
-		Role:     user.Role,
-		IsActive: user.IsActive,
+		ID:             user.ID.String(),
+		TenantID:       user.TenantID,
+		Name:           user.Name,
+		Email:          user.Email,
+		Role:           user.Role,
+		IsActive:       user.IsActive,
+		ApprovalStatus: user.ApprovalStatus,
+		Version:        user.Version,
+		CreatedAt:      user.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:      user.UpdatedAt.UTC().Format(time.RFC3339),
 	}
 }
\ No newline at end of file
@@ -2,13 +2,23 @@ package service
 
 import (
 	"context"
-	// "errors"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/internal/repository"
+	"github.com/ariam/my-api/pkg/jwt"
+	"github.com/ariam/my-api/pkg/mailer"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
@@ -21,6 +31,11 @@ func (m *MockUserRepository) Create(ctx context.Context, user *model.User) error
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) CreateBatch(ctx context.Context, users []model.User) error {
+	args := m.Called(ctx, users)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) FindByID(ctx context.Context, id string) (*model.User, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -37,16 +52,57 @@ func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*mo
 	return args.Get(0).(*model.User), args.Error(1)
 }
 
-func (m *MockUserRepository) FindAll(ctx context.Context, page, perPage int) ([]model.User, int64, error) {
-	args := m.Called(ctx, page, perPage)
+func (m *MockUserRepository) FindDeletedByEmail(ctx context.Context, email string) (*model.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindAll(ctx context.Context, filter repository.UserFilter, sort repository.Sort, page, perPage int) ([]model.User, int64, error) {
+	args := m.Called(ctx, filter, sort, page, perPage)
+	return args.Get(0).([]model.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) FindAfter(ctx context.Context, filter repository.UserFilter, cursor string, limit int) ([]model.User, string, error) {
+	args := m.Called(ctx, filter, cursor, limit)
+	return args.Get(0).([]model.User), args.String(1), args.Error(2)
+}
+
+func (m *MockUserRepository) Search(ctx context.Context, query string, page, perPage int) ([]model.User, int64, error) {
+	args := m.Called(ctx, query, page, perPage)
 	return args.Get(0).([]model.User), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockUserRepository) FindInBatches(ctx context.Context, filter repository.UserFilter, batchSize int, fn func([]model.User) error) error {
+	args := m.Called(ctx, filter, batchSize, fn)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	args := m.Called(ctx, role)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) LockActiveAdmins(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockUserRepository) Update(ctx context.Context, user *model.User) error {
 	args := m.Called(ctx, user)
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) Restore(ctx context.Context, user *model.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -60,10 +116,11 @@ func TestUserService_Create_Success(t *testing.T) {
 	input := &CreateUserInput{
 		Name:     "John Doe",
 		Email:    "john@example.com",
-		Password: "password123",
+		Password: "Password123!",
 	}
 
 	mockRepo.On("FindByEmail", ctx, input.Email).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindDeletedByEmail", ctx, input.Email).Return(nil, gorm.ErrRecordNotFound)
 	mockRepo.On("Create", ctx, mock.AnythingOfType("*model.User")).Return(nil)
 
 	result, err := service.Create(ctx, input)
@@ -75,6 +132,22 @@ func TestUserService_Create_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserService_Create_ReturnsTimestamps(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewUserService(repository.NewUserRepository(db), WithPasswordCost(bcrypt.MinCost))
+
+	result, err := service.Create(context.Background(), &CreateUserInput{Name: "John Doe", Email: "john@example.com", Password: "Password123!"})
+	require.NoError(t, err)
+
+	createdAt, err := time.Parse(time.RFC3339, result.CreatedAt)
+	require.NoError(t, err)
+	updatedAt, err := time.Parse(time.RFC3339, result.UpdatedAt)
+	require.NoError(t, err)
+	assert.False(t, createdAt.IsZero())
+	assert.False(t, updatedAt.IsZero())
+	assert.WithinDuration(t, time.Now(), createdAt, time.Minute)
+}
+
 func TestUserService_Create_EmailExists(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	service := NewUserService(mockRepo)
@@ -83,7 +156,7 @@ func TestUserService_Create_EmailExists(t *testing.T) {
 	input := &CreateUserInput{
 		Name:     "John Doe",
 		Email:    "john@example.com",
-		Password: "password123",
+		Password: "Password123!",
 	}
 
 	existingUser := &model.User{
@@ -101,6 +174,157 @@ func TestUserService_Create_EmailExists(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserService_Create_EmailExistsInAnotherTenant(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+	ctx := context.Background()
+
+	input := &CreateUserInput{
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		Password: "Password123!",
+	}
+
+	// the tenant-scoped lookups miss it; the global unique index doesn't
+	mockRepo.On("FindByEmail", ctx, input.Email).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindDeletedByEmail", ctx, input.Email).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*model.User")).Return(gorm.ErrDuplicatedKey)
+
+	result, err := service.Create(ctx, input)
+
+	assert.ErrorIs(t, err, ErrEmailAlreadyExists)
+	assert.Nil(t, result)
+}
+
+func TestUserService_Create_EmailLookupError(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+	ctx := context.Background()
+
+	dbErr := errors.New("connection refused")
+	mockRepo.On("FindByEmail", ctx, "john@example.com").Return(nil, dbErr)
+
+	result, err := service.Create(ctx, &CreateUserInput{Name: "John Doe", Email: "john@example.com", Password: "Password123!"})
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUserService_Create_DeletedEmailPolicy(t *testing.T) {
+	tests := []struct {
+		policy  string
+		wantErr error
+	}{
+		{policy: DeletedEmailReject, wantErr: ErrEmailDeletedAccount},
+		{policy: DeletedEmailRestore},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			db := setupTestDB(t)
+			service := NewUserService(repository.NewUserRepository(db), WithPasswordCost(bcrypt.MinCost), WithDeletedEmailPolicy(tt.policy))
+			ctx := context.Background()
+
+			original, err := service.Create(ctx, &CreateUserInput{Name: "John Doe", Email: "john@example.com", Password: "Password123!"})
+			require.NoError(t, err)
+			_, err = service.SetRole(ctx, original.ID, "admin")
+			require.NoError(t, err)
+			require.NoError(t, service.Delete(ctx, original.ID))
+
+			created, err := service.Create(ctx, &CreateUserInput{Name: "Johnny", Email: "John@example.com", Password: "Password456!"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				_, err = service.FindByID(ctx, original.ID)
+				assert.ErrorIs(t, err, ErrUserNotFound, "the deleted account stays deleted")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, original.ID, created.ID, "the deleted account is restored")
+			found, err := service.FindByID(ctx, original.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Johnny", found.Name)
+			assert.Equal(t, "user", found.Role, "a restored account does not regain its old role")
+			assert.Equal(t, original.CreatedAt, found.CreatedAt)
+
+			auth := NewAuthService(repository.NewUserRepository(db), jwt.NewJWTManager("test-secret-key-min-32-characters", 24))
+			_, err = auth.Login(ctx, &LoginInput{Email: "john@example.com", Password: "Password456!"})
+			assert.NoError(t, err, "the new password applies")
+		})
+	}
+}
+
+func TestUserService_Create_RestoreRevokesCredentials(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&model.Session{}))
+	sessions := repository.NewSessionRepository(db)
+	tokens := repository.NewUserTokenRepository(db)
+	service := NewUserService(repository.NewUserRepository(db),
+		WithPasswordCost(bcrypt.MinCost),
+		WithDeletedEmailPolicy(DeletedEmailRestore),
+		WithSessionRepository(sessions),
+		WithUserTokens(tokens, mailer.NewNoop()),
+	)
+	ctx := context.Background()
+
+	original, err := service.Create(ctx, &CreateUserInput{Name: "John Doe", Email: "john@example.com", Password: "Password123!"})
+	require.NoError(t, err)
+	userID := uuid.MustParse(original.ID)
+	require.NoError(t, sessions.Create(ctx, &model.Session{UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}))
+	require.NoError(t, tokens.Create(ctx, &model.UserToken{UserID: userID, Purpose: model.TokenPurposePasswordReset, TokenHash: "hash", ExpiresAt: time.Now().Add(time.Hour)}))
+	require.NoError(t, service.Delete(ctx, original.ID))
+
+	restored, err := service.Create(ctx, &CreateUserInput{Name: "Johnny", Email: "john@example.com", Password: "Password456!"})
+	require.NoError(t, err)
+	require.Equal(t, original.ID, restored.ID)
+
+	active, err := sessions.FindActiveByUser(ctx, original.ID)
+	require.NoError(t, err)
+	assert.Empty(t, active, "the previous owner's sessions are revoked")
+	token, err := tokens.FindByHash(ctx, model.TokenPurposePasswordReset, "hash")
+	require.NoError(t, err)
+	assert.NotNil(t, token.UsedAt, "the previous owner's links stop working")
+}
+
+func TestUserService_Create_DeletedLookupError(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, WithDeletedEmailPolicy(DeletedEmailRestore))
+	ctx := context.Background()
+
+	dbErr := errors.New("connection refused")
+	mockRepo.On("FindByEmail", ctx, "john@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindDeletedByEmail", ctx, "john@example.com").Return(nil, dbErr)
+
+	_, err := service.Create(ctx, &CreateUserInput{Name: "John Doe", Email: "john@example.com", Password: "Password123!"})
+
+	assert.ErrorIs(t, err, dbErr)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUserService_Create_NormalizesEmail(t *testing.T) {
+	db := setupTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	service := NewUserService(userRepo, WithPasswordCost(bcrypt.MinCost))
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, &CreateUserInput{Name: "John Doe", Email: " John@Example.com ", Password: "Password123!"})
+	require.NoError(t, err)
+	assert.Equal(t, "john@example.com", created.Email)
+
+	_, err = service.Create(ctx, &CreateUserInput{Name: "Johnny", Email: "JOHN@example.COM", Password: "Password123!"})
+	assert.ErrorIs(t, err, ErrEmailAlreadyExists)
+
+	found, err := service.FindByEmail(ctx, "John@EXAMPLE.com")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+
+	auth := NewAuthService(userRepo, jwt.NewJWTManager("test-secret-key-min-32-characters", 24))
+	result, err := auth.Login(ctx, &LoginInput{Email: "  JOHN@Example.com", Password: "Password123!"})
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, result.User.ID)
+}
+
 func TestUserService_FindByID_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	service := NewUserService(mockRepo)
@@ -139,6 +363,57 @@ func TestUserService_FindByID_NotFound(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserService_FindByEmail(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+	ctx := context.Background()
+
+	user := &model.User{Base: model.Base{ID: uuid.New()}, Name: "John Doe", Email: "john@example.com", Role: "user"}
+	mockRepo.On("FindByEmail", ctx, "john@example.com").Return(user, nil)
+	mockRepo.On("FindByEmail", ctx, "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	result, err := service.FindByEmail(ctx, "john@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID.String(), result.ID)
+
+	result, err = service.FindByEmail(ctx, "nobody@example.com")
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.Nil(t, result)
+}
+
+func TestUserService_FindAll_NormalizesPagination(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.On("FindAll", ctx, UserFilter{}, Sort{}, 1, MaxPerPage).Return([]model.User{}, int64(0), nil)
+	mockRepo.On("Search", ctx, "john", 1, DefaultPerPage).Return([]model.User{}, int64(0), nil)
+
+	_, _, err := service.FindAll(ctx, UserFilter{}, Sort{}, 0, 10000)
+	assert.NoError(t, err)
+
+	_, _, err = service.Search(ctx, "john", -3, 0)
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_FindAll_ConfiguredPageLimits(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, WithPageLimits(PageLimits{DefaultPerPage: 25, MaxPerPage: 500}))
+	ctx := context.Background()
+
+	mockRepo.On("FindAll", ctx, UserFilter{}, Sort{}, 1, 500).Return([]model.User{}, int64(0), nil)
+	mockRepo.On("FindAll", ctx, UserFilter{}, Sort{}, 1, 25).Return([]model.User{}, int64(0), nil)
+
+	_, _, err := service.FindAll(ctx, UserFilter{}, Sort{}, 1, 501)
+	assert.NoError(t, err)
+	_, _, err = service.FindAll(ctx, UserFilter{}, Sort{}, 1, 0)
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
 func TestUserService_Delete_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	service := NewUserService(mockRepo)
@@ -170,4 +445,1023 @@ func TestUserService_Delete_NotFound(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, ErrUserNotFound, err)
 	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_Update_ReplacesProfile(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	mockRepo.On("FindByID", ctx, userID.String()).
+		Return(&model.User{Base: model.Base{ID: userID}, Name: "John", Email: "john@example.com", Role: "user"}, nil)
+	mockRepo.On("FindByEmail", ctx, "jane@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(u *model.User) bool {
+		return u.Name == "Jane" && u.Email == "jane@example.com" && u.Role == "user"
+	})).Return(nil)
+
+	result, err := service.Update(ctx, userID.String(), &UpdateUserInput{Name: "Jane", Email: "jane@example.com"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jane@example.com", result.Email)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_Update_ReportsChangedFields(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	mockRepo.On("FindByID", ctx, userID.String()).
+		Return(&model.User{Base: model.Base{ID: userID}, Name: "John", Email: "john@example.com", Role: "user"}, nil)
+	mockRepo.On("Update", ctx, mock.Anything).Return(nil)
+
+	result, err := service.Update(ctx, userID.String(), &UpdateUserInput{Name: "Jane", Email: "john@example.com"})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]FieldChange{"name": {Old: "John", New: "Jane"}}, result.Changes)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_Update_VersionConflict(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewUserService(repository.NewUserRepository(db), WithPasswordCost(bcrypt.MinCost))
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, &CreateUserInput{Name: "John Doe", Email: "john@example.com", Password: "Password123!"})
+	require.NoError(t, err)
+	read := created.Version
+
+	// another client updates first
+	updated, err := service.Update(ctx, created.ID, &UpdateUserInput{Name: "Jane Doe", Email: "john@example.com", Version: &read})
+	require.NoError(t, err)
+	assert.Equal(t, read+1, updated.Version)
+
+	_, err = service.Update(ctx, created.ID, &UpdateUserInput{Name: "Johnny", Email: "john@example.com", Version: &read})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	name := "Johnny"
+	_, err = service.Patch(ctx, created.ID, &PatchUserInput{Name: &name, Version: &read})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+
+	stored, err := service.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe", stored.Name)
+}
+
+func TestUserService_Update_StaleWriteIsConflict(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	mockRepo.On("FindByID", ctx, userID.String()).
+		Return(&model.User{Base: model.Base{ID: userID}, Name: "John", Email: "john@example.com", Version: 1}, nil)
+	mockRepo.On("Update", ctx, mock.Anything).Return(repository.ErrStaleVersion)
+
+	_, err := service.Update(ctx, userID.String(), &UpdateUserInput{Name: "Jane", Email: "john@example.com"})
+
+	assert.ErrorIs(t, err, ErrVersionConflict)
+}
+
+func TestUserService_Patch(t *testing.T) {
+	userID := uuid.New()
+	otherID := uuid.New()
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name        string
+		input       PatchUserInput
+		setupMock   func(*MockUserRepository)
+		expected    *model.User
+		expectedErr error
+	}{
+		{
+			name:     "nil fields leave the user unchanged",
+			input:    PatchUserInput{},
+			expected: &model.User{Name: "John", Email: "john@example.com", Role: "user"},
+		},
+		{
+			name:     "new name is applied",
+			input:    PatchUserInput{Name: str("Johnny")},
+			expected: &model.User{Name: "Johnny", Email: "john@example.com", Role: "user"},
+		},
+		{
+			name:  "new email is applied after a uniqueness check",
+			input: PatchUserInput{Email: str("johnny@example.com")},
+			setupMock: func(m *MockUserRepository) {
+				m.On("FindByEmail", mock.Anything, "johnny@example.com").Return(nil, gorm.ErrRecordNotFound)
+			},
+			expected: &model.User{Name: "John", Email: "johnny@example.com", Role: "user"},
+		},
+		{
+			name:     "unchanged email skips the uniqueness check",
+			input:    PatchUserInput{Email: str("john@example.com")},
+			expected: &model.User{Name: "John", Email: "john@example.com", Role: "user"},
+		},
+		{
+			name:  "email owned by another user is rejected",
+			input: PatchUserInput{Name: str("Johnny"), Email: str("taken@example.com")},
+			setupMock: func(m *MockUserRepository) {
+				m.On("FindByEmail", mock.Anything, "taken@example.com").
+					Return(&model.User{Base: model.Base{ID: otherID}, Email: "taken@example.com"}, nil)
+			},
+			expectedErr: ErrEmailAlreadyExists,
+		},
+		{
+			name:     "new role is applied",
+			input:    PatchUserInput{Role: str("admin")},
+			expected: &model.User{Name: "John", Email: "john@example.com", Role: "admin"},
+		},
+		{
+			name:     "empty name is applied as given",
+			input:    PatchUserInput{Name: str("")},
+			expected: &model.User{Name: "", Email: "john@example.com", Role: "user"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			service := NewUserService(mockRepo)
+
+			mockRepo.On("FindByID", mock.Anything, userID.String()).
+				Return(&model.User{Base: model.Base{ID: userID}, Name: "John", Email: "john@example.com", Role: "user"}, nil)
+			if tt.setupMock != nil {
+				tt.setupMock(mockRepo)
+			}
+			if tt.expected != nil {
+				mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+					return u.Name == tt.expected.Name && u.Email == tt.expected.Email && u.Role == tt.expected.Role
+				})).Return(nil)
+			}
+
+			result, err := service.Patch(context.Background(), userID.String(), &tt.input)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.Name, result.Name)
+				assert.Equal(t, tt.expected.Email, result.Email)
+				assert.Equal(t, tt.expected.Role, result.Role)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_Patch_NotFound(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.On("FindByID", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.Patch(ctx, "missing", &PatchUserInput{})
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_SetRole(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name        string
+		role        string
+		setupMock   func(*MockUserRepository)
+		expectedErr error
+	}{
+		{
+			name:        "unknown role is rejected",
+			role:        "superuser",
+			expectedErr: ErrInvalidRole,
+		},
+		{
+			name: "missing user",
+			role: "admin",
+			setupMock: func(m *MockUserRepository) {
+				m.On("FindByID", mock.Anything, userID.String()).Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedErr: ErrUserNotFound,
+		},
+		{
+			name: "user is promoted",
+			role: "admin",
+			setupMock: func(m *MockUserRepository) {
+				m.On("FindByID", mock.Anything, userID.String()).Return(&model.User{Base: model.Base{ID: userID}, Role: "user"}, nil)
+				m.On("Update", mock.Anything, mock.MatchedBy(func(u *model.User) bool { return u.Role == "admin" })).Return(nil)
+			},
+		},
+		{
+			name: "admin is demoted while another admin remains",
+			role: "user",
+			setupMock: func(m *MockUserRepository) {
+				m.On("FindByID", mock.Anything, userID.String()).Return(&model.User{Base: model.Base{ID: userID}, Role: "admin"}, nil)
+				m.On("LockActiveAdmins", mock.Anything).Return([]string{userID.String(), uuid.NewString()}, nil)
+				m.On("Update", mock.Anything, mock.MatchedBy(func(u *model.User) bool { return u.Role == "user" })).Return(nil)
+			},
+		},
+		{
+			name: "last admin cannot be demoted",
+			role: "user",
+			setupMock: func(m *MockUserRepository) {
+				m.On("FindByID", mock.Anything, userID.String()).Return(&model.User{Base: model.Base{ID: userID}, Role: "admin"}, nil)
+				m.On("LockActiveAdmins", mock.Anything).Return([]string{userID.String()}, nil)
+			},
+			expectedErr: ErrLastAdmin,
+		},
+		{
+			name: "last admin keeping the admin role is a no-op change",
+			role: "admin",
+			setupMock: func(m *MockUserRepository) {
+				m.On("FindByID", mock.Anything, userID.String()).Return(&model.User{Base: model.Base{ID: userID}, Role: "admin"}, nil)
+				m.On("Update", mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			if tt.setupMock != nil {
+				tt.setupMock(mockRepo)
+			}
+			service := NewUserService(mockRepo)
+
+			result, err := service.SetRole(context.Background(), userID.String(), tt.role)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.role, result.Role)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_SetRole_OnlyAdminsWhoCanSignInCount(t *testing.T) {
+	db := setupTestDB(t)
+	service := NewUserService(repository.NewUserRepository(db), WithTransactor(NewTransactor(db)))
+	ctx := context.Background()
+
+	active := model.User{Name: "Active", Email: "active@example.com", Password: "x", Role: "admin", IsActive: true}
+	inactive := model.User{Name: "Inactive", Email: "inactive@example.com", Password: "x", Role: "admin"}
+	pending := model.User{Name: "Pending", Email: "pending@example.com", Password: "x", Role: "admin", IsActive: true, ApprovalStatus: model.ApprovalPending}
+	for _, u := range []*model.User{&active, &inactive, &pending} {
+		require.NoError(t, db.Create(u).Error)
+	}
+	require.NoError(t, db.Model(&inactive).Update("is_active", false).Error)
+
+	_, err := service.SetRole(ctx, active.ID.String(), "user")
+	assert.ErrorIs(t, err, ErrLastAdmin)
+
+	// demoting an admin who can't sign in leaves the active one in place
+	_, err = service.SetRole(ctx, inactive.ID.String(), "user")
+	assert.NoError(t, err)
+}
+
+func TestUserService_Patch_LastAdminGuard(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+	ctx := context.Background()
+	role := "user"
+
+	adminID := uuid.New()
+	mockRepo.On("FindByID", ctx, "admin-id").Return(&model.User{Base: model.Base{ID: adminID}, Role: "admin"}, nil)
+	mockRepo.On("LockActiveAdmins", ctx).Return([]string{adminID.String()}, nil)
+
+	_, err := service.Patch(ctx, "admin-id", &PatchUserInput{Role: &role})
+
+	assert.ErrorIs(t, err, ErrLastAdmin)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func streamUsers(count int) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		enc := json.NewEncoder(pw)
+		pw.Write([]byte("["))
+		for i := 0; i < count; i++ {
+			if i > 0 {
+				pw.Write([]byte(","))
+			}
+			enc.Encode(CreateUserInput{
+				Name:     fmt.Sprintf("User %d", i),
+				Email:    fmt.Sprintf("user%d@example.com", i),
+				Password: "Password123!",
+			})
+		}
+		pw.Write([]byte("]"))
+		pw.Close()
+	}()
+	return pr
+}
+
+func TestUserService_BulkCreate_StreamsInBoundedBatches(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, WithPasswordCost(bcrypt.MinCost), WithBulkImport(20, 1000))
+	ctx := context.Background()
+
+	var batchSizes []int
+	mockRepo.On("FindByEmail", ctx, mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindDeletedByEmail", ctx, mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("CreateBatch", ctx, mock.AnythingOfType("[]model.User")).
+		Run(func(args mock.Arguments) {
+			batchSizes = append(batchSizes, len(args.Get(1).([]model.User)))
+		}).
+		Return(nil)
+
+	result, err := service.BulkCreate(ctx, streamUsers(250))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 250, result.Processed)
+	assert.Equal(t, 250, result.Created)
+	assert.Empty(t, result.Failed)
+	assert.Len(t, batchSizes, 13)
+	for _, size := range batchSizes {
+		assert.LessOrEqual(t, size, 20)
+	}
+}
+
+func TestUserService_BulkCreate_MaxItemsExceeded(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, WithPasswordCost(bcrypt.MinCost), WithBulkImport(10, 25))
+	ctx := context.Background()
+
+	mockRepo.On("FindByEmail", ctx, mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindDeletedByEmail", ctx, mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("CreateBatch", ctx, mock.AnythingOfType("[]model.User")).Return(nil)
+
+	result, err := service.BulkCreate(ctx, streamUsers(30))
+
+	assert.ErrorIs(t, err, ErrBulkImportTooLarge)
+	assert.Equal(t, 25, result.Processed)
+	assert.Equal(t, 25, result.Created)
+}
+
+func TestUserService_BulkCreate_ItemFailures(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, WithPasswordCost(bcrypt.MinCost))
+	ctx := context.Background()
+
+	existingUser := &model.User{Base: model.Base{ID: uuid.New()}, Email: "taken@example.com"}
+	mockRepo.On("FindByEmail", ctx, "taken@example.com").Return(existingUser, nil)
+	mockRepo.On("FindByEmail", ctx, "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindDeletedByEmail", ctx, "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("CreateBatch", ctx, mock.AnythingOfType("[]model.User")).Return(nil)
+
+	payload := `[
+		{"name": "New", "email": "new@example.com", "password": "Password123!"},
+		{"name": "Taken", "email": "taken@example.com", "password": "Password123!"},
+		{"name": "X", "email": "not-an-email", "password": "short"},
+		{"name": 42, "email": "typed@example.com", "password": "Password123!"}
+	]`
+
+	result, err := service.BulkCreate(ctx, strings.NewReader(payload))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.Processed)
+	assert.Equal(t, 1, result.Created)
+	assert.Len(t, result.Failed, 3)
+	assert.Equal(t, 1, result.Failed[0].Index)
+	assert.Equal(t, ErrEmailAlreadyExists.Error(), result.Failed[0].Error)
+	assert.Equal(t, 2, result.Failed[1].Index)
+	assert.Equal(t, 3, result.Failed[2].Index)
+}
+
+func TestUserService_BulkCreate_InvalidPayload(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, WithPasswordCost(bcrypt.MinCost))
+	ctx := context.Background()
+
+	result, err := service.BulkCreate(ctx, strings.NewReader(`{"name": "not an array"}`))
+
+	assert.ErrorIs(t, err, ErrInvalidBulkPayload)
+	assert.Nil(t, result)
+}
+
+func TestUserService_BulkCreate_TruncatedStreamFlushesDecodedItems(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, WithPasswordCost(bcrypt.MinCost))
+	ctx := context.Background()
+
+	mockRepo.On("FindByEmail", ctx, "first@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindDeletedByEmail", ctx, "first@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("CreateBatch", ctx, mock.AnythingOfType("[]model.User")).Return(nil)
+
+	payload := `[{"name": "First", "email": "first@example.com", "password": "Password123!"}, {"name": "Sec`
+
+	result, err := service.BulkCreate(ctx, strings.NewReader(payload))
+
+	assert.ErrorIs(t, err, ErrInvalidBulkPayload)
+	assert.Equal(t, 1, result.Created)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserService_BulkCreate_LookupErrorStopsImport(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, WithPasswordCost(bcrypt.MinCost))
+	ctx := context.Background()
+
+	dbErr := errors.New("connection refused")
+	mockRepo.On("FindByEmail", ctx, "first@example.com").Return(nil, dbErr)
+
+	payload := `[{"name": "First", "email": "first@example.com", "password": "Password123!"}]`
+
+	result, err := service.BulkCreate(ctx, strings.NewReader(payload))
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Equal(t, 0, result.Created)
+	mockRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+}
+
+func TestUserService_BulkCreate_DeletedEmailIsItemFailure(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, WithPasswordCost(bcrypt.MinCost))
+	ctx := context.Background()
+
+	mockRepo.On("FindByEmail", ctx, mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindDeletedByEmail", ctx, "gone@example.com").Return(&model.User{Base: model.Base{ID: uuid.New()}}, nil)
+	mockRepo.On("FindDeletedByEmail", ctx, "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("CreateBatch", ctx, mock.AnythingOfType("[]model.User")).Return(nil)
+
+	payload := `[
+		{"name": "Gone", "email": "gone@example.com", "password": "Password123!"},
+		{"name": "New", "email": "new@example.com", "password": "Password123!"}
+	]`
+
+	result, err := service.BulkCreate(ctx, strings.NewReader(payload))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, 0, result.Failed[0].Index)
+	assert.Equal(t, ErrEmailDeletedAccount.Error(), result.Failed[0].Error)
+}
+
+func TestUserService_BulkCreate_DuplicateKeyIsItemFailure(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, WithPasswordCost(bcrypt.MinCost))
+	ctx := context.Background()
+
+	// the email is held in another tenant, so only the unique index sees it
+	mockRepo.On("FindByEmail", ctx, mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindDeletedByEmail", ctx, mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("CreateBatch", ctx, mock.AnythingOfType("[]model.User")).Return(gorm.ErrDuplicatedKey)
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(u *model.User) bool { return u.Email == "other-tenant@example.com" })).Return(gorm.ErrDuplicatedKey)
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(u *model.User) bool { return u.Email == "new@example.com" })).Return(nil)
+
+	payload := `[
+		{"name": "New", "email": "new@example.com", "password": "Password123!"},
+		{"name": "Other", "email": "other-tenant@example.com", "password": "Password123!"}
+	]`
+
+	result, err := service.BulkCreate(ctx, strings.NewReader(payload))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Processed)
+	assert.Equal(t, 1, result.Created)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, 1, result.Failed[0].Index)
+	assert.Equal(t, ErrEmailAlreadyExists.Error(), result.Failed[0].Error)
+}
+
+func TestUserService_ExportPersonalData(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessions := new(MockSessionRepository)
+	service := NewUserService(mockRepo, WithSessionRepository(mockSessions))
+	ctx := context.Background()
+
+	userID := uuid.New()
+	user := &model.User{
+		Base:     model.Base{ID: userID},
+		Name:     "John Doe",
+		Email:    "john@example.com",
+		Password: "$2a$10$secrethashvalue",
+		Role:     "user",
+		IsActive: true,
+	}
+	sessions := []model.Session{
+		{Base: model.Base{ID: uuid.New()}, UserID: userID, IP: "10.0.0.1", UserAgent: "curl/8.0"},
+	}
+
+	mockRepo.On("FindByID", ctx, userID.String()).Return(user, nil)
+	mockSessions.On("FindByUser", ctx, userID.String()).Return(sessions, nil)
+
+	export, err := service.ExportPersonalData(ctx, userID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "john@example.com", export.Profile.Email)
+	assert.Len(t, export.Sessions, 1)
+	assert.Equal(t, "10.0.0.1", export.Sessions[0].IP)
+
+	body, err := json.Marshal(export)
+	assert.NoError(t, err)
+
+	var bundle map[string]interface{}
+	assert.NoError(t, json.Unmarshal(body, &bundle))
+	assert.Contains(t, bundle, "profile")
+	assert.Contains(t, bundle, "sessions")
+	assert.Contains(t, bundle, "exported_at")
+	assert.NotContains(t, bundle["profile"], "password")
+	assert.NotContains(t, string(body), user.Password)
+}
+
+func TestUserService_ExportPersonalData_NotFound(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.On("FindByID", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	export, err := service.ExportPersonalData(ctx, "missing")
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.Nil(t, export)
+}
+
+type MockUserTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserTokenRepository) Create(ctx context.Context, token *model.UserToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockUserTokenRepository) FindByHash(ctx context.Context, purpose, hash string) (*model.UserToken, error) {
+	args := m.Called(ctx, purpose, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.UserToken), args.Error(1)
+}
+
+func (m *MockUserTokenRepository) MarkUsed(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserTokenRepository) MarkAllUsed(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) Send(ctx context.Context, to, subject, body string) error {
+	args := m.Called(ctx, to, subject, body)
+	return args.Error(0)
+}
+
+func TestUserService_Register_WithEmailVerification(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokens := new(MockUserTokenRepository)
+	mockMailer := new(MockMailer)
+	service := NewUserService(mockRepo,
+		WithPasswordCost(bcrypt.MinCost),
+		WithUserTokens(mockTokens, mockMailer),
+		WithEmailVerification("https://api.example.com", time.Hour),
+	)
+	ctx := context.Background()
+
+	var stored *model.UserToken
+	mockRepo.On("FindByEmail", ctx, "john@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindDeletedByEmail", ctx, "john@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(u *model.User) bool { return !u.IsActive })).Return(nil)
+	mockTokens.On("Create", ctx, mock.AnythingOfType("*model.UserToken")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*model.UserToken)
+	}).Return(nil)
+	mockMailer.On("Send", ctx, "john@example.com", mock.Anything, mock.MatchedBy(func(body string) bool {
+		return strings.Contains(body, "https://api.example.com/api/v1/auth/verify?token=")
+	})).Return(nil)
+
+	result, err := service.Register(ctx, &CreateUserInput{Name: "John Doe", Email: "john@example.com", Password: "Password123!"})
+
+	assert.NoError(t, err)
+	assert.False(t, result.IsActive)
+	assert.Equal(t, model.TokenPurposeEmailVerification, stored.Purpose)
+	assert.Len(t, stored.TokenHash, 64)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+	mockMailer.AssertExpectations(t)
+}
+
+func TestUserService_Register_VerificationEmailFails(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokens := new(MockUserTokenRepository)
+	mockMailer := new(MockMailer)
+	service := NewUserService(mockRepo,
+		WithPasswordCost(bcrypt.MinCost),
+		WithUserTokens(mockTokens, mockMailer),
+		WithEmailVerification("https://api.example.com", time.Hour),
+	)
+	ctx := context.Background()
+
+	mockRepo.On("FindByEmail", ctx, "john@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindDeletedByEmail", ctx, "john@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*model.User")).Return(nil)
+	mockTokens.On("Create", ctx, mock.AnythingOfType("*model.UserToken")).Return(nil)
+	mockMailer.On("Send", ctx, "john@example.com", mock.Anything, mock.Anything).Return(errors.New("smtp unavailable"))
+
+	result, err := service.Register(ctx, &CreateUserInput{Name: "John Doe", Email: "john@example.com", Password: "Password123!"})
+
+	assert.ErrorIs(t, err, ErrVerificationEmailNotSent)
+	assert.NotNil(t, result)
+	assert.Equal(t, "john@example.com", result.Email)
+}
+
+func TestUserService_Create_SkipsSignupGating(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockTokens := new(MockUserTokenRepository)
+	mockMailer := new(MockMailer)
+	service := NewUserService(mockRepo,
+		WithPasswordCost(bcrypt.MinCost),
+		WithUserTokens(mockTokens, mockMailer),
+		WithEmailVerification("https://api.example.com", time.Hour),
+		WithApproval(),
+	)
+	ctx := context.Background()
+
+	mockRepo.On("FindByEmail", ctx, "john@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindDeletedByEmail", ctx, "john@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*model.User")).Return(nil)
+
+	result, err := service.Create(ctx, &CreateUserInput{Name: "John Doe", Email: "john@example.com", Password: "Password123!"})
+
+	assert.NoError(t, err)
+	assert.True(t, result.IsActive)
+	assert.Equal(t, model.ApprovalApproved, result.ApprovalStatus)
+	mockTokens.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUserService_VerifyEmail(t *testing.T) {
+	userID := uuid.New()
+	tokenID := uuid.New()
+	usedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		setupMock   func(*MockUserRepository, *MockUserTokenRepository)
+		expectedErr error
+	}{
+		{
+			name: "valid token activates the user",
+			setupMock: func(users *MockUserRepository, tokens *MockUserTokenRepository) {
+				tokens.On("FindByHash", mock.Anything, model.TokenPurposeEmailVerification, hashToken("secret")).
+					Return(&model.UserToken{Base: model.Base{ID: tokenID}, UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+				tokens.On("MarkUsed", mock.Anything, tokenID.String()).Return(true, nil)
+				users.On("FindByID", mock.Anything, userID.String()).Return(&model.User{Base: model.Base{ID: userID}}, nil)
+				users.On("Update", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+					return u.IsActive && u.EmailVerifiedAt != nil
+				})).Return(nil)
+			},
+		},
+		{
+			name: "unknown token",
+			setupMock: func(users *MockUserRepository, tokens *MockUserTokenRepository) {
+				tokens.On("FindByHash", mock.Anything, model.TokenPurposeEmailVerification, hashToken("secret")).Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedErr: ErrInvalidToken,
+		},
+		{
+			name: "expired token",
+			setupMock: func(users *MockUserRepository, tokens *MockUserTokenRepository) {
+				tokens.On("FindByHash", mock.Anything, model.TokenPurposeEmailVerification, hashToken("secret")).
+					Return(&model.UserToken{Base: model.Base{ID: tokenID}, UserID: userID, ExpiresAt: time.Now().Add(-time.Second)}, nil)
+			},
+			expectedErr: ErrTokenExpired,
+		},
+		{
+			name: "already used token",
+			setupMock: func(users *MockUserRepository, tokens *MockUserTokenRepository) {
+				tokens.On("FindByHash", mock.Anything, model.TokenPurposeEmailVerification, hashToken("secret")).
+					Return(&model.UserToken{Base: model.Base{ID: tokenID}, UserID: userID, ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}, nil)
+			},
+			expectedErr: ErrTokenUsed,
+		},
+		{
+			name: "token consumed concurrently",
+			setupMock: func(users *MockUserRepository, tokens *MockUserTokenRepository) {
+				tokens.On("FindByHash", mock.Anything, model.TokenPurposeEmailVerification, hashToken("secret")).
+					Return(&model.UserToken{Base: model.Base{ID: tokenID}, UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+				tokens.On("MarkUsed", mock.Anything, tokenID.String()).Return(false, nil)
+			},
+			expectedErr: ErrTokenUsed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			mockTokens := new(MockUserTokenRepository)
+			tt.setupMock(mockRepo, mockTokens)
+			service := NewUserService(mockRepo, WithUserTokens(mockTokens, new(MockMailer)), WithEmailVerification("", 0))
+
+			err := service.VerifyEmail(context.Background(), "secret")
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+			mockTokens.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_RequestPasswordReset(t *testing.T) {
+	t.Run("known email gets a reset link", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokens := new(MockUserTokenRepository)
+		mockMailer := new(MockMailer)
+		service := NewUserService(mockRepo,
+			WithUserTokens(mockTokens, mockMailer),
+			WithPasswordReset("https://app.example.com/reset", 30*time.Minute),
+		)
+		ctx := context.Background()
+
+		user := &model.User{Base: model.Base{ID: uuid.New()}, Name: "John", Email: "john@example.com"}
+		var stored *model.UserToken
+		mockRepo.On("FindByEmail", ctx, "john@example.com").Return(user, nil)
+		mockTokens.On("Create", ctx, mock.AnythingOfType("*model.UserToken")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*model.UserToken)
+		}).Return(nil)
+		mockMailer.On("Send", ctx, "john@example.com", mock.Anything, mock.MatchedBy(func(body string) bool {
+			return strings.Contains(body, "https://app.example.com/reset?token=")
+		})).Return(nil)
+
+		err := service.RequestPasswordReset(ctx, "john@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.TokenPurposePasswordReset, stored.Purpose)
+		assert.WithinDuration(t, time.Now().Add(30*time.Minute), stored.ExpiresAt, time.Minute)
+		mockMailer.AssertExpectations(t)
+	})
+
+	t.Run("unknown email succeeds without sending", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokens := new(MockUserTokenRepository)
+		mockMailer := new(MockMailer)
+		service := NewUserService(mockRepo, WithUserTokens(mockTokens, mockMailer))
+		ctx := context.Background()
+
+		mockRepo.On("FindByEmail", ctx, "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+		err := service.RequestPasswordReset(ctx, "nobody@example.com")
+
+		assert.NoError(t, err)
+		mockTokens.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// failingUpdateRepo is a UserRepository whose updates fail.
+type failingUpdateRepo struct {
+	repository.UserRepository
+}
+
+func (failingUpdateRepo) Update(ctx context.Context, user *model.User) error {
+	return errors.New("database unavailable")
+}
+
+func TestUserService_VerifyEmail_FailedUpdateKeepsToken(t *testing.T) {
+	db := setupTestDB(t)
+	users := repository.NewUserRepository(db)
+	tokens := repository.NewUserTokenRepository(db)
+	ctx := context.Background()
+
+	user := &model.User{Name: "John", Email: "john@example.com", Password: "x"}
+	require.NoError(t, users.Create(ctx, user))
+
+	svc := NewUserService(users, WithTransactor(NewTransactor(db)), WithUserTokens(tokens, new(MockMailer)), WithEmailVerification("", 0))
+	token, err := svc.GenerateVerificationToken(ctx, user.ID.String())
+	require.NoError(t, err)
+
+	failing := NewUserService(failingUpdateRepo{users}, WithTransactor(NewTransactor(db)), WithUserTokens(tokens, new(MockMailer)), WithEmailVerification("", 0))
+	require.Error(t, failing.VerifyEmail(ctx, token))
+
+	require.NoError(t, svc.VerifyEmail(ctx, token), "the token survives the failed attempt")
+	verified, err := users.FindByID(ctx, user.ID.String())
+	require.NoError(t, err)
+	assert.True(t, verified.IsActive)
+	assert.NotNil(t, verified.EmailVerifiedAt)
+}
+
+func TestUserService_ResetPassword(t *testing.T) {
+	userID := uuid.New()
+	tokenID := uuid.New()
+	usedAt := time.Now().Add(-time.Minute)
+
+	validToken := func() *model.UserToken {
+		return &model.UserToken{Base: model.Base{ID: tokenID}, UserID: userID, Purpose: model.TokenPurposePasswordReset, ExpiresAt: time.Now().Add(time.Hour)}
+	}
+
+	tests := []struct {
+		name        string
+		setupMock   func(*MockUserRepository, *MockUserTokenRepository, *MockSessionRepository)
+		expectedErr error
+	}{
+		{
+			name: "valid token updates the password and revokes sessions",
+			setupMock: func(users *MockUserRepository, tokens *MockUserTokenRepository, sessions *MockSessionRepository) {
+				tokens.On("FindByHash", mock.Anything, model.TokenPurposePasswordReset, hashToken("secret")).Return(validToken(), nil)
+				tokens.On("MarkUsed", mock.Anything, tokenID.String()).Return(true, nil)
+				users.On("FindByID", mock.Anything, userID.String()).Return(&model.User{Base: model.Base{ID: userID}, Password: "old-hash"}, nil)
+				users.On("Update", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+					return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("new-password")) == nil
+				})).Return(nil)
+				active := activeSessions(userID, 2)
+				sessions.On("FindActiveByUser", mock.Anything, userID.String()).Return(active, nil)
+				sessions.On("Revoke", mock.Anything, []string{active[0].ID.String(), active[1].ID.String()}).Return(nil)
+			},
+		},
+		{
+			name: "expired token",
+			setupMock: func(users *MockUserRepository, tokens *MockUserTokenRepository, sessions *MockSessionRepository) {
+				expired := validToken()
+				expired.ExpiresAt = time.Now().Add(-time.Second)
+				tokens.On("FindByHash", mock.Anything, model.TokenPurposePasswordReset, hashToken("secret")).Return(expired, nil)
+			},
+			expectedErr: ErrTokenExpired,
+		},
+		{
+			name: "reused token",
+			setupMock: func(users *MockUserRepository, tokens *MockUserTokenRepository, sessions *MockSessionRepository) {
+				used := validToken()
+				used.UsedAt = &usedAt
+				tokens.On("FindByHash", mock.Anything, model.TokenPurposePasswordReset, hashToken("secret")).Return(used, nil)
+			},
+			expectedErr: ErrTokenUsed,
+		},
+		{
+			name: "verification token cannot reset a password",
+			setupMock: func(users *MockUserRepository, tokens *MockUserTokenRepository, sessions *MockSessionRepository) {
+				tokens.On("FindByHash", mock.Anything, model.TokenPurposePasswordReset, hashToken("secret")).Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			mockTokens := new(MockUserTokenRepository)
+			mockSessions := new(MockSessionRepository)
+			tt.setupMock(mockRepo, mockTokens, mockSessions)
+			service := NewUserService(mockRepo,
+				WithPasswordCost(bcrypt.MinCost),
+				WithSessionRepository(mockSessions),
+				WithUserTokens(mockTokens, new(MockMailer)),
+			)
+
+			err := service.ResetPassword(context.Background(), "secret", "new-password")
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+			mockTokens.AssertExpectations(t)
+			mockSessions.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_AdminSetPassword(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+	newPassword := func(u *model.User) bool {
+		return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("N3w-Password!")) == nil
+	}
+
+	tests := []struct {
+		name           string
+		id             uuid.UUID
+		revokeSessions bool
+		setupMock      func(*MockUserRepository, *MockSessionRepository)
+		expectedErr    error
+	}{
+		{
+			name:           "sets the password and revokes sessions",
+			id:             userID,
+			revokeSessions: true,
+			setupMock: func(users *MockUserRepository, sessions *MockSessionRepository) {
+				users.On("FindByID", mock.Anything, userID.String()).Return(&model.User{Base: model.Base{ID: userID}, Password: "old-hash"}, nil)
+				users.On("Update", mock.Anything, mock.MatchedBy(newPassword)).Return(nil)
+				active := activeSessions(userID, 1)
+				sessions.On("FindActiveByUser", mock.Anything, userID.String()).Return(active, nil)
+				sessions.On("Revoke", mock.Anything, []string{active[0].ID.String()}).Return(nil)
+			},
+		},
+		{
+			name: "keeps sessions unless asked",
+			id:   userID,
+			setupMock: func(users *MockUserRepository, sessions *MockSessionRepository) {
+				users.On("FindByID", mock.Anything, userID.String()).Return(&model.User{Base: model.Base{ID: userID}, Password: "old-hash"}, nil)
+				users.On("Update", mock.Anything, mock.MatchedBy(newPassword)).Return(nil)
+			},
+		},
+		{
+			name: "unknown user",
+			id:   userID,
+			setupMock: func(users *MockUserRepository, sessions *MockSessionRepository) {
+				users.On("FindByID", mock.Anything, userID.String()).Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedErr: ErrUserNotFound,
+		},
+		{
+			name: "admin's own account",
+			id:   adminID,
+			setupMock: func(users *MockUserRepository, sessions *MockSessionRepository) {
+				users.On("FindByID", mock.Anything, adminID.String()).Return(&model.User{Base: model.Base{ID: adminID}, Role: "admin"}, nil)
+			},
+			expectedErr: ErrResetOwnPassword,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			mockSessions := new(MockSessionRepository)
+			tt.setupMock(mockRepo, mockSessions)
+			service := NewUserService(mockRepo,
+				WithPasswordCost(bcrypt.MinCost),
+				WithSessionRepository(mockSessions),
+			)
+			ctx := ContextWithActor(context.Background(), adminID.String())
+
+			err := service.AdminSetPassword(ctx, tt.id.String(), &AdminSetPasswordInput{Password: "N3w-Password!", RevokeSessions: tt.revokeSessions})
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+			mockSessions.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_ApproveUser_ConcurrentDecisionIsConflict(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo, WithApproval())
+	ctx := context.Background()
+
+	userID := uuid.New()
+	mockRepo.On("FindByID", ctx, userID.String()).Return(&model.User{Base: model.Base{ID: userID}, ApprovalStatus: model.ApprovalPending}, nil)
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*model.User")).Return(repository.ErrStaleVersion)
+
+	_, err := service.ApproveUser(ctx, userID.String())
+
+	assert.ErrorIs(t, err, ErrVersionConflict)
+}
+
+func TestUserService_ApprovalFlow(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockMailer := new(MockMailer)
+	service := NewUserService(mockRepo,
+		WithPasswordCost(bcrypt.MinCost),
+		WithUserTokens(new(MockUserTokenRepository), mockMailer),
+		WithApproval(),
+	)
+	ctx := context.Background()
+
+	var created *model.User
+	mockRepo.On("FindByEmail", ctx, "john@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindDeletedByEmail", ctx, "john@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*model.User")).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*model.User)
+		created.ID = uuid.New()
+	}).Return(nil)
+
+	result, err := service.Register(ctx, &CreateUserInput{Name: "John Doe", Email: "john@example.com", Password: "Password123!"})
+	assert.NoError(t, err)
+	assert.Equal(t, model.ApprovalPending, result.ApprovalStatus)
+
+	mockRepo.On("FindByID", ctx, created.ID.String()).Return(created, nil)
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(u *model.User) bool { return u.ApprovalStatus == model.ApprovalApproved })).Return(nil).Once()
+	mockMailer.On("Send", ctx, "john@example.com", "Your account has been approved", mock.Anything).Return(nil)
+
+	approved, err := service.ApproveUser(ctx, created.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, model.ApprovalApproved, approved.ApprovalStatus)
+	mockMailer.AssertExpectations(t)
+
+	_, err = service.RejectUser(ctx, created.ID.String())
+	assert.ErrorIs(t, err, ErrNotPendingApproval)
 }
\ No newline at end of file
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ariam/my-api/internal/model"
+	"github.com/ariam/my-api/internal/repository"
+	"github.com/ariam/my-api/pkg/mailer"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const defaultVerificationTTL = 24 * time.Hour
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token has expired")
+	ErrTokenUsed    = errors.New("token has already been used")
+
+	ErrTokensDisabled = errors.New("user tokens are not configured")
+
+	// ErrVerificationEmailNotSent is returned together with the created user
+	// when the account exists but its verification email could not be sent.
+	ErrVerificationEmailNotSent = errors.New("verification email could not be sent")
+)
+
+// WithUserTokens provides the token store and mailer used by the email
+// verification and password reset flows.
+func WithUserTokens(tokenRepo repository.UserTokenRepository, m mailer.Mailer) UserServiceOption {
+	return func(s *userService) {
+		s.tokenRepo = tokenRepo
+		s.mailer = m
+	}
+}
+
+// WithEmailVerification makes new signups inactive until the emailed link,
+// built from baseURL, is followed. It requires WithUserTokens. A ttl of zero
+// uses the default of 24 hours.
+func WithEmailVerification(baseURL string, ttl time.Duration) UserServiceOption {
+	return func(s *userService) {
+		s.requireVerification = true
+		s.baseURL = baseURL
+		if ttl > 0 {
+			s.verificationTTL = ttl
+		}
+	}
+}
+
+func (s *userService) verificationEnabled() bool {
+	return s.requireVerification && s.tokenRepo != nil
+}
+
+func (s *userService) GenerateVerificationToken(ctx context.Context, userID string) (string, error) {
+	if s.tokenRepo == nil {
+		return "", ErrTokensDisabled
+	}
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return "", ErrUserNotFound
+	}
+	return s.issueToken(ctx, id, model.TokenPurposeEmailVerification, s.verificationTTL)
+}
+
+func (s *userService) VerifyEmail(ctx context.Context, token string) error {
+	// redeeming the token and activating the user succeed or fail together, so
+	// a failed activation leaves the token usable
+	var user *model.User
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		userToken, err := s.redeemToken(ctx, model.TokenPurposeEmailVerification, token)
+		if err != nil {
+			return err
+		}
+
+		user, err = s.userRepo.FindByID(ctx, userToken.UserID.String())
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		now := time.Now()
+		user.IsActive = true
+		user.EmailVerifiedAt = &now
+		return s.userRepo.Update(ctx, user)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.userUpdated(ctx, user)
+	return nil
+}
+
+func (s *userService) sendVerificationEmail(ctx context.Context, user *model.User) error {
+	token, err := s.GenerateVerificationToken(ctx, user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	link := s.baseURL + "/api/v1/auth/verify?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening this link:\n\n%s\n", user.Name, link)
+	return s.mailer.Send(ctx, user.Email, "Verify your email address", body)
+}
+
+// issueToken stores the hash of a new random token and returns the token
+// itself, which is only ever sent to the user.
+func (s *userService) issueToken(ctx context.Context, userID uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	err := s.tokenRepo.Create(ctx, &model.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// redeemToken validates a token for purpose and consumes it.
+func (s *userService) redeemToken(ctx context.Context, purpose, token string) (*model.UserToken, error) {
+	if s.tokenRepo == nil || token == "" {
+		return nil, ErrInvalidToken
+	}
+
+	userToken, err := s.tokenRepo.FindByHash(ctx, purpose, hashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	if userToken.UsedAt != nil {
+		return nil, ErrTokenUsed
+	}
+	if time.Now().After(userToken.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+
+	consumed, err := s.tokenRepo.MarkUsed(ctx, userToken.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrTokenUsed
+	}
+	return userToken, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
\ No newline at end of file
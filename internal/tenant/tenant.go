@@ -0,0 +1,21 @@
+// Package tenant carries the tenant a request acts within, so data access
+// further down the call chain can be scoped to it.
+package tenant
+
+import "context"
+
+type key struct{}
+
+// WithID binds the tenant ID to ctx. An empty ID is a tenant of its own: it
+// scopes queries to records that belong to no tenant.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, key{}, id)
+}
+
+// FromContext returns the tenant ID bound by WithID. ok is false when none is
+// bound, as for internal callers such as seeds and the CLI, which are not
+// scoped.
+func FromContext(ctx context.Context) (id string, ok bool) {
+	id, ok = ctx.Value(key{}).(string)
+	return id, ok
+}
\ No newline at end of file
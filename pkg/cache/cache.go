@@ -0,0 +1,14 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores values under string keys for up to a TTL. Get returns nil,
+// nil for a missing or expired key.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
\ No newline at end of file
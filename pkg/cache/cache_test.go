@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaches(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	caches := map[string]Cache{
+		"lru":   NewLRU(10),
+		"redis": NewRedis(client, "test:"),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			value, err := c.Get(ctx, "missing")
+			require.NoError(t, err)
+			assert.Nil(t, value)
+
+			require.NoError(t, c.Set(ctx, "user:1", []byte("alice"), time.Minute))
+			value, err = c.Get(ctx, "user:1")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("alice"), value)
+
+			require.NoError(t, c.Delete(ctx, "user:1"))
+			value, err = c.Get(ctx, "user:1")
+			require.NoError(t, err)
+			assert.Nil(t, value)
+		})
+	}
+
+	assert.False(t, server.Exists("test:user:1"))
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU(2)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, c.Set(ctx, "b", []byte("2"), time.Minute))
+	_, _ = c.Get(ctx, "a")
+	require.NoError(t, c.Set(ctx, "c", []byte("3"), time.Minute))
+
+	b, _ := c.Get(ctx, "b")
+	assert.Nil(t, b, "b was least recently used")
+	a, _ := c.Get(ctx, "a")
+	assert.Equal(t, []byte("1"), a)
+}
+
+func TestLRU_Expires(t *testing.T) {
+	c := NewLRU(2)
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Millisecond))
+	time.Sleep(5 * time.Millisecond)
+
+	a, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Nil(t, a)
+}
\ No newline at end of file
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used
+	entries  map[string]*list.Element
+}
+
+// NewLRU keeps up to capacity entries in process memory, evicting the least
+// recently used when full. Entries are not shared between instances.
+func NewLRU(capacity int) Cache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &lruCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (c *lruCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	entry := elem.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.remove(elem)
+		return nil, nil
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.value, nil
+}
+
+func (c *lruCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(ttl)
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	if c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *lruCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	return nil
+}
+
+func (c *lruCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*lruEntry).key)
+}
\ No newline at end of file
@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type redisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedis stores entries in Redis under prefix, so they are shared by every
+// instance using the same server.
+func NewRedis(client *redis.Client, prefix string) Cache {
+	return &redisCache{client: client, prefix: prefix}
+}
+
+func (c *redisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+func (c *redisCache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.prefix+key).Err()
+}
\ No newline at end of file
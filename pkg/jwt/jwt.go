@@ -1,44 +1,160 @@
 package jwt
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 var (
-	ErrInvalidToken = errors.New("invalid token")
-	ErrExpiredToken = errors.New("token has expired")
+	ErrInvalidToken     = errors.New("invalid token")
+	ErrExpiredToken     = errors.New("token has expired")
+	ErrTokenNotValidYet = errors.New("token is not valid yet")
+	ErrReservedClaim    = errors.New("claim name is reserved")
 )
 
+// reservedClaims are set by the manager and can't be supplied as extra claims.
+var reservedClaims = map[string]bool{
+	"user_id": true, "email": true, "role": true,
+	"iss": true, "sub": true, "aud": true, "exp": true, "nbf": true, "iat": true, "jti": true,
+}
+
+// Claims are the token's fields. Extra holds any claims beyond the fixed
+// ones, such as those passed to Generate; it is flattened into the token's
+// top level alongside them.
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID string                 `json:"user_id"`
+	Email  string                 `json:"email"`
+	Role   string                 `json:"role"`
+	Extra  map[string]interface{} `json:"-"`
 	jwt.RegisteredClaims
 }
 
+// claimsFields has the fields of Claims without its JSON methods.
+type claimsFields Claims
+
+func (c Claims) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(claimsFields(c))
+	if err != nil || len(c.Extra) == 0 {
+		return data, err
+	}
+
+	var merged map[string]interface{}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	for name, value := range c.Extra {
+		if !reservedClaims[name] {
+			merged[name] = value
+		}
+	}
+	return json.Marshal(merged)
+}
+
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*claimsFields)(c)); err != nil {
+		return err
+	}
+
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	c.Extra = nil
+	for name, value := range all {
+		if reservedClaims[name] {
+			continue
+		}
+		if c.Extra == nil {
+			c.Extra = make(map[string]interface{})
+		}
+		c.Extra[name] = value
+	}
+	return nil
+}
+
 type JWTManager struct {
 	secret      string
 	expireHours int
+	leeway      time.Duration
 }
 
-func NewJWTManager(secret string, expireHours int) *JWTManager {
-	return &JWTManager{
+// Option configures a JWTManager.
+type Option func(*JWTManager)
+
+// WithLeeway tolerates clocks that differ by up to leeway between the
+// server that issued a token and the one validating it, when checking exp,
+// nbf and iat. The default is no leeway.
+func WithLeeway(leeway time.Duration) Option {
+	return func(m *JWTManager) {
+		m.leeway = leeway
+	}
+}
+
+func NewJWTManager(secret string, expireHours int, opts ...Option) *JWTManager {
+	m := &JWTManager{
 		secret:      secret,
 		expireHours: expireHours,
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (m *JWTManager) TTL() time.Duration {
+	return time.Hour * time.Duration(m.expireHours)
+}
+
+// Generate issues a token for the user. Any extra claims, such as a tenant ID,
+// are added to the token and returned in Claims.Extra by Validate; naming a
+// reserved claim fails with ErrReservedClaim.
+func (m *JWTManager) Generate(userID, email, role string, extra ...map[string]interface{}) (string, error) {
+	return m.GenerateWithID(uuid.NewString(), userID, email, role, extra...)
 }
 
-func (m *JWTManager) Generate(userID, email, role string) (string, error) {
+// GenerateWithTTL issues a token that expires after ttl instead of the
+// configured lifetime, e.g. for a "remember me" login.
+func (m *JWTManager) GenerateWithTTL(userID, email, role string, ttl time.Duration, extra ...map[string]interface{}) (string, error) {
+	return m.GenerateWithIDAndTTL(uuid.NewString(), userID, email, role, ttl, extra...)
+}
+
+// GenerateWithID issues a token whose jti claim is tokenID, so the token can
+// be tied to a server-side session and revoked.
+func (m *JWTManager) GenerateWithID(tokenID, userID, email, role string, extra ...map[string]interface{}) (string, error) {
+	return m.GenerateWithIDAndTTL(tokenID, userID, email, role, m.TTL(), extra...)
+}
+
+// GenerateWithIDAndTTL combines GenerateWithID and GenerateWithTTL.
+func (m *JWTManager) GenerateWithIDAndTTL(tokenID, userID, email, role string, ttl time.Duration, extra ...map[string]interface{}) (string, error) {
+	var merged map[string]interface{}
+	for _, claims := range extra {
+		for name, value := range claims {
+			if reservedClaims[name] {
+				return "", fmt.Errorf("%w: %s", ErrReservedClaim, name)
+			}
+			if merged == nil {
+				merged = make(map[string]interface{})
+			}
+			merged[name] = value
+		}
+	}
+
+	now := time.Now()
 	claims := &Claims{
 		UserID: userID,
 		Email:  email,
 		Role:   role,
+		Extra:  merged,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(m.expireHours))),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
@@ -46,18 +162,23 @@ func (m *JWTManager) Generate(userID, email, role string) (string, error) {
 	return token.SignedString([]byte(m.secret))
 }
 
+// Validate checks the token's signature and that, give or take the leeway,
+// it has not expired and its nbf and iat are not in the future.
 func (m *JWTManager) Validate(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, ErrInvalidToken
 		}
 		return []byte(m.secret), nil
-	})
+	}, jwt.WithLeeway(m.leeway), jwt.WithIssuedAt())
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, ErrExpiredToken
 		}
+		if errors.Is(err, jwt.ErrTokenNotValidYet) || errors.Is(err, jwt.ErrTokenUsedBeforeIssued) {
+			return nil, ErrTokenNotValidYet
+		}
 		return nil, ErrInvalidToken
 	}
 
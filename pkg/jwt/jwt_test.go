@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -65,4 +66,136 @@ func TestJWTManager_Validate_ExpiredToken(t *testing.T) {
 
 	assert.Error(t, err)
 	assert.Nil(t, claims)
+}
+
+func TestJWTManager_GenerateWithID(t *testing.T) {
+	manager := NewJWTManager("test-secret-key-min-32-characters", 24)
+
+	token, err := manager.GenerateWithID("session-1", "user-123", "test@example.com", "user")
+	assert.NoError(t, err)
+
+	claims, err := manager.Validate(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "session-1", claims.ID)
+	assert.Equal(t, "user-123", claims.UserID)
+}
+
+func TestJWTManager_GenerateWithTTL(t *testing.T) {
+	manager := NewJWTManager("test-secret-key-min-32-characters", 24)
+
+	short, err := manager.Generate("user-123", "test@example.com", "user")
+	assert.NoError(t, err)
+	long, err := manager.GenerateWithTTL("user-123", "test@example.com", "user", 30*24*time.Hour)
+	assert.NoError(t, err)
+
+	shortClaims, err := manager.Validate(short)
+	assert.NoError(t, err)
+	longClaims, err := manager.Validate(long)
+	assert.NoError(t, err)
+
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), shortClaims.ExpiresAt.Time, time.Minute)
+	assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), longClaims.ExpiresAt.Time, time.Minute)
+	assert.True(t, longClaims.ExpiresAt.After(shortClaims.ExpiresAt.Time))
+	assert.NotEqual(t, shortClaims.ID, longClaims.ID)
+}
+func TestJWTManager_ExtraClaims(t *testing.T) {
+	manager := NewJWTManager("test-secret-key-min-32-characters", 24)
+
+	token, err := manager.Generate("user-123", "test@example.com", "user", map[string]interface{}{
+		"tenant_id":   "tenant-a",
+		"permissions": []string{"users:read"},
+	})
+	assert.NoError(t, err)
+
+	claims, err := manager.Validate(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-123", claims.UserID)
+	assert.Equal(t, "tenant-a", claims.Extra["tenant_id"])
+	assert.Equal(t, []interface{}{"users:read"}, claims.Extra["permissions"])
+	assert.NotContains(t, claims.Extra, "user_id")
+	assert.NotContains(t, claims.Extra, "exp")
+}
+
+func TestJWTManager_ExtraClaims_Reserved(t *testing.T) {
+	manager := NewJWTManager("test-secret-key-min-32-characters", 24)
+
+	for _, name := range []string{"user_id", "role", "exp", "jti"} {
+		token, err := manager.Generate("user-123", "test@example.com", "user", map[string]interface{}{name: "override"})
+
+		assert.ErrorIs(t, err, ErrReservedClaim, name)
+		assert.Empty(t, token)
+	}
+
+	// claims built by hand still can't shadow the fixed fields
+	claims := Claims{UserID: "user-123", Role: "user", Extra: map[string]interface{}{"role": "admin"}}
+	data, err := claims.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"role":"user"`)
+	assert.NotContains(t, string(data), "admin")
+}
+
+func TestJWTManager_NoExtraClaims(t *testing.T) {
+	manager := NewJWTManager("test-secret-key-min-32-characters", 24)
+
+	token, _ := manager.Generate("user-123", "test@example.com", "user")
+	claims, err := manager.Validate(token)
+
+	assert.NoError(t, err)
+	assert.Nil(t, claims.Extra)
+}
+func TestJWTManager_Generate_SetsIssuedAtAndNotBefore(t *testing.T) {
+	manager := NewJWTManager("test-secret-key-min-32-characters", 24)
+
+	token, _ := manager.Generate("user-123", "test@example.com", "user")
+	claims, err := manager.Validate(token)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, claims.IssuedAt) && assert.NotNil(t, claims.NotBefore) {
+		assert.WithinDuration(t, time.Now(), claims.IssuedAt.Time, 2*time.Second)
+		assert.Equal(t, claims.IssuedAt, claims.NotBefore)
+	}
+}
+
+func TestJWTManager_Validate_NotBefore(t *testing.T) {
+	const secret = "test-secret-key-min-32-characters"
+	manager := NewJWTManager(secret, 24, WithLeeway(30*time.Second))
+
+	tests := []struct {
+		name      string
+		notBefore time.Duration
+		issuedAt  time.Duration
+		wantErr   bool
+	}{
+		{name: "nbf within the leeway", notBefore: 10 * time.Second, issuedAt: 10 * time.Second},
+		{name: "future-dated nbf", notBefore: time.Hour, wantErr: true},
+		{name: "future-dated iat", issuedAt: time.Hour, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// a token from a server whose clock runs ahead of ours
+			now := time.Now()
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+				UserID: "user-123",
+				RegisteredClaims: jwt.RegisteredClaims{
+					ExpiresAt: jwt.NewNumericDate(now.Add(2 * time.Hour)),
+					NotBefore: jwt.NewNumericDate(now.Add(tt.notBefore)),
+					IssuedAt:  jwt.NewNumericDate(now.Add(tt.issuedAt)),
+				},
+			}).SignedString([]byte(secret))
+			assert.NoError(t, err)
+
+			claims, err := manager.Validate(token)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrTokenNotValidYet)
+				assert.Nil(t, claims)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "user-123", claims.UserID)
+			}
+		})
+	}
 }
\ No newline at end of file
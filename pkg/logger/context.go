@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID, which
+// WithContext attaches to every entry logged for that request.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, or "" if none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithContext returns the logger with a request_id field when ctx carries a
+// request ID. Use it directly, e.g. logger.WithContext(ctx).Error(...).
+func WithContext(ctx context.Context) *zap.Logger {
+	// undo the caller skip added for the package-level helpers, since callers
+	// log through the returned logger directly
+	l := Get().WithOptions(zap.AddCallerSkip(-1))
+	if id := RequestIDFromContext(ctx); id != "" {
+		return l.With(zap.String("request_id", id))
+	}
+	return l
+}
\ No newline at end of file
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	t.Cleanup(Replace(zap.New(core)))
+	return logs
+}
+
+func TestWithContext_AddsRequestID(t *testing.T) {
+	logs := observeLogs(t)
+
+	ctx := ContextWithRequestID(context.Background(), "req-123")
+	WithContext(ctx).Info("handled")
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "req-123", entries[0].ContextMap()["request_id"])
+}
+
+func TestWithContext_WithoutRequestID(t *testing.T) {
+	logs := observeLogs(t)
+
+	WithContext(context.Background()).Info("handled")
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.NotContains(t, entries[0].ContextMap(), "request_id")
+}
\ No newline at end of file
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"fmt"
 	"os"
 	"sync"
 
@@ -11,29 +12,121 @@ import (
 var (
 	log  *zap.Logger
 	once sync.Once
+
+	// exit is swapped out by tests of Fatal.
+	exit = os.Exit
 )
 
-func Init(env string) {
-	once.Do(func() {
-		var config zap.Config
-
-		if env == "production" {
-			config = zap.NewProductionConfig()
-			config.EncoderConfig.TimeKey = "timestamp"
-			config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-		} else {
-			config = zap.NewDevelopmentConfig()
-			config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-		}
+// syncThenExit replaces zap's fatal hook, which exits without flushing, so
+// entries buffered before a Fatal are written out first.
+type syncThenExit struct{}
+
+func (syncThenExit) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	Sync()
+	exit(1)
+}
+
+type options struct {
+	level    string
+	sampling *bool
+	encoding string
+}
+
+// Option overrides a default that Init picks from the environment.
+type Option func(*options)
+
+// WithLevel sets the minimum level: debug, info, warn or error. An empty level
+// keeps the default, debug in development and info elsewhere.
+func WithLevel(level string) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithSampling turns sampling of repeated entries on or off. By default only
+// production samples.
+func WithSampling(enabled bool) Option {
+	return func(o *options) {
+		o.sampling = &enabled
+	}
+}
+
+// WithEncoding sets the output format, "json" or "console". An empty
+// encoding keeps the default, JSON in production and console elsewhere.
+func WithEncoding(encoding string) Option {
+	return func(o *options) {
+		o.encoding = encoding
+	}
+}
 
+func Init(env string, opts ...Option) {
+	once.Do(func() {
 		var err error
-		log, err = config.Build(zap.AddCallerSkip(1))
+		log, err = build(env, opts...)
 		if err != nil {
 			panic(err)
 		}
 	})
 }
 
+func build(env string, opts ...Option) (*zap.Logger, error) {
+	config, err := newConfig(env, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return config.Build(zap.AddCallerSkip(1), zap.WithFatalHook(syncThenExit{}))
+}
+
+func newConfig(env string, opts ...Option) (zap.Config, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var config zap.Config
+
+	if env == "production" {
+		config = zap.NewProductionConfig()
+		config.EncoderConfig = jsonEncoderConfig()
+	} else {
+		config = zap.NewDevelopmentConfig()
+		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
+
+	if o.level != "" {
+		level, err := zapcore.ParseLevel(o.level)
+		if err != nil {
+			return config, err
+		}
+		config.Level = zap.NewAtomicLevelAt(level)
+	}
+	if o.sampling != nil {
+		config.Sampling = nil
+		if *o.sampling {
+			config.Sampling = zap.NewProductionConfig().Sampling
+		}
+	}
+	switch o.encoding {
+	case "":
+	case "json":
+		config.Encoding = "json"
+		config.EncoderConfig = jsonEncoderConfig()
+	case "console":
+		config.Encoding = "console"
+	default:
+		return config, fmt.Errorf("unknown log encoding %q", o.encoding)
+	}
+
+	return config, nil
+}
+
+func jsonEncoderConfig() zapcore.EncoderConfig {
+	config := zap.NewProductionEncoderConfig()
+	config.TimeKey = "timestamp"
+	config.EncodeTime = zapcore.ISO8601TimeEncoder
+	return config
+}
+
 func Get() *zap.Logger {
 	if log == nil {
 		Init("development")
@@ -41,6 +134,14 @@ func Get() *zap.Logger {
 	return log
 }
 
+// Replace swaps the package logger, returning a func that restores the
+// previous one. It is meant for tests that assert on log output.
+func Replace(l *zap.Logger) (restore func()) {
+	previous := Get()
+	log = l
+	return func() { log = previous }
+}
+
 func Sync() {
 	if log != nil {
 		_ = log.Sync()
@@ -63,7 +164,8 @@ func Warn(msg string, fields ...zap.Field) {
 	Get().Warn(msg, fields...)
 }
 
+// Fatal logs at fatal level, flushes the logger and exits with status 1.
+// Deferred functions do not run.
 func Fatal(msg string, fields ...zap.Field) {
 	Get().Fatal(msg, fields...)
-	os.Exit(1)
 }
\ No newline at end of file
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestFatal_FlushesBeforeExit(t *testing.T) {
+	var out bytes.Buffer
+	buffered := &zapcore.BufferedWriteSyncer{WS: zapcore.AddSync(&out), FlushInterval: time.Hour}
+	t.Cleanup(func() { buffered.Stop() })
+
+	l, err := build("production")
+	require.NoError(t, err)
+	t.Cleanup(Replace(l.WithOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core {
+		return zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), buffered, zapcore.DebugLevel)
+	}))))
+
+	var code int
+	var written string
+	exit = func(c int) {
+		code = c
+		written = out.String()
+	}
+	t.Cleanup(func() { exit = os.Exit })
+
+	Info("starting")
+	assert.Empty(t, out.String(), "entries are buffered until a sync")
+
+	Fatal("cannot continue")
+
+	assert.Equal(t, 1, code)
+	assert.Contains(t, written, "starting")
+	assert.Contains(t, written, "cannot continue")
+}
+func TestBuild_Level(t *testing.T) {
+	tests := []struct {
+		env      string
+		level    string
+		expected zapcore.Level
+	}{
+		{env: "development", expected: zapcore.DebugLevel},
+		{env: "production", expected: zapcore.InfoLevel},
+		{env: "production", level: "debug", expected: zapcore.DebugLevel},
+		{env: "development", level: "info", expected: zapcore.InfoLevel},
+		{env: "development", level: "warn", expected: zapcore.WarnLevel},
+		{env: "production", level: "error", expected: zapcore.ErrorLevel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env+"/"+tt.level, func(t *testing.T) {
+			l, err := build(tt.env, WithLevel(tt.level))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, l.Level())
+			assert.True(t, l.Core().Enabled(tt.expected))
+			if tt.expected > zapcore.DebugLevel {
+				assert.False(t, l.Core().Enabled(tt.expected-1))
+			}
+		})
+	}
+
+	_, err := build("production", WithLevel("verbose"))
+	assert.Error(t, err)
+}
+
+func TestNewConfig_SamplingAndEncoding(t *testing.T) {
+	config, err := newConfig("production")
+	require.NoError(t, err)
+	assert.NotNil(t, config.Sampling, "production samples by default")
+	assert.Equal(t, "json", config.Encoding)
+
+	config, err = newConfig("production", WithSampling(false))
+	require.NoError(t, err)
+	assert.Nil(t, config.Sampling)
+
+	config, err = newConfig("development")
+	require.NoError(t, err)
+	assert.Nil(t, config.Sampling)
+	assert.Equal(t, "console", config.Encoding)
+
+	config, err = newConfig("development", WithSampling(true), WithEncoding("json"))
+	require.NoError(t, err)
+	assert.NotNil(t, config.Sampling)
+	assert.Equal(t, "json", config.Encoding)
+	assert.Equal(t, "timestamp", config.EncoderConfig.TimeKey)
+
+	_, err = newConfig("development", WithEncoding("xml"))
+	assert.Error(t, err)
+}
\ No newline at end of file
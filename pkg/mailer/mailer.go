@@ -0,0 +1,28 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/ariam/my-api/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+type noopMailer struct{}
+
+// NewNoop returns a Mailer that delivers nothing. Only the recipient and
+// subject are logged: bodies carry verification and reset tokens.
+func NewNoop() Mailer {
+	return noopMailer{}
+}
+
+func (noopMailer) Send(ctx context.Context, to, subject, body string) error {
+	logger.Debug("Mail not sent (no mailer configured)",
+		zap.String("to", to),
+		zap.String("subject", subject),
+	)
+	return nil
+}
\ No newline at end of file
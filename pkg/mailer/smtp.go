@@ -0,0 +1,58 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTP returns a Mailer that delivers through the SMTP server at host:port,
+// upgrading to TLS when the server offers it. Without a username it sends
+// unauthenticated.
+func NewSMTP(host, port, username, password, from string) Mailer {
+	m := &smtpMailer{addr: net.JoinHostPort(host, port), from: from}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m
+}
+
+func (m *smtpMailer) Send(ctx context.Context, to, subject, body string) error {
+	msg, err := buildMessage(m.from, to, subject, body)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{to}, msg)
+}
+
+// buildMessage formats a plain-text message. Header values containing line
+// breaks are rejected so a crafted name or address cannot inject headers.
+func buildMessage(from, to, subject, body string) ([]byte, error) {
+	for _, v := range []string{from, to, subject} {
+		if strings.ContainsAny(v, "\r\n") {
+			return nil, errors.New("mailer: header value contains a line break")
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String()), nil
+}
\ No newline at end of file
@@ -0,0 +1,29 @@
+package mailer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMessage(t *testing.T) {
+	msg, err := buildMessage("noreply@example.com", "john@example.com", "Verify your email address", "Hi John,\n\nOpen this link.\n")
+	require.NoError(t, err)
+
+	assert.Equal(t, "From: noreply@example.com\r\n"+
+		"To: john@example.com\r\n"+
+		"Subject: Verify your email address\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n"+
+		"\r\n"+
+		"Hi John,\r\n\r\nOpen this link.\r\n", string(msg))
+}
+
+func TestBuildMessage_RejectsHeaderInjection(t *testing.T) {
+	_, err := buildMessage("noreply@example.com", "john@example.com\r\nBcc: eve@example.com", "Hello", "body")
+	assert.Error(t, err)
+
+	_, err = buildMessage("noreply@example.com", "john@example.com", "Hello\nBcc: eve@example.com", "body")
+	assert.Error(t, err)
+}
\ No newline at end of file
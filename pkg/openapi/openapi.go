@@ -0,0 +1,252 @@
+// Package openapi validates JSON request bodies against the Swagger 2.0
+// document swag generates from the handler annotations. It understands the
+// subset of JSON Schema swag emits for request bodies: $ref, type,
+// properties, required, items, enum and length and range bounds.
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ariam/my-api/pkg/validator"
+)
+
+// Schema is a Swagger 2.0 schema object.
+type Schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Properties map[string]*Schema `json:"properties"`
+	Required   []string           `json:"required"`
+	Items      *Schema            `json:"items"`
+	Enum       []interface{}      `json:"enum"`
+	MinLength  *int               `json:"minLength"`
+	MaxLength  *int               `json:"maxLength"`
+	Minimum    *float64           `json:"minimum"`
+	Maximum    *float64           `json:"maximum"`
+
+	// AdditionalProperties is absent, true or a schema. Objects without it
+	// reject properties they don't declare.
+	AdditionalProperties json.RawMessage `json:"additionalProperties"`
+}
+
+type document struct {
+	BasePath    string                          `json:"basePath"`
+	Paths       map[string]map[string]operation `json:"paths"`
+	Definitions map[string]*Schema              `json:"definitions"`
+}
+
+type operation struct {
+	Parameters []struct {
+		In     string  `json:"in"`
+		Schema *Schema `json:"schema"`
+	} `json:"parameters"`
+}
+
+// Spec holds the body schema of every operation in a document.
+type Spec struct {
+	basePath    string
+	definitions map[string]*Schema
+	bodies      map[string]*Schema
+}
+
+// Parse reads a Swagger 2.0 JSON document.
+func Parse(doc []byte) (*Spec, error) {
+	var d document
+	if err := json.Unmarshal(doc, &d); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+
+	spec := &Spec{
+		basePath:    strings.TrimRight(d.BasePath, "/"),
+		definitions: d.Definitions,
+		bodies:      make(map[string]*Schema),
+	}
+	for path, ops := range d.Paths {
+		for method, op := range ops {
+			for _, p := range op.Parameters {
+				if p.In == "body" && p.Schema != nil {
+					spec.bodies[strings.ToUpper(method)+" "+path] = p.Schema
+				}
+			}
+		}
+	}
+	return spec, nil
+}
+
+// RequestBody returns the body schema of the operation for method and a
+// Fiber route path such as /api/v1/users/:id.
+func (s *Spec) RequestBody(method, routePath string) (*Schema, bool) {
+	path := strings.TrimPrefix(routePath, s.basePath)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") {
+			segments[i] = "{" + strings.TrimSuffix(seg[1:], "?") + "}"
+		}
+	}
+
+	schema, ok := s.bodies[method+" /"+strings.Join(segments, "/")]
+	return schema, ok
+}
+
+// Validate checks body against schema and returns the mismatches, naming each
+// field by its JSON path, e.g. "items[0].email". The error is set only when
+// body is not valid JSON.
+func (s *Spec) Validate(schema *Schema, body []byte) ([]validator.ErrorResponse, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+
+	var errs []validator.ErrorResponse
+	s.validate(schema, value, "", &errs)
+	return errs, nil
+}
+
+func (s *Spec) validate(schema *Schema, value interface{}, field string, errs *[]validator.ErrorResponse) {
+	schema = s.resolve(schema)
+	if schema == nil {
+		return
+	}
+
+	fail := func(tag, format string, args ...interface{}) {
+		name := field
+		if name == "" {
+			name = "body"
+		}
+		*errs = append(*errs, validator.ErrorResponse{
+			Field:   name,
+			Tag:     tag,
+			Message: name + " " + fmt.Sprintf(format, args...),
+		})
+	}
+
+	if schema.Type != "" && !hasType(value, schema.Type) {
+		fail("type", "must be %s", article(schema.Type))
+		return
+	}
+	if len(schema.Enum) > 0 && !inEnum(value, schema.Enum) {
+		fail("enum", "must be one of %s", joinEnum(schema.Enum))
+	}
+
+	switch v := value.(type) {
+	case string:
+		n := utf8.RuneCountInString(v)
+		if schema.MinLength != nil && n < *schema.MinLength {
+			fail("minLength", "must be at least %d characters", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && n > *schema.MaxLength {
+			fail("maxLength", "must be at most %d characters", *schema.MaxLength)
+		}
+	case json.Number:
+		f, _ := v.Float64()
+		if schema.Minimum != nil && f < *schema.Minimum {
+			fail("minimum", "must be at least %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && f > *schema.Maximum {
+			fail("maximum", "must be at most %v", *schema.Maximum)
+		}
+	case []interface{}:
+		for i, item := range v {
+			s.validate(schema.Items, item, fmt.Sprintf("%s[%d]", field, i), errs)
+		}
+	case map[string]interface{}:
+		s.validateObject(schema, v, field, errs)
+	}
+}
+
+func (s *Spec) validateObject(schema *Schema, obj map[string]interface{}, field string, errs *[]validator.ErrorResponse) {
+	prefix := field
+	if prefix != "" {
+		prefix += "."
+	}
+
+	for _, name := range schema.Required {
+		if v, ok := obj[name]; !ok || v == nil {
+			*errs = append(*errs, validator.ErrorResponse{Field: prefix + name, Tag: "required", Message: prefix + name + " is required"})
+		}
+	}
+
+	var additional *Schema
+	// an object without declared properties is free-form
+	allowAdditional := schema.Properties == nil
+	if raw := schema.AdditionalProperties; len(raw) > 0 && string(raw) != "false" {
+		allowAdditional = true
+		_ = json.Unmarshal(raw, &additional)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(obj)) {
+		v := obj[name]
+		prop, declared := schema.Properties[name]
+		switch {
+		case declared:
+			// null leaves an optional field unset
+			if v != nil {
+				s.validate(prop, v, prefix+name, errs)
+			}
+		case allowAdditional:
+			s.validate(additional, v, prefix+name, errs)
+		default:
+			*errs = append(*errs, validator.UnknownField(prefix+name))
+		}
+	}
+}
+
+// resolve follows a local $ref such as #/definitions/service.LoginInput.
+func (s *Spec) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = s.definitions[strings.TrimPrefix(schema.Ref, "#/definitions/")]
+	}
+	return schema
+}
+
+func hasType(value interface{}, typ string) bool {
+	switch v := value.(type) {
+	case string:
+		return typ == "string"
+	case bool:
+		return typ == "boolean"
+	case json.Number:
+		if typ == "integer" {
+			_, err := v.Int64()
+			return err == nil
+		}
+		return typ == "number"
+	case []interface{}:
+		return typ == "array"
+	case map[string]interface{}:
+		return typ == "object"
+	}
+	return false
+}
+
+func article(typ string) string {
+	switch typ {
+	case "array", "integer", "object":
+		return "an " + typ
+	}
+	return "a " + typ
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinEnum(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprint(e)
+	}
+	return strings.Join(values, ", ")
+}
\ No newline at end of file
@@ -0,0 +1,101 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDoc = `{
+	"basePath": "/api/v1",
+	"paths": {
+		"/users/{id}": {
+			"put": {
+				"parameters": [
+					{"in": "path", "name": "id", "type": "string"},
+					{"in": "body", "name": "request", "schema": {"$ref": "#/definitions/UserInput"}}
+				]
+			}
+		},
+		"/users/bulk": {
+			"post": {
+				"parameters": [
+					{"in": "body", "name": "request", "schema": {"type": "array", "items": {"$ref": "#/definitions/UserInput"}}}
+				]
+			}
+		}
+	},
+	"definitions": {
+		"UserInput": {
+			"type": "object",
+			"required": ["name"],
+			"properties": {
+				"name": {"type": "string", "minLength": 2},
+				"role": {"type": "string", "enum": ["user", "admin"]},
+				"version": {"type": "integer", "minimum": 1},
+				"labels": {"type": "object", "additionalProperties": {"type": "string"}}
+			}
+		}
+	}
+}`
+
+func TestSpec_RequestBody(t *testing.T) {
+	spec, err := Parse([]byte(testDoc))
+	require.NoError(t, err)
+
+	_, ok := spec.RequestBody("PUT", "/api/v1/users/:id")
+	assert.True(t, ok)
+	_, ok = spec.RequestBody("POST", "/api/v1/users/bulk/")
+	assert.True(t, ok, "a trailing slash still matches")
+	_, ok = spec.RequestBody("GET", "/api/v1/users/:id")
+	assert.False(t, ok)
+}
+
+func TestSpec_Validate(t *testing.T) {
+	spec, err := Parse([]byte(testDoc))
+	require.NoError(t, err)
+	user, _ := spec.RequestBody("PUT", "/api/v1/users/:id")
+	bulk, _ := spec.RequestBody("POST", "/api/v1/users/bulk")
+
+	tests := []struct {
+		name           string
+		schema         *Schema
+		body           string
+		expectedFields []string
+		expectedTags   []string
+	}{
+		{name: "valid", schema: user, body: `{"name":"Jane","role":"admin","version":2,"labels":{"team":"ops"}}`},
+		{name: "optional null", schema: user, body: `{"name":"Jane","role":null}`},
+		{name: "wrong type", schema: user, body: `{"name":123}`, expectedFields: []string{"name"}, expectedTags: []string{"type"}},
+		{name: "fractional integer", schema: user, body: `{"name":"Jane","version":1.5}`, expectedFields: []string{"version"}, expectedTags: []string{"type"}},
+		{name: "below minimum", schema: user, body: `{"name":"Jane","version":0}`, expectedFields: []string{"version"}, expectedTags: []string{"minimum"}},
+		{name: "undeclared field", schema: user, body: `{"name":"Jane","is_admin":true}`, expectedFields: []string{"is_admin"}, expectedTags: []string{"unknown"}},
+		{name: "missing required", schema: user, body: `{"role":"user"}`, expectedFields: []string{"name"}, expectedTags: []string{"required"}},
+		{name: "not in enum", schema: user, body: `{"name":"Jane","role":"root"}`, expectedFields: []string{"role"}, expectedTags: []string{"enum"}},
+		{name: "too short", schema: user, body: `{"name":"J"}`, expectedFields: []string{"name"}, expectedTags: []string{"minLength"}},
+		{name: "additional property of wrong type", schema: user, body: `{"name":"Jane","labels":{"team":1}}`, expectedFields: []string{"labels.team"}, expectedTags: []string{"type"}},
+		{name: "array item", schema: bulk, body: `[{"name":"Jane"},{"name":false}]`, expectedFields: []string{"[1].name"}, expectedTags: []string{"type"}},
+		{name: "object instead of array", schema: bulk, body: `{"name":"Jane"}`, expectedFields: []string{"body"}, expectedTags: []string{"type"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := spec.Validate(tt.schema, []byte(tt.body))
+			require.NoError(t, err)
+
+			var fields, tags []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+				tags = append(tags, e.Tag)
+			}
+			assert.Equal(t, tt.expectedFields, fields)
+			assert.Equal(t, tt.expectedTags, tags)
+		})
+	}
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := spec.Validate(user, []byte(`{"name":`))
+		assert.Error(t, err)
+	})
+}
\ No newline at end of file
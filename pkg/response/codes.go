@@ -0,0 +1,60 @@
+package response
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Error codes are stable identifiers clients can branch on; the message sent
+// alongside is for humans and may change. Errors without a specific code get
+// one derived from the status, e.g. NOT_FOUND or TOO_MANY_REQUESTS.
+const (
+	CodeValidationFailed = "VALIDATION_FAILED"
+
+	CodeUserNotFound        = "USER_NOT_FOUND"
+	CodeEmailExists         = "EMAIL_EXISTS"
+	CodeEmailDeletedAccount = "EMAIL_DELETED_ACCOUNT"
+	CodeInvalidRole         = "INVALID_ROLE"
+	CodeLastAdmin           = "LAST_ADMIN"
+	CodeNotPendingApproval  = "NOT_PENDING_APPROVAL"
+	CodeInvalidCursor       = "INVALID_CURSOR"
+	CodeInvalidBulkPayload  = "INVALID_BULK_PAYLOAD"
+	CodeBulkImportTooLarge  = "BULK_IMPORT_TOO_LARGE"
+	CodeVersionConflict     = "VERSION_CONFLICT"
+	CodeResetOwnPassword    = "RESET_OWN_PASSWORD"
+
+	CodeInvalidCredentials  = "INVALID_CREDENTIALS"
+	CodeEmailNotVerified    = "EMAIL_NOT_VERIFIED"
+	CodePendingApproval     = "PENDING_APPROVAL"
+	CodeAccountRejected     = "ACCOUNT_REJECTED"
+	CodeSessionLimitReached = "SESSION_LIMIT_REACHED"
+	CodeSessionNotFound     = "SESSION_NOT_FOUND"
+
+	CodeMaintenance = "MAINTENANCE"
+
+	CodeInvalidToken = "INVALID_TOKEN"
+	CodeTokenExpired = "TOKEN_EXPIRED"
+	CodeTokenUsed    = "TOKEN_USED"
+)
+
+// APIError is an error with a client-facing status and code. Handlers may
+// return one instead of writing the response; the app's error handler
+// renders it with ErrorCode.
+type APIError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// defaultCode derives a code from the status text, e.g. 404 -> NOT_FOUND.
+func defaultCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "ERROR"
+	}
+	return strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+}
\ No newline at end of file
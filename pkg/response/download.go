@@ -0,0 +1,52 @@
+package response
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+
+	"github.com/ariam/my-api/pkg/logger"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Download streams an attachment produced by write. The body is never buffered
+// in full and is gzip-compressed whenever the client accepts gzip, independent
+// of any size threshold used by general response compression.
+//
+// write runs after the handler has returned, so it must not touch c; load
+// anything request-scoped before calling Download. The status is already sent
+// by then, so errors from write can only be logged.
+func Download(c *fiber.Ctx, contentType, filename string, write func(w io.Writer) error) error {
+	c.Attachment(filename)
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Vary(fiber.HeaderAcceptEncoding)
+
+	// AcceptsEncodings treats a missing header as accepting anything
+	compress := c.Get(fiber.HeaderAcceptEncoding) != "" && c.AcceptsEncodings("gzip") == "gzip"
+	if compress {
+		c.Set(fiber.HeaderContentEncoding, "gzip")
+	}
+
+	c.Context().SetBodyStreamWriter(func(bw *bufio.Writer) {
+		var w io.Writer = bw
+		var zw *gzip.Writer
+		if compress {
+			zw = gzip.NewWriter(bw)
+			w = zw
+		}
+
+		if err := write(w); err != nil {
+			logger.Error("Download stream failed", zap.String("filename", filename), zap.Error(err))
+		}
+
+		if zw != nil {
+			if err := zw.Close(); err != nil {
+				logger.Error("Failed to finish gzip stream", zap.String("filename", filename), zap.Error(err))
+			}
+		}
+		_ = bw.Flush()
+	})
+
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,68 @@
+package response
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownload(t *testing.T) {
+	const expectedCSV = "id,name\n1,Alice\n2,Bob\n"
+
+	app := fiber.New()
+	app.Get("/export", func(c *fiber.Ctx) error {
+		return Download(c, "text/csv", "users.csv", func(w io.Writer) error {
+			cw := csv.NewWriter(w)
+			_ = cw.Write([]string{"id", "name"})
+			_ = cw.Write([]string{"1", "Alice"})
+			_ = cw.Write([]string{"2", "Bob"})
+			cw.Flush()
+			return cw.Error()
+		})
+	})
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		expectGzip     bool
+	}{
+		{name: "gzip when accepted", acceptEncoding: "gzip, deflate", expectGzip: true},
+		{name: "plain when gzip is not accepted", acceptEncoding: "", expectGzip: false},
+		{name: "plain when gzip is refused", acceptEncoding: "gzip;q=0, identity", expectGzip: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/export", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+			assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+			assert.Contains(t, resp.Header.Get("Content-Disposition"), "users.csv")
+
+			var body io.Reader = resp.Body
+			if tt.expectGzip {
+				assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+				zr, err := gzip.NewReader(resp.Body)
+				require.NoError(t, err)
+				body = zr
+			} else {
+				assert.Empty(t, resp.Header.Get("Content-Encoding"))
+			}
+
+			data, err := io.ReadAll(body)
+			require.NoError(t, err)
+			assert.Equal(t, expectedCSV, string(data))
+		})
+	}
+}
\ No newline at end of file
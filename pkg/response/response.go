@@ -1,20 +1,68 @@
 package response
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// ValidationFormatHeader lets a client opt in to validation errors keyed by
+// field name by sending "map"; without it the flat list is returned.
+const ValidationFormatHeader = "X-Validation-Format"
+
+// TraceIDLocal is the fiber.Ctx local error responses read the trace ID from.
+// middleware.Tracing sets it when tracing is enabled; otherwise it is unset
+// and responses carry no trace ID.
+const TraceIDLocal = "trace_id"
+
+// Pagination headers repeat the page metadata of Paginated and
+// PaginatedWithLinks responses for clients that read it from headers.
+const (
+	HeaderTotalCount = "X-Total-Count"
+	HeaderPage       = "X-Page"
+	HeaderPerPage    = "X-Per-Page"
+	HeaderTotalPages = "X-Total-Pages"
+)
+
+// PaginationHeaders lists the pagination headers, for CORS to expose.
+var PaginationHeaders = []string{HeaderTotalCount, HeaderPage, HeaderPerPage, HeaderTotalPages}
 
 type Response struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   interface{} `json:"error,omitempty"`
+	Success   bool        `json:"success"`
+	Message   string      `json:"message,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+	Error     interface{} `json:"error,omitempty"`
+	Code      string      `json:"code,omitempty"`
+	Warnings  []string    `json:"warnings,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+	TraceID   string      `json:"trace_id,omitempty"`
 }
 
 type PaginatedData struct {
+	Items      interface{}      `json:"items"`
+	Total      int64            `json:"total"`
+	Page       int              `json:"page"`
+	PerPage    int              `json:"per_page"`
+	TotalPages int              `json:"total_pages"`
+	Links      *PaginationLinks `json:"links,omitempty"`
+}
+
+// PaginationLinks are relative URLs for moving through a paginated list.
+// Next and Prev are null at the ends of the list.
+type PaginationLinks struct {
+	Self  string  `json:"self"`
+	First string  `json:"first"`
+	Last  string  `json:"last"`
+	Next  *string `json:"next"`
+	Prev  *string `json:"prev"`
+}
+
+type CursorData struct {
 	Items      interface{} `json:"items"`
-	Total      int64       `json:"total"`
-	Page       int         `json:"page"`
-	PerPage    int         `json:"per_page"`
-	TotalPages int         `json:"total_pages"`
+	Limit      int         `json:"limit"`
+	NextCursor string      `json:"next_cursor"`
 }
 
 func Success(c *fiber.Ctx, data interface{}) error {
@@ -39,15 +87,67 @@ func Created(c *fiber.Ctx, data interface{}) error {
 	})
 }
 
+// CreatedAt is Created with a Location header pointing at the new resource.
+func CreatedAt(c *fiber.Ctx, location string, data interface{}) error {
+	c.Location(location)
+	return Created(c, data)
+}
+
+// SuccessWithWarnings reports a successful operation that completed with
+// non-fatal caveats the client may want to surface.
+func SuccessWithWarnings(c *fiber.Ctx, data interface{}, warnings ...string) error {
+	return c.JSON(Response{
+		Success:  true,
+		Data:     data,
+		Warnings: warnings,
+	})
+}
+
+func CreatedWithWarnings(c *fiber.Ctx, data interface{}, warnings ...string) error {
+	return c.Status(fiber.StatusCreated).JSON(Response{
+		Success:  true,
+		Data:     data,
+		Warnings: warnings,
+	})
+}
+
 func NoContent(c *fiber.Ctx) error {
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+// Error responds with message and a code derived from statusCode. Use
+// ErrorCode when the client needs to tell this error apart from others with
+// the same status.
 func Error(c *fiber.Ctx, statusCode int, message string) error {
-	return c.Status(statusCode).JSON(Response{
-		Success: false,
-		Error:   message,
-	})
+	return ErrorCode(c, statusCode, defaultCode(statusCode), message)
+}
+
+func ErrorCode(c *fiber.Ctx, statusCode int, code, message string) error {
+	return c.Status(statusCode).JSON(failure(c, Response{
+		Error: message,
+		Code:  code,
+	}))
+}
+
+func ErrorWithData(c *fiber.Ctx, statusCode int, message string, data interface{}) error {
+	return ErrorCodeWithData(c, statusCode, defaultCode(statusCode), message, data)
+}
+
+func ErrorCodeWithData(c *fiber.Ctx, statusCode int, code, message string, data interface{}) error {
+	return c.Status(statusCode).JSON(failure(c, Response{
+		Data:  data,
+		Error: message,
+		Code:  code,
+	}))
+}
+
+// failure marks resp as an error and adds the IDs a client needs to correlate
+// it with server logs: the X-Request-ID of this request and its trace ID.
+func failure(c *fiber.Ctx, resp Response) Response {
+	resp.Success = false
+	resp.RequestID = c.GetRespHeader(fiber.HeaderXRequestID)
+	resp.TraceID, _ = c.Locals(TraceIDLocal).(string)
+	return resp
 }
 
 func BadRequest(c *fiber.Ctx, message string) error {
@@ -71,26 +171,141 @@ func InternalServerError(c *fiber.Ctx, message string) error {
 }
 
 func ValidationError(c *fiber.Ctx, errors interface{}) error {
-	return c.Status(fiber.StatusUnprocessableEntity).JSON(Response{
-		Success: false,
-		Error:   errors,
-	})
+	return c.Status(fiber.StatusUnprocessableEntity).JSON(failure(c, Response{
+		Error: errors,
+		Code:  CodeValidationFailed,
+	}))
+}
+
+// ValidationErrorMap is ValidationError with errors keyed by field name, e.g.
+// {"email": ["email is required"]}.
+func ValidationErrorMap(c *fiber.Ctx, errors map[string][]string) error {
+	return ValidationError(c, errors)
 }
 
 func Paginated(c *fiber.Ctx, items interface{}, total int64, page, perPage int) error {
-	totalPages := int(total) / perPage
-	if int(total)%perPage > 0 {
-		totalPages++
+	data := paginate(items, total, page, perPage)
+	setPaginationHeaders(c, data)
+	return c.JSON(Response{
+		Success: true,
+		Data:    data,
+	})
+}
+
+// PaginatedWithLinks is Paginated plus navigation links, built from the
+// request's path and query with only page and per_page changed.
+func PaginatedWithLinks(c *fiber.Ctx, items interface{}, total int64, page, perPage int) error {
+	data := paginate(items, total, page, perPage)
+
+	pageURL := func(n int) string {
+		args := fasthttp.AcquireArgs()
+		defer fasthttp.ReleaseArgs(args)
+		c.Context().QueryArgs().CopyTo(args)
+		args.SetUint("page", n)
+		args.SetUint("per_page", perPage)
+		return c.Path() + "?" + args.String()
 	}
 
+	last := data.TotalPages
+	if last < 1 {
+		last = 1
+	}
+	links := &PaginationLinks{
+		Self:  pageURL(page),
+		First: pageURL(1),
+		Last:  pageURL(last),
+	}
+	if page < last {
+		next := pageURL(page + 1)
+		links.Next = &next
+	}
+	if page > 1 {
+		prev := pageURL(min(page-1, last))
+		links.Prev = &prev
+	}
+	data.Links = links
+
+	setPaginationHeaders(c, data)
 	return c.JSON(Response{
 		Success: true,
-		Data: PaginatedData{
+		Data:    data,
+	})
+}
+
+// paginate reports zero pages for an empty list or a non-positive perPage,
+// which would otherwise divide by zero.
+func paginate(items interface{}, total int64, page, perPage int) PaginatedData {
+	totalPages := 0
+	if perPage > 0 && total > 0 {
+		totalPages = int((total + int64(perPage) - 1) / int64(perPage))
+	}
+
+	return PaginatedData{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: totalPages,
+	}
+}
+
+func setPaginationHeaders(c *fiber.Ctx, data PaginatedData) {
+	c.Set(HeaderTotalCount, strconv.FormatInt(data.Total, 10))
+	c.Set(HeaderPage, strconv.Itoa(data.Page))
+	c.Set(HeaderPerPage, strconv.Itoa(data.PerPage))
+	c.Set(HeaderTotalPages, strconv.Itoa(data.TotalPages))
+}
+
+func CursorPaginated(c *fiber.Ctx, items interface{}, nextCursor string, limit int) error {
+	return c.JSON(Response{
+		Success: true,
+		Data: CursorData{
 			Items:      items,
-			Total:      total,
-			Page:       page,
-			PerPage:    perPage,
-			TotalPages: totalPages,
+			Limit:      limit,
+			NextCursor: nextCursor,
 		},
 	})
+}
+
+// Project serializes data to JSON and keeps only the listed top-level keys.
+func Project(data interface{}, fields []string) (map[string]interface{}, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var full map[string]interface{}
+	if err := json.Unmarshal(raw, &full); err != nil {
+		return nil, err
+	}
+
+	projected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if value, ok := full[field]; ok {
+			projected[field] = value
+		}
+	}
+
+	return projected, nil
+}
+
+// ProjectEach applies Project to every element of the slice items.
+func ProjectEach(items interface{}, fields []string) ([]map[string]interface{}, error) {
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []json.RawMessage
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return nil, err
+	}
+
+	projected := make([]map[string]interface{}, len(all))
+	for i, item := range all {
+		if projected[i], err = Project(item, fields); err != nil {
+			return nil, err
+		}
+	}
+	return projected, nil
 }
\ No newline at end of file
@@ -0,0 +1,347 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarnings(t *testing.T) {
+	tests := []struct {
+		name             string
+		handler          fiber.Handler
+		expectedStatus   int
+		expectedWarnings []string
+	}{
+		{
+			name: "created with warning",
+			handler: func(c *fiber.Ctx) error {
+				return CreatedWithWarnings(c, fiber.Map{"id": "1"}, "verification email could not be sent")
+			},
+			expectedStatus:   fiber.StatusCreated,
+			expectedWarnings: []string{"verification email could not be sent"},
+		},
+		{
+			name: "success with warning",
+			handler: func(c *fiber.Ctx) error {
+				return SuccessWithWarnings(c, nil, "partial result")
+			},
+			expectedStatus:   fiber.StatusOK,
+			expectedWarnings: []string{"partial result"},
+		},
+		{
+			name: "no warnings are omitted",
+			handler: func(c *fiber.Ctx) error {
+				return Success(c, fiber.Map{"id": "1"})
+			},
+			expectedStatus:   fiber.StatusOK,
+			expectedWarnings: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", tt.handler)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var body map[string]interface{}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.True(t, body["success"].(bool))
+			if tt.expectedWarnings == nil {
+				assert.NotContains(t, body, "warnings")
+				return
+			}
+			assert.ElementsMatch(t, tt.expectedWarnings, body["warnings"])
+		})
+	}
+}
+
+func TestValidationErrorMap(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", func(c *fiber.Ctx) error {
+		return ValidationErrorMap(c, map[string][]string{"email": {"email is required", "email must be a valid email"}})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+	var body struct {
+		Success bool                `json:"success"`
+		Error   map[string][]string `json:"error"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.False(t, body.Success)
+	assert.Equal(t, []string{"email is required", "email must be a valid email"}, body.Error["email"])
+}
+
+func TestError_IncludesRequestID(t *testing.T) {
+	tests := []struct {
+		name           string
+		handler        fiber.Handler
+		expectedStatus int
+	}{
+		{
+			name:           "not found",
+			handler:        func(c *fiber.Ctx) error { return NotFound(c, "User not found") },
+			expectedStatus: fiber.StatusNotFound,
+		},
+		{
+			name:           "internal server error",
+			handler:        func(c *fiber.Ctx) error { return InternalServerError(c, "Failed to fetch user") },
+			expectedStatus: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(requestid.New())
+			app.Get("/", tt.handler)
+
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set(fiber.HeaderXRequestID, "req-123")
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			var body map[string]interface{}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.False(t, body["success"].(bool))
+			assert.Equal(t, "req-123", body["request_id"])
+			assert.NotContains(t, body, "trace_id")
+		})
+	}
+}
+
+func TestError_IncludesTraceID(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		c.Locals(TraceIDLocal, "4bf92f3577b34da6a3ce929d0e0e4736")
+		return BadRequest(c, "Invalid request body")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	assert.NoError(t, err)
+
+	var body map[string]interface{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", body["trace_id"])
+}
+
+func TestErrorCodes(t *testing.T) {
+	tests := []struct {
+		name         string
+		handler      fiber.Handler
+		expectedCode string
+	}{
+		{
+			name: "explicit code",
+			handler: func(c *fiber.Ctx) error {
+				return ErrorCode(c, fiber.StatusNotFound, CodeUserNotFound, "user not found")
+			},
+			expectedCode: CodeUserNotFound,
+		},
+		{
+			name: "derived from status",
+			handler: func(c *fiber.Ctx) error {
+				return NotFound(c, "Route not found")
+			},
+			expectedCode: "NOT_FOUND",
+		},
+		{
+			name: "multi-word status",
+			handler: func(c *fiber.Ctx) error {
+				return InternalServerError(c, "Failed")
+			},
+			expectedCode: "INTERNAL_SERVER_ERROR",
+		},
+		{
+			name: "validation errors",
+			handler: func(c *fiber.Ctx) error {
+				return ValidationError(c, []string{"name is required"})
+			},
+			expectedCode: CodeValidationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", tt.handler)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			assert.NoError(t, err)
+
+			var body Response
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, tt.expectedCode, body.Code)
+			assert.NotEmpty(t, body.Error, "the human-readable message is kept")
+		})
+	}
+}
+
+func TestPaginatedWithLinks(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		total int64
+		self  string
+		first string
+		last  string
+		next  interface{}
+		prev  interface{}
+	}{
+		{
+			name:  "first page has no prev",
+			query: "?page=1&per_page=10&role=admin",
+			total: 25,
+			self:  "/users?page=1&per_page=10&role=admin",
+			first: "/users?page=1&per_page=10&role=admin",
+			last:  "/users?page=3&per_page=10&role=admin",
+			next:  "/users?page=2&per_page=10&role=admin",
+			prev:  nil,
+		},
+		{
+			name:  "last page has no next",
+			query: "?role=admin&page=3&per_page=10",
+			total: 25,
+			self:  "/users?role=admin&page=3&per_page=10",
+			first: "/users?role=admin&page=1&per_page=10",
+			last:  "/users?role=admin&page=3&per_page=10",
+			next:  nil,
+			prev:  "/users?role=admin&page=2&per_page=10",
+		},
+		{
+			name:  "empty list has neither",
+			query: "",
+			total: 0,
+			self:  "/users?page=1&per_page=10",
+			first: "/users?page=1&per_page=10",
+			last:  "/users?page=1&per_page=10",
+			next:  nil,
+			prev:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/users", func(c *fiber.Ctx) error {
+				page := c.QueryInt("page", 1)
+				return PaginatedWithLinks(c, []string{}, tt.total, page, 10)
+			})
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/users"+tt.query, nil))
+			assert.NoError(t, err)
+
+			var body struct {
+				Data struct {
+					Links map[string]interface{} `json:"links"`
+				} `json:"data"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+			links := body.Data.Links
+			assert.Equal(t, tt.self, links["self"])
+			assert.Equal(t, tt.first, links["first"])
+			assert.Equal(t, tt.last, links["last"])
+			assert.Contains(t, links, "next", "boundaries are null, not omitted")
+			assert.Equal(t, tt.next, links["next"])
+			assert.Contains(t, links, "prev")
+			assert.Equal(t, tt.prev, links["prev"])
+		})
+	}
+}
+
+func TestPaginated_OmitsLinks(t *testing.T) {
+	app := fiber.New()
+	app.Get("/users", func(c *fiber.Ctx) error {
+		return Paginated(c, []string{}, 25, 1, 10)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users", nil))
+	assert.NoError(t, err)
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.NotContains(t, body.Data, "links")
+}
+
+func TestPaginated_Headers(t *testing.T) {
+	tests := []struct {
+		name     string
+		paginate func(c *fiber.Ctx) error
+	}{
+		{name: "Paginated", paginate: func(c *fiber.Ctx) error { return Paginated(c, []string{"a", "b", "c"}, 23, 2, 10) }},
+		{name: "PaginatedWithLinks", paginate: func(c *fiber.Ctx) error { return PaginatedWithLinks(c, []string{"a", "b", "c"}, 23, 2, 10) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/users", tt.paginate)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/users", nil))
+			assert.NoError(t, err)
+
+			var body struct {
+				Data PaginatedData `json:"data"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, 3, body.Data.TotalPages)
+
+			assert.Equal(t, strconv.FormatInt(body.Data.Total, 10), resp.Header.Get(HeaderTotalCount))
+			assert.Equal(t, strconv.Itoa(body.Data.Page), resp.Header.Get(HeaderPage))
+			assert.Equal(t, strconv.Itoa(body.Data.PerPage), resp.Header.Get(HeaderPerPage))
+			assert.Equal(t, strconv.Itoa(body.Data.TotalPages), resp.Header.Get(HeaderTotalPages))
+		})
+	}
+}
+
+func TestPaginated_TotalPages(t *testing.T) {
+	tests := []struct {
+		name       string
+		total      int64
+		perPage    int
+		totalPages int
+	}{
+		{name: "partial last page", total: 25, perPage: 10, totalPages: 3},
+		{name: "exact pages", total: 20, perPage: 10, totalPages: 2},
+		{name: "empty list", total: 0, perPage: 10, totalPages: 0},
+		{name: "zero per page", total: 25, perPage: 0, totalPages: 0},
+		{name: "negative per page", total: 25, perPage: -5, totalPages: 0},
+		{name: "all zero", total: 0, perPage: 0, totalPages: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				return Paginated(c, []string{}, tt.total, 1, tt.perPage)
+			})
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+			var body struct {
+				Data PaginatedData `json:"data"`
+			}
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, tt.totalPages, body.Data.TotalPages)
+			assert.Equal(t, tt.total, body.Data.Total)
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,47 @@
+package validator
+
+import "strings"
+
+const fallbackMessage = "{field} is invalid"
+
+// defaultMessages is the built-in English catalog. {field} is replaced with
+// the JSON field name and {param} with the tag parameter; for strongpassword,
+// {param} is the active PasswordPolicy's requirements, and for oneof, the
+// allowed values separated by commas.
+var defaultMessages = map[string]string{
+	"required":       "{field} is required",
+	"email":          "{field} must be a valid email",
+	"min":            "{field} must be at least {param} characters",
+	"max":            "{field} must be at most {param} characters",
+	"eqfield":        "{field} must match {param}",
+	"uuid":           "{field} must be a valid UUID",
+	"uuid4":          "{field} must be a valid UUID",
+	"numeric":        "{field} must be numeric",
+	"number":         "{field} must be numeric",
+	"oneof":          "{field} must be one of {param}",
+	"strongpassword": "{field} must {param}",
+}
+
+var messages = defaultMessages
+
+// SetMessages overrides catalog entries by tag, e.g. to translate or reword
+// validation errors. Tags not in overrides keep their default message, and
+// calling it with nil restores the defaults. Call it during startup.
+func SetMessages(overrides map[string]string) {
+	catalog := make(map[string]string, len(defaultMessages)+len(overrides))
+	for tag, msg := range defaultMessages {
+		catalog[tag] = msg
+	}
+	for tag, msg := range overrides {
+		catalog[tag] = msg
+	}
+	messages = catalog
+}
+
+func formatMessage(tag, field, param string) string {
+	msg, ok := messages[tag]
+	if !ok {
+		msg = fallbackMessage
+	}
+	return strings.NewReplacer("{field}", field, "{param}", param).Replace(msg)
+}
\ No newline at end of file
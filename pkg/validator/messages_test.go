@@ -0,0 +1,50 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetMessages_OverridesDefault(t *testing.T) {
+	Init()
+	t.Cleanup(func() { SetMessages(nil) })
+
+	SetMessages(map[string]string{"required": "{field} est obligatoire"})
+
+	errors := Validate(&TestInput{Name: "", Email: "invalid"})
+
+	assert.Len(t, errors, 2)
+	assert.Equal(t, "name est obligatoire", errors[0].Message)
+	assert.Equal(t, "email must be a valid email", errors[1].Message, "tags without an override keep the default")
+}
+
+func TestSetMessages_SubstitutesParam(t *testing.T) {
+	Init()
+	t.Cleanup(func() { SetMessages(nil) })
+
+	SetMessages(map[string]string{"min": "{field} needs {param}+ chars"})
+
+	errors := Validate(&TestInput{Name: "J", Email: "john@example.com"})
+
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "name needs 2+ chars", errors[0].Message)
+}
+
+func TestSetMessages_NilRestoresDefaults(t *testing.T) {
+	Init()
+
+	SetMessages(map[string]string{"required": "custom"})
+	SetMessages(nil)
+
+	errors := Validate(&TestInput{Name: "", Email: "john@example.com"})
+
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "name is required", errors[0].Message)
+}
+
+func TestFormatMessage_UnknownTagFallsBack(t *testing.T) {
+	SetMessages(nil)
+
+	assert.Equal(t, "name is invalid", formatMessage("alphanum", "name", ""))
+}
\ No newline at end of file
@@ -0,0 +1,102 @@
+package validator
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// PasswordPolicy controls what the strongpassword tag accepts.
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// DefaultPasswordPolicy requires at least 8 characters with one upper, one
+// lower, one digit and one symbol.
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:     8,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+}
+
+var passwordPolicy = DefaultPasswordPolicy()
+
+// SetPasswordPolicy replaces the policy used by the strongpassword tag. Call
+// it during startup, before requests are validated.
+func SetPasswordPolicy(policy PasswordPolicy) {
+	passwordPolicy = policy
+}
+
+// Allows reports whether password satisfies the policy.
+func (p PasswordPolicy) Allows(password string) bool {
+	if len([]rune(password)) < p.MinLength {
+		return false
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	return (upper || !p.RequireUpper) &&
+		(lower || !p.RequireLower) &&
+		(digit || !p.RequireDigit) &&
+		(symbol || !p.RequireSymbol)
+}
+
+// describe renders the policy for validation messages, e.g. "be at least 8
+// characters and contain an uppercase letter, a digit and a symbol".
+func (p PasswordPolicy) describe() string {
+	var classes []string
+	if p.RequireUpper {
+		classes = append(classes, "an uppercase letter")
+	}
+	if p.RequireLower {
+		classes = append(classes, "a lowercase letter")
+	}
+	if p.RequireDigit {
+		classes = append(classes, "a digit")
+	}
+	if p.RequireSymbol {
+		classes = append(classes, "a symbol")
+	}
+
+	var parts []string
+	if p.MinLength > 0 {
+		parts = append(parts, "be at least "+strconv.Itoa(p.MinLength)+" characters")
+	}
+	if len(classes) > 0 {
+		list := classes[len(classes)-1]
+		if len(classes) > 1 {
+			list = strings.Join(classes[:len(classes)-1], ", ") + " and " + list
+		}
+		parts = append(parts, "contain "+list)
+	}
+	if len(parts) == 0 {
+		return "be a valid password"
+	}
+	return strings.Join(parts, " and ")
+}
+
+func validateStrongPassword(fl validator.FieldLevel) bool {
+	return passwordPolicy.Allows(fl.Field().String())
+}
\ No newline at end of file
@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type passwordInput struct {
+	Password string `json:"password" validate:"required,strongpassword"`
+}
+
+func TestValidate_StrongPassword(t *testing.T) {
+	Init()
+
+	tests := []struct {
+		name     string
+		password string
+		valid    bool
+	}{
+		{name: "meets every rule", password: "Sup3r-secret", valid: true},
+		{name: "too short", password: "Ab1!xyz", valid: false},
+		{name: "missing uppercase", password: "sup3r-secret", valid: false},
+		{name: "missing lowercase", password: "SUP3R-SECRET", valid: false},
+		{name: "missing digit", password: "Super-secret", valid: false},
+		{name: "missing symbol", password: "Sup3rsecret", valid: false},
+		{name: "digits only", password: "12345678", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := Validate(&passwordInput{Password: tt.password})
+
+			if tt.valid {
+				assert.Empty(t, errors)
+				return
+			}
+			assert.Len(t, errors, 1)
+			assert.Equal(t, "password", errors[0].Field)
+			assert.Equal(t, "strongpassword", errors[0].Tag)
+			assert.Equal(t, "password must be at least 8 characters and contain an uppercase letter, a lowercase letter, a digit and a symbol", errors[0].Message)
+		})
+	}
+}
+
+func TestSetPasswordPolicy(t *testing.T) {
+	Init()
+	t.Cleanup(func() { SetPasswordPolicy(DefaultPasswordPolicy()) })
+
+	SetPasswordPolicy(PasswordPolicy{MinLength: 12, RequireDigit: true})
+
+	assert.Empty(t, Validate(&passwordInput{Password: "longlowercase1"}))
+
+	errors := Validate(&passwordInput{Password: "Short-Pass1"})
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "password must be at least 12 characters and contain a digit", errors[0].Message)
+}
\ No newline at end of file
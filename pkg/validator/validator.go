@@ -25,6 +25,8 @@ func Init() {
 		}
 		return name
 	})
+
+	validate.RegisterValidation("strongpassword", validateStrongPassword)
 }
 
 func Get() *validator.Validate {
@@ -50,19 +52,63 @@ func Validate(data interface{}) []ErrorResponse {
 	return errors
 }
 
+// ValidateVar validates a single value against tag, e.g. a path parameter
+// against "uuid". Errors are reported for the field name "value".
+func ValidateVar(value interface{}, tag string) []ErrorResponse {
+	var errors []ErrorResponse
+
+	if err := Get().Var(value, tag); err != nil {
+		for _, err := range err.(validator.ValidationErrors) {
+			errors = append(errors, ErrorResponse{
+				Field:   "value",
+				Tag:     err.Tag(),
+				Message: generateMessage(err),
+			})
+		}
+	}
+
+	return errors
+}
+
+// UnknownField reports a field the input doesn't declare, in the same shape
+// as the errors from Validate.
+func UnknownField(field string) ErrorResponse {
+	return ErrorResponse{Field: field, Tag: "unknown", Message: field + " is not allowed"}
+}
+
+// ValidateToMap validates data and groups the messages by field name, the
+// shape form libraries usually bind to. It returns nil when data is valid.
+func ValidateToMap(data interface{}) map[string][]string {
+	return GroupByField(Validate(data))
+}
+
+// GroupByField converts errors from Validate or ValidateVar into a
+// field -> messages map.
+func GroupByField(errs []ErrorResponse) map[string][]string {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	grouped := make(map[string][]string)
+	for _, err := range errs {
+		grouped[err.Field] = append(grouped[err.Field], err.Message)
+	}
+	return grouped
+}
+
 func generateMessage(err validator.FieldError) string {
+	field := err.Field()
+	if field == "" {
+		field = "value"
+	}
+
+	param := err.Param()
 	switch err.Tag() {
-	case "required":
-		return err.Field() + " is required"
-	case "email":
-		return err.Field() + " must be a valid email"
-	case "min":
-		return err.Field() + " must be at least " + err.Param() + " characters"
-	case "max":
-		return err.Field() + " must be at most " + err.Param() + " characters"
-	case "eqfield":
-		return err.Field() + " must match " + err.Param()
-	default:
-		return err.Field() + " is invalid"
+	case "strongpassword":
+		param = passwordPolicy.describe()
+	case "oneof":
+		param = strings.Join(strings.Fields(param), ", ")
 	}
+
+	return formatMessage(err.Tag(), field, param)
 }
\ No newline at end of file
@@ -69,6 +69,23 @@ func TestValidate_MinLength(t *testing.T) {
 	assert.Equal(t, "min", errors[0].Tag)
 }
 
+func TestValidate_Role(t *testing.T) {
+	Init()
+
+	type roleInput struct {
+		Role string `json:"role" validate:"required,oneof=user admin"`
+	}
+
+	assert.Empty(t, Validate(&roleInput{Role: "admin"}))
+
+	errors := Validate(&roleInput{Role: "superuser"})
+
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "role", errors[0].Field)
+	assert.Equal(t, "oneof", errors[0].Tag)
+	assert.Equal(t, "role must be one of user, admin", errors[0].Message)
+}
+
 func TestValidate_MultipleErrors(t *testing.T) {
 	Init()
 
@@ -80,4 +97,65 @@ func TestValidate_MultipleErrors(t *testing.T) {
 	errors := Validate(&input)
 
 	assert.Len(t, errors, 2)
+}
+
+func TestValidateVar(t *testing.T) {
+	Init()
+
+	tests := []struct {
+		name            string
+		value           interface{}
+		tag             string
+		expectedTag     string
+		expectedMessage string
+	}{
+		{name: "valid uuid", value: "0b5e0d3c-6f4a-4b8e-9a4f-2d1c3e5f7a9b", tag: "uuid"},
+		{name: "invalid uuid", value: "not-a-uuid", tag: "uuid", expectedTag: "uuid", expectedMessage: "value must be a valid UUID"},
+		{name: "valid email", value: "john@example.com", tag: "required,email"},
+		{name: "invalid email", value: "john@", tag: "required,email", expectedTag: "email", expectedMessage: "value must be a valid email"},
+		{name: "missing required", value: "", tag: "required,email", expectedTag: "required", expectedMessage: "value is required"},
+		{name: "valid numeric", value: "12345", tag: "numeric"},
+		{name: "invalid numeric", value: "12a45", tag: "numeric", expectedTag: "numeric", expectedMessage: "value must be numeric"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := ValidateVar(tt.value, tt.tag)
+
+			if tt.expectedTag == "" {
+				assert.Empty(t, errors)
+				return
+			}
+			assert.Len(t, errors, 1)
+			assert.Equal(t, "value", errors[0].Field)
+			assert.Equal(t, tt.expectedTag, errors[0].Tag)
+			assert.Equal(t, tt.expectedMessage, errors[0].Message)
+		})
+	}
+}
+
+func TestValidateToMap(t *testing.T) {
+	Init()
+
+	assert.Nil(t, ValidateToMap(&TestInput{Name: "John", Email: "john@example.com"}))
+
+	errors := ValidateToMap(&TestInput{Name: "", Email: "invalid"})
+
+	assert.Equal(t, map[string][]string{
+		"name":  {"name is required"},
+		"email": {"email must be a valid email"},
+	}, errors)
+}
+
+func TestGroupByField_GroupsSameField(t *testing.T) {
+	errors := GroupByField([]ErrorResponse{
+		{Field: "password", Tag: "min", Message: "password must be at least 8 characters"},
+		{Field: "email", Tag: "email", Message: "email must be a valid email"},
+		{Field: "password", Tag: "strongpassword", Message: "password must contain a digit"},
+	})
+
+	assert.Equal(t, map[string][]string{
+		"password": {"password must be at least 8 characters", "password must contain a digit"},
+		"email":    {"email must be a valid email"},
+	}, errors)
 }
\ No newline at end of file